@@ -2,6 +2,7 @@ package config
 
 import (
 	"app/src/utils"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -10,6 +11,7 @@ var (
 	IsProd              bool
 	AppHost             string
 	AppPort             int
+	AppURL              string
 	DBHost              string
 	DBUser              string
 	DBPassword          string
@@ -28,6 +30,17 @@ var (
 	GoogleClientID      string
 	GoogleClientSecret  string
 	RedirectURL         string
+	TrashRetentionDays  int
+	StorageDriver       string
+	StorageLocalPath    string
+	S3Endpoint          string
+	S3Region            string
+	S3Bucket            string
+	S3AccessKey         string
+	S3SecretKey         string
+	S3UseSSL            bool
+	AttachmentMaxSize   int
+	AttachmentTypes     []string
 )
 
 func init() {
@@ -37,6 +50,7 @@ func init() {
 	IsProd = viper.GetString("APP_ENV") == "prod"
 	AppHost = viper.GetString("APP_HOST")
 	AppPort = viper.GetInt("APP_PORT")
+	AppURL = strings.TrimSuffix(viper.GetString("APP_URL"), "/")
 
 	// database configuration
 	DBHost = viper.GetString("DB_HOST")
@@ -63,6 +77,32 @@ func init() {
 	GoogleClientID = viper.GetString("GOOGLE_CLIENT_ID")
 	GoogleClientSecret = viper.GetString("GOOGLE_CLIENT_SECRET")
 	RedirectURL = viper.GetString("REDIRECT_URL")
+
+	// trash configuration
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	TrashRetentionDays = viper.GetInt("TRASH_RETENTION_DAYS")
+
+	// storage configuration
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_PATH", "./uploads")
+	StorageDriver = viper.GetString("STORAGE_DRIVER")
+	StorageLocalPath = viper.GetString("STORAGE_LOCAL_PATH")
+	S3Endpoint = viper.GetString("S3_ENDPOINT")
+	S3Region = viper.GetString("S3_REGION")
+	S3Bucket = viper.GetString("S3_BUCKET")
+	S3AccessKey = viper.GetString("S3_ACCESS_KEY")
+	S3SecretKey = viper.GetString("S3_SECRET_KEY")
+	S3UseSSL = viper.GetBool("S3_USE_SSL")
+
+	// attachment configuration
+	viper.SetDefault("ATTACHMENT_MAX_SIZE_MB", 25)
+	viper.SetDefault("ATTACHMENT_ALLOWED_TYPES",
+		"image/*,video/*,audio/*,text/plain,text/csv,application/pdf,application/zip,"+
+			"application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,"+
+			"application/vnd.ms-excel,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,"+
+			"application/vnd.ms-powerpoint,application/vnd.openxmlformats-officedocument.presentationml.presentation")
+	AttachmentMaxSize = viper.GetInt("ATTACHMENT_MAX_SIZE_MB") * 1024 * 1024
+	AttachmentTypes = strings.Split(viper.GetString("ATTACHMENT_ALLOWED_TYPES"), ",")
 }
 
 func loadConfig() {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"app/src/utils"
+	"slices"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
@@ -16,5 +17,9 @@ func FiberConfig() fiber.Config {
 		ErrorHandler:  utils.ErrorHandler,
 		JSONEncoder:   sonic.Marshal,
 		JSONDecoder:   sonic.Unmarshal,
+		// Запас сверх лимита вложения на заголовки multipart
+		BodyLimit: AttachmentMaxSize + 1024*1024,
+		// Методы WebDAV для CalDAV-сервера
+		RequestMethods: append(slices.Clone(fiber.DefaultMethods), "PROPFIND", "REPORT"),
 	}
 }
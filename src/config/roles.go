@@ -3,7 +3,7 @@ package config
 
 var allRoles = map[string][]string{
 	"user":  {"comment","task_role_edit", },
-	"admin": {"getUsers", "manageUsers"},
+	"admin": {"getUsers", "manageUsers", "getAuditLogs"},
 }
 
 var Roles = getKeys(allRoles)
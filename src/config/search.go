@@ -0,0 +1,4 @@
+package config
+
+// Конфигурация полнотекстового поиска Postgres. Используется и в индексах, и в запросах
+const SearchConfig = "simple"
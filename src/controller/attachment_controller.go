@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AttachmentController struct {
+	AttachmentService service.AttachmentService
+}
+
+func NewAttachmentController(attachmentService service.AttachmentService) *AttachmentController {
+	return &AttachmentController{
+		AttachmentService: attachmentService,
+	}
+}
+
+// Upload attachment.
+// @Summary Upload attachment to task
+// @Description The file type is detected by content and must be in ATTACHMENT_ALLOWED_TYPES, the size is limited by ATTACHMENT_MAX_SIZE_MB.
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param file formData file true "File"
+// @Param comment_id formData string false "Comment of the same task to link the file to"
+// @Success 201 {object} response.SuccessWithData[model.Attachment]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 415 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/attachments [post]
+func (ac *AttachmentController) UploadAttachment(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "File is required")
+	}
+	var commentID *uuid.UUID
+	if value := c.FormValue("comment_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+		}
+		commentID = &id
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+	attachment, err := ac.AttachmentService.UploadAttachment(c, taskID, file, commentID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.Attachment]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Attachment uploaded successfully",
+		Data:    *attachment,
+	})
+}
+
+// Get task attachments.
+// @Summary Get task attachments
+// @Tags Attachments
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.SuccessWithData[[]model.Attachment]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/attachments [get]
+func (ac *AttachmentController) GetTaskAttachments(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	attachments, err := ac.AttachmentService.GetTaskAttachments(c, taskID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.Attachment]{
+		Code:    200,
+		Status:  "success",
+		Message: "Attachments retrieved successfully",
+		Data:    attachments,
+	})
+}
+
+// Download attachment.
+// @Summary Download attachment
+// @Description Streams the file. Available to everyone with access to the task.
+// @Tags Attachments
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /attachments/{attachmentID}/download [get]
+func (ac *AttachmentController) DownloadAttachment(c *fiber.Ctx) error {
+	attachmentID, err := uuid.Parse(c.Params("attachmentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	attachment, body, err := ac.AttachmentService.DownloadAttachment(c, attachmentID, user.ID)
+	if err != nil {
+		return err
+	}
+	c.Attachment(attachment.Name)
+	c.Set(fiber.HeaderContentType, attachment.Type)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	// fasthttp закрывает поток после отправки
+	return c.SendStream(body, attachment.Size)
+}
+
+// Get attachment thumbnail.
+// @Summary Get attachment thumbnail
+// @Description Thumbnails are generated in the background for images and PDFs. Sizes: small (64px), medium (256px), large (1024px) on the longer side.
+// @Tags Attachments
+// @Produce image/jpeg
+// @Produce image/png
+// @Security BearerAuth
+// @Param attachmentID path string true "Attachment ID"
+// @Param size query string false "Thumbnail size" Enums(small, medium, large) default(medium)
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /attachments/{attachmentID}/thumbnail [get]
+func (ac *AttachmentController) GetThumbnail(c *fiber.Ctx) error {
+	attachmentID, err := uuid.Parse(c.Params("attachmentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	thumbnail, body, err := ac.AttachmentService.GetThumbnail(c, attachmentID, c.Query("size"), user.ID)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, thumbnail.Type)
+	// Миниатюра не меняется, пока существует вложение
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	return c.SendStream(body, thumbnail.Bytes)
+}
+
+// Delete attachment.
+// @Summary Delete attachment
+// @Description Only the uploader can delete an attachment.
+// @Tags Attachments
+// @Security BearerAuth
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {object} response.Common
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /attachments/{attachmentID} [delete]
+func (ac *AttachmentController) DeleteAttachment(c *fiber.Ctx) error {
+	attachmentID, err := uuid.Parse(c.Params("attachmentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := ac.AttachmentService.DeleteAttachment(c, attachmentID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Attachment deleted successfully",
+	})
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditController struct {
+	AuditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) *AuditController {
+	return &AuditController{
+		AuditService: auditService,
+	}
+}
+
+// Get audit logs.
+// @Summary Get audit logs
+// @Description Security audit trail, newest first. With format=ndjson all matching entries are streamed oldest first, one JSON object per line, without pagination.
+// @Tags Audit
+// @Produce json
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param actor_id query string false "Actor user ID"
+// @Param action query string false "Actions, comma separated, e.g. auth.login_failed,user.role_changed"
+// @Param entity_type query string false "Entity type: user, user_group, project, task"
+// @Param entity_id query string false "Entity ID"
+// @Param ip query string false "Client IP"
+// @Param from query string false "Created from (YYYY-MM-DD)"
+// @Param to query string false "Created to, inclusive (YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of entries" default(50)
+// @Param cursor query string false "Cursor from the previous page"
+// @Param format query string false "json (default) or ndjson"
+// @Success 200 {object} response.SuccessWithCursor[model.AuditLog]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /audit-logs [get]
+func (ac *AuditController) GetAuditLogs(c *fiber.Ctx) error {
+	query := &validation.QueryAuditLog{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		IP:         c.Query("ip"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		Limit:      c.QueryInt("limit", 50),
+		Cursor:     c.Query("cursor"),
+		Format:     c.Query("format"),
+	}
+
+	if query.Format == "ndjson" {
+		stream, err := ac.AuditService.ExportAuditLogs(c, query)
+		if err != nil {
+			return err
+		}
+		filename := fmt.Sprintf("audit-logs-%s.ndjson", time.Now().Format("20060102-150405"))
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Context().SetBodyStreamWriter(stream)
+		return nil
+	}
+
+	logs, nextCursor, err := ac.AuditService.GetAuditLogs(c, query)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithCursor[model.AuditLog]{
+		Code:       200,
+		Status:     "success",
+		Message:    "Audit logs retrieved successfully",
+		Results:    logs,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	})
+}
@@ -0,0 +1,424 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"encoding/xml"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CalDAVController struct {
+	CalDAVService service.CalDAVService
+}
+
+func NewCalDAVController(caldavService service.CalDAVService) *CalDAVController {
+	return &CalDAVController{
+		CalDAVService: caldavService,
+	}
+}
+
+// Адреса ресурсов CalDAV: корень, принципал пользователя, домашняя коллекция с календарями проектов
+const (
+	caldavRoot      = "/v1/caldav/"
+	caldavPrincipal = caldavRoot + "principal/"
+	caldavHome      = caldavRoot + "projects/"
+)
+
+const todoContentType = "text/calendar; charset=utf-8; component=VTODO"
+
+func calendarHref(projectID uuid.UUID) string {
+	return caldavHome + projectID.String() + "/"
+}
+
+func todoHref(projectID, taskID uuid.UUID) string {
+	return calendarHref(projectID) + taskID.String() + ".ics"
+}
+
+func davName(namespace, local string) xml.Name {
+	return xml.Name{Space: namespace, Local: local}
+}
+
+func davHref(href string) string {
+	return utils.DAVElement(utils.DAVNamespace, "href", utils.DAVText(href))
+}
+
+type davResource struct {
+	href  string
+	props []utils.DAVProp
+}
+
+// multistatus отвечает 207 со свойствами ресурсов. Запрошенные, но неизвестные свойства
+// возвращаются со статусом 404, как требует RFC 4918, notFound - адреса отсутствующих ресурсов
+func multistatus(c *fiber.Ctx, req *utils.DAVRequest, resources []davResource, notFound ...string) error {
+	ms := utils.NewDAVMultistatus()
+	for _, resource := range resources {
+		if req.AllProp {
+			ms.Response(resource.href, resource.props, nil)
+			continue
+		}
+		var found []utils.DAVProp
+		var missing []xml.Name
+		for _, name := range req.Props {
+			i := slices.IndexFunc(resource.props, func(prop utils.DAVProp) bool { return prop.Name == name })
+			if i >= 0 {
+				found = append(found, resource.props[i])
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		ms.Response(resource.href, found, missing)
+	}
+	for _, href := range notFound {
+		ms.Status(href, fiber.StatusNotFound)
+	}
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Status(fiber.StatusMultiStatus).Send(ms.Bytes())
+}
+
+func parseDAVRequest(c *fiber.Ctx) (*utils.DAVRequest, error) {
+	req, err := utils.ParseDAVRequest(c.Body())
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return req, nil
+}
+
+// withChildren - нужно ли отдавать содержимое коллекции. Depth: infinity не поддерживается
+// и, как и отсутствующий заголовок, обрабатывается как Depth: 1
+func withChildren(c *fiber.Ctx) bool {
+	return c.Get("Depth") != "0"
+}
+
+func principalProps(user *model.User, resourceType string) []utils.DAVProp {
+	return []utils.DAVProp{
+		{Name: davName(utils.DAVNamespace, "resourcetype"), Value: resourceType},
+		{Name: davName(utils.DAVNamespace, "displayname"), Value: utils.DAVText(user.Name)},
+		{Name: davName(utils.DAVNamespace, "current-user-principal"), Value: davHref(caldavPrincipal)},
+		{Name: davName(utils.DAVNamespace, "principal-URL"), Value: davHref(caldavPrincipal)},
+		{Name: davName(utils.CalDAVNamespace, "calendar-home-set"), Value: davHref(caldavHome)},
+		{Name: davName(utils.CalDAVNamespace, "calendar-user-address-set"), Value: davHref("mailto:" + user.Email)},
+	}
+}
+
+func calendarProps(calendar *response.CalDAVCalendar) []utils.DAVProp {
+	collection := utils.DAVElement(utils.DAVNamespace, "collection", "")
+	privilege := func(name string) string {
+		return utils.DAVElement(utils.DAVNamespace, "privilege", utils.DAVElement(utils.DAVNamespace, name, ""))
+	}
+	report := func(name string) string {
+		return utils.DAVElement(utils.DAVNamespace, "supported-report", utils.DAVElement(utils.DAVNamespace, "report",
+			utils.DAVElement(utils.CalDAVNamespace, name, "")))
+	}
+	return []utils.DAVProp{
+		{Name: davName(utils.DAVNamespace, "resourcetype"),
+			Value: collection + utils.DAVElement(utils.CalDAVNamespace, "calendar", "")},
+		{Name: davName(utils.DAVNamespace, "displayname"), Value: utils.DAVText(calendar.Title)},
+		{Name: davName(utils.DAVNamespace, "current-user-principal"), Value: davHref(caldavPrincipal)},
+		{Name: davName(utils.CalendarServerNamespace, "getctag"), Value: utils.DAVText(calendar.CTag)},
+		{Name: davName(utils.CalDAVNamespace, "supported-calendar-component-set"),
+			Value: `<C:comp name="VTODO"/>`},
+		{Name: davName(utils.DAVNamespace, "supported-report-set"),
+			Value: report("calendar-query") + report("calendar-multiget")},
+		// Создавать задачи через CalDAV нельзя, поэтому права bind нет
+		{Name: davName(utils.DAVNamespace, "current-user-privilege-set"),
+			Value: privilege("read") + privilege("write-content") + privilege("unbind")},
+	}
+}
+
+func todoProps(todo *response.CalDAVTodo, withData bool) []utils.DAVProp {
+	props := []utils.DAVProp{
+		{Name: davName(utils.DAVNamespace, "resourcetype")},
+		{Name: davName(utils.DAVNamespace, "getetag"), Value: utils.DAVText(todo.ETag)},
+		{Name: davName(utils.DAVNamespace, "getcontenttype"), Value: todoContentType},
+	}
+	if withData {
+		props = append(props, utils.DAVProp{
+			Name:  davName(utils.CalDAVNamespace, "calendar-data"),
+			Value: utils.DAVText(string(todo.Data)),
+		})
+	}
+	return props
+}
+
+// Options сообщает клиенту, что сервер поддерживает CalDAV
+func (cc *CalDAVController) Options(c *fiber.Ctx) error {
+	c.Set("DAV", "1, 3, calendar-access")
+	c.Set(fiber.HeaderAllow, "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// PropfindRoot - точка входа клиента, по ней он находит принципала и домашнюю коллекцию
+func (cc *CalDAVController) PropfindRoot(c *fiber.Ctx) error {
+	req, err := parseDAVRequest(c)
+	if err != nil {
+		return err
+	}
+	user, _ := c.Locals("user").(*model.User)
+	collection := utils.DAVElement(utils.DAVNamespace, "collection", "")
+	return multistatus(c, req, []davResource{{href: caldavRoot, props: principalProps(user, collection)}})
+}
+
+func (cc *CalDAVController) PropfindPrincipal(c *fiber.Ctx) error {
+	req, err := parseDAVRequest(c)
+	if err != nil {
+		return err
+	}
+	user, _ := c.Locals("user").(*model.User)
+	resourceType := utils.DAVElement(utils.DAVNamespace, "collection", "") +
+		utils.DAVElement(utils.DAVNamespace, "principal", "")
+	return multistatus(c, req, []davResource{{href: caldavPrincipal, props: principalProps(user, resourceType)}})
+}
+
+// PropfindHome возвращает домашнюю коллекцию и с Depth: 1 - календари проектов пользователя
+func (cc *CalDAVController) PropfindHome(c *fiber.Ctx) error {
+	req, err := parseDAVRequest(c)
+	if err != nil {
+		return err
+	}
+	user, _ := c.Locals("user").(*model.User)
+	resources := []davResource{{href: caldavHome, props: []utils.DAVProp{
+		{Name: davName(utils.DAVNamespace, "resourcetype"), Value: utils.DAVElement(utils.DAVNamespace, "collection", "")},
+		{Name: davName(utils.DAVNamespace, "displayname"), Value: "Projects"},
+		{Name: davName(utils.DAVNamespace, "current-user-principal"), Value: davHref(caldavPrincipal)},
+	}}}
+	if withChildren(c) {
+		calendars, err := cc.CalDAVService.GetCalendars(c, user.ID)
+		if err != nil {
+			return err
+		}
+		for i := range calendars {
+			resources = append(resources, davResource{
+				href:  calendarHref(calendars[i].ProjectID),
+				props: calendarProps(&calendars[i]),
+			})
+		}
+	}
+	return multistatus(c, req, resources)
+}
+
+// PropfindCalendar возвращает календарь проекта и с Depth: 1 - ETag всех его задач
+func (cc *CalDAVController) PropfindCalendar(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	req, err := parseDAVRequest(c)
+	if err != nil {
+		return err
+	}
+	user, _ := c.Locals("user").(*model.User)
+	calendar, err := cc.CalDAVService.GetCalendar(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	resources := []davResource{{href: calendarHref(projectID), props: calendarProps(calendar)}}
+	if withChildren(c) {
+		todos, err := cc.CalDAVService.GetTodos(c, projectID, user.ID, nil)
+		if err != nil {
+			return err
+		}
+		for i := range todos {
+			resources = append(resources, davResource{
+				href:  todoHref(projectID, todos[i].TaskID),
+				props: todoProps(&todos[i], false),
+			})
+		}
+	}
+	return multistatus(c, req, resources)
+}
+
+// Report обрабатывает calendar-query и calendar-multiget. Фильтры calendar-query
+// не применяются - в календаре есть только VTODO, и клиенту отдаются все задачи
+func (cc *CalDAVController) Report(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	req, err := parseDAVRequest(c)
+	if err != nil {
+		return err
+	}
+	user, _ := c.Locals("user").(*model.User)
+
+	var taskIDs []uuid.UUID
+	var unknown []string
+	switch req.Kind {
+	case "calendar-query":
+	case "calendar-multiget":
+		taskIDs = make([]uuid.UUID, 0, len(req.Hrefs))
+		for _, href := range req.Hrefs {
+			// Клиент может прислать полный URL или экранированный путь
+			resource, err := url.Parse(href)
+			if err != nil {
+				unknown = append(unknown, href)
+				continue
+			}
+			taskID, err := uuid.Parse(strings.TrimSuffix(path.Base(resource.Path), ".ics"))
+			if err != nil || path.Dir(resource.Path)+"/" != calendarHref(projectID) {
+				unknown = append(unknown, href)
+				continue
+			}
+			taskIDs = append(taskIDs, taskID)
+		}
+	default:
+		return fiber.NewError(fiber.StatusForbidden, "Unsupported report")
+	}
+
+	todos, err := cc.CalDAVService.GetTodos(c, projectID, user.ID, taskIDs)
+	if err != nil {
+		return err
+	}
+	resources := make([]davResource, len(todos))
+	found := make(map[uuid.UUID]bool, len(todos))
+	for i := range todos {
+		found[todos[i].TaskID] = true
+		resources[i] = davResource{
+			href:  todoHref(projectID, todos[i].TaskID),
+			props: todoProps(&todos[i], true),
+		}
+	}
+	for _, taskID := range taskIDs {
+		if !found[taskID] {
+			unknown = append(unknown, todoHref(projectID, taskID))
+		}
+	}
+	// Удалённые и чужие ресурсы из multiget отдаются со статусом 404
+	return multistatus(c, req, resources, unknown...)
+}
+
+func todoParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	return projectID, taskID, nil
+}
+
+func (cc *CalDAVController) todo(c *fiber.Ctx) (uuid.UUID, *response.CalDAVTodo, error) {
+	projectID, taskID, err := todoParams(c)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	user, _ := c.Locals("user").(*model.User)
+	todos, err := cc.CalDAVService.GetTodos(c, projectID, user.ID, []uuid.UUID{taskID})
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if len(todos) == 0 {
+		return uuid.Nil, nil, fiber.NewError(fiber.StatusNotFound, "Task not found")
+	}
+	return projectID, &todos[0], nil
+}
+
+func (cc *CalDAVController) PropfindTodo(c *fiber.Ctx) error {
+	req, err := parseDAVRequest(c)
+	if err != nil {
+		return err
+	}
+	projectID, todo, err := cc.todo(c)
+	if err != nil {
+		return err
+	}
+	return multistatus(c, req, []davResource{{
+		href:  todoHref(projectID, todo.TaskID),
+		props: todoProps(todo, false),
+	}})
+}
+
+// Get CalDAV todo.
+// @Summary Get task as VTODO
+// @Description CalDAV resource of a task. The ETag changes with every change of the task.
+// @Tags CalDAV
+// @Produce text/calendar
+// @Security BasicAuth
+// @Param projectID path string true "Project ID"
+// @Param taskID path string true "Task ID"
+// @Success 200 {string} string
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /caldav/projects/{projectID}/{taskID}.ics [get]
+func (cc *CalDAVController) GetTodo(c *fiber.Ctx) error {
+	_, todo, err := cc.todo(c)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderETag, todo.ETag)
+	if c.Get(fiber.HeaderIfNoneMatch) == todo.ETag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, todoContentType)
+	return c.Send(todo.Data)
+}
+
+// Put CalDAV todo.
+// @Summary Update task from VTODO
+// @Description Applies SUMMARY, DESCRIPTION, DUE and completion of the VTODO to an existing task. With If-Match the ETag must match the current version of the task. Creating tasks is not supported.
+// @Tags CalDAV
+// @Accept text/calendar
+// @Security BasicAuth
+// @Param projectID path string true "Project ID"
+// @Param taskID path string true "Task ID"
+// @Param If-Match header string false "ETag of the task"
+// @Param request body string true "iCalendar with a VTODO"
+// @Success 204 "Updated, the new ETag is in the ETag header"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
+// @Router /caldav/projects/{projectID}/{taskID}.ics [put]
+func (cc *CalDAVController) PutTodo(c *fiber.Ctx) error {
+	projectID, taskID, err := todoParams(c)
+	if err != nil {
+		return err
+	}
+	// If-None-Match: * клиент присылает только для нового ресурса
+	if c.Get(fiber.HeaderIfNoneMatch) == "*" {
+		return fiber.NewError(fiber.StatusForbidden, "Creating tasks over CalDAV is not supported")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	etag, err := cc.CalDAVService.PutTodo(c, projectID, taskID, user.ID, c.Body(), c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderETag, etag)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Delete CalDAV todo.
+// @Summary Delete task from CalDAV
+// @Description Moves the task to the trash.
+// @Tags CalDAV
+// @Security BasicAuth
+// @Param projectID path string true "Project ID"
+// @Param taskID path string true "Task ID"
+// @Param If-Match header string false "ETag of the task"
+// @Success 204 "Deleted"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
+// @Router /caldav/projects/{projectID}/{taskID}.ics [delete]
+func (cc *CalDAVController) DeleteTodo(c *fiber.Ctx) error {
+	projectID, taskID, err := todoParams(c)
+	if err != nil {
+		return err
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := cc.CalDAVService.DeleteTodo(c, projectID, taskID, user.ID, c.Get(fiber.HeaderIfMatch)); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
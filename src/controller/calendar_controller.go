@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CalendarController struct {
+	CalendarService service.CalendarService
+}
+
+func NewCalendarController(calendarService service.CalendarService) *CalendarController {
+	return &CalendarController{
+		CalendarService: calendarService,
+	}
+}
+
+// Get calendar feeds.
+// @Summary Get my calendar feeds
+// @Description Personal feed of assigned tasks and per-project feeds with their subscription URLs.
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessWithData[[]model.CalendarFeed]
+// @Router /calendar/feeds [get]
+func (cc *CalendarController) GetFeeds(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	feeds, err := cc.CalendarService.GetFeeds(c, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.CalendarFeed]{
+		Code:    200,
+		Status:  "success",
+		Message: "Calendar feeds retrieved successfully",
+		Data:    feeds,
+	})
+}
+
+// Create calendar feed.
+// @Summary Create calendar feed
+// @Description Without project_id the feed contains tasks assigned to the user. Returns the existing feed if there is one.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body validation.CreateCalendarFeed true "Feed scope"
+// @Success 200 {object} response.SuccessWithData[model.CalendarFeed]
+// @Success 201 {object} response.SuccessWithData[model.CalendarFeed]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /calendar/feeds [post]
+func (cc *CalendarController) CreateFeed(c *fiber.Ctx) error {
+	var req validation.CreateCalendarFeed
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	feed, created, err := cc.CalendarService.CreateFeed(c, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	code, message := fiber.StatusOK, "Calendar feed retrieved successfully"
+	if created {
+		code, message = fiber.StatusCreated, "Calendar feed created successfully"
+	}
+	return c.Status(code).JSON(response.SuccessWithData[model.CalendarFeed]{
+		Code:    code,
+		Status:  "success",
+		Message: message,
+		Data:    *feed,
+	})
+}
+
+// Regenerate calendar feed token.
+// @Summary Regenerate calendar feed token
+// @Description Issues a new feed URL, the old one stops working immediately.
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Param feedID path string true "Feed ID"
+// @Success 200 {object} response.SuccessWithData[model.CalendarFeed]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /calendar/feeds/{feedID}/regenerate [post]
+func (cc *CalendarController) RegenerateToken(c *fiber.Ctx) error {
+	feedID, err := uuid.Parse(c.Params("feedID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid feed ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	feed, err := cc.CalendarService.RegenerateToken(c, feedID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.CalendarFeed]{
+		Code:    200,
+		Status:  "success",
+		Message: "Calendar feed token regenerated successfully",
+		Data:    *feed,
+	})
+}
+
+// Delete calendar feed.
+// @Summary Delete calendar feed
+// @Tags Calendar
+// @Security BearerAuth
+// @Param feedID path string true "Feed ID"
+// @Success 200 {object} response.Common
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /calendar/feeds/{feedID} [delete]
+func (cc *CalendarController) DeleteFeed(c *fiber.Ctx) error {
+	feedID, err := uuid.Parse(c.Params("feedID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid feed ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := cc.CalendarService.DeleteFeed(c, feedID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Calendar feed deleted successfully",
+	})
+}
+
+// Get calendar feed.
+// @Summary Get iCalendar feed
+// @Description Read-only feed of task due dates authorized by the token in the URL. Tasks are VEVENT entries, with type=todo they are VTODO.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Param type query string false "Component type" Enums(event, todo) default(event)
+// @Success 200 {string} string
+// @Failure 404 {object} response.ErrorResponse
+// @Router /calendar/{token}.ics [get]
+func (cc *CalendarController) GetFeed(c *fiber.Ctx) error {
+	calendar, err := cc.CalendarService.RenderFeed(c, c.Params("token"), c.Query("type") == "todo")
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="tasks.ics"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(calendar)
+}
@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CommentController struct {
+	CommentService service.CommentService
+}
+
+func NewCommentController(commentService service.CommentService) *CommentController {
+	return &CommentController{
+		CommentService: commentService,
+	}
+}
+
+// Comment task.
+// @Summary Comment task
+// @Description Reply and quote targets must be live comments of the same task.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security  BearerAuth
+// @Param request body validation.CreateComment true "Create comment"
+// @Success 201 {object} response.SuccessWithData[model.Comment]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /comments [post]
+func (cc *CommentController) CreateComment(c *fiber.Ctx) error {
+	var req validation.CreateComment
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	comment, err := cc.CommentService.CreateComment(c, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.Comment]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Comment send successfully",
+		Data:    *comment,
+	})
+}
+
+// Get task comments.
+// @Summary Get task comments
+// @Description Top-level comments in creation order, each with its nested replies. Deleted comments stay as tombstones.
+// @Tags Comments
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param limit query int false "Maximum number of threads" default(20)
+// @Param cursor query string false "Cursor from the previous page"
+// @Success 200 {object} response.SuccessWithCursor[model.Comment]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/comments [get]
+func (cc *CommentController) GetTaskComments(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	query := &validation.QueryComments{
+		Limit:  c.QueryInt("limit", 20),
+		Cursor: c.Query("cursor"),
+	}
+	user, _ := c.Locals("user").(*model.User)
+	comments, nextCursor, err := cc.CommentService.GetTaskComments(c, taskID, query, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithCursor[model.Comment]{
+		Code:       200,
+		Status:     "success",
+		Message:    "Comments retrieved successfully",
+		Results:    comments,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	})
+}
+
+// Update comment.
+// @Summary Edit comment
+// @Description Only the author can edit. The previous text is kept as a revision.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param commentID path string true "Comment ID"
+// @Param request body validation.UpdateComment true "New text"
+// @Success 200 {object} response.SuccessWithData[model.Comment]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /comments/{commentID} [put]
+func (cc *CommentController) UpdateComment(c *fiber.Ctx) error {
+	commentID, err := uuid.Parse(c.Params("commentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+	var req validation.UpdateComment
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	comment, err := cc.CommentService.UpdateComment(c, commentID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Comment]{
+		Code:    200,
+		Status:  "success",
+		Message: "Comment updated successfully",
+		Data:    *comment,
+	})
+}
+
+// Delete comment.
+// @Summary Delete comment
+// @Description Only the author can delete. The comment stays in the thread as a tombstone without text.
+// @Tags Comments
+// @Security BearerAuth
+// @Param commentID path string true "Comment ID"
+// @Success 200 {object} response.Common
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /comments/{commentID} [delete]
+func (cc *CommentController) DeleteComment(c *fiber.Ctx) error {
+	commentID, err := uuid.Parse(c.Params("commentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := cc.CommentService.DeleteComment(c, commentID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Comment deleted successfully",
+	})
+}
+
+// Get comment revisions.
+// @Summary Get comment revisions
+// @Description Previous versions of the comment text, newest first.
+// @Tags Comments
+// @Produce json
+// @Security BearerAuth
+// @Param commentID path string true "Comment ID"
+// @Success 200 {object} response.SuccessWithData[[]model.CommentRevision]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /comments/{commentID}/revisions [get]
+func (cc *CommentController) GetCommentRevisions(c *fiber.Ctx) error {
+	commentID, err := uuid.Parse(c.Params("commentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	revisions, err := cc.CommentService.GetCommentRevisions(c, commentID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.CommentRevision]{
+		Code:    200,
+		Status:  "success",
+		Message: "Comment revisions retrieved successfully",
+		Data:    revisions,
+	})
+}
+
+// Add reaction.
+// @Summary Add emoji reaction to comment
+// @Description Each user can react with each emoji once.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param commentID path string true "Comment ID"
+// @Param request body validation.CommentReaction true "Reaction"
+// @Success 201 {object} response.SuccessWithData[response.CommentReactions]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /comments/{commentID}/reactions [post]
+func (cc *CommentController) AddReaction(c *fiber.Ctx) error {
+	commentID, err := uuid.Parse(c.Params("commentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+	var req validation.CommentReaction
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	reactions, err := cc.CommentService.AddReaction(c, commentID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[response.CommentReactions]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Reaction added successfully",
+		Data:    *reactions,
+	})
+}
+
+// Remove reaction.
+// @Summary Remove emoji reaction from comment
+// @Tags Comments
+// @Produce json
+// @Security BearerAuth
+// @Param commentID path string true "Comment ID"
+// @Param emoji path string true "URL-encoded emoji"
+// @Success 200 {object} response.SuccessWithData[response.CommentReactions]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /comments/{commentID}/reactions/{emoji} [delete]
+func (cc *CommentController) RemoveReaction(c *fiber.Ctx) error {
+	commentID, err := uuid.Parse(c.Params("commentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+	emoji, err := url.PathUnescape(c.Params("emoji"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid emoji")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	reactions, err := cc.CommentService.RemoveReaction(c, commentID, emoji, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.CommentReactions]{
+		Code:    200,
+		Status:  "success",
+		Message: "Reaction removed successfully",
+		Data:    *reactions,
+	})
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CustomFieldController struct {
+	CustomFieldService service.CustomFieldService
+}
+
+func NewCustomFieldController(customFieldService service.CustomFieldService) *CustomFieldController {
+	return &CustomFieldController{
+		CustomFieldService: customFieldService,
+	}
+}
+
+// Get project custom fields.
+// @Summary Get project custom fields
+// @Tags Custom fields
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} response.SuccessWithData[[]model.CustomField]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/custom-fields [get]
+func (fc *CustomFieldController) GetCustomFields(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	fields, err := fc.CustomFieldService.GetCustomFields(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.CustomField]{
+		Code:    200,
+		Status:  "success",
+		Message: "Custom fields retrieved successfully",
+		Data:    fields,
+	})
+}
+
+// Create custom field.
+// @Summary Create project custom field
+// @Description Types: text, number, date (YYYY-MM-DD), select, multi_select, user (project member ID), checkbox. Select fields require options. Only the project owner or a manager can do this.
+// @Tags Custom fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Param request body validation.CreateCustomField true "Field definition"
+// @Success 201 {object} response.SuccessWithData[model.CustomField]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /projects/{projectID}/custom-fields [post]
+func (fc *CustomFieldController) CreateCustomField(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	var req validation.CreateCustomField
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	field, err := fc.CustomFieldService.CreateCustomField(c, projectID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.CustomField]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Custom field created successfully",
+		Data:    *field,
+	})
+}
+
+// Update custom field.
+// @Summary Update custom field
+// @Description Rename, reorder or change options of a field. The type cannot be changed, options used by tasks cannot be removed. Only the project owner or a manager can do this.
+// @Tags Custom fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param fieldID path string true "Custom field ID"
+// @Param request body validation.UpdateCustomField true "Changes"
+// @Success 200 {object} response.SuccessWithData[model.CustomField]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /custom-fields/{fieldID} [put]
+func (fc *CustomFieldController) UpdateCustomField(c *fiber.Ctx) error {
+	fieldID, err := uuid.Parse(c.Params("fieldID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid custom field ID")
+	}
+	var req validation.UpdateCustomField
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	field, err := fc.CustomFieldService.UpdateCustomField(c, fieldID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.CustomField]{
+		Code:    200,
+		Status:  "success",
+		Message: "Custom field updated successfully",
+		Data:    *field,
+	})
+}
+
+// Delete custom field.
+// @Summary Delete custom field
+// @Description Delete the field and its values in all tasks of the project. Only the project owner or a manager can do this.
+// @Tags Custom fields
+// @Security BearerAuth
+// @Param fieldID path string true "Custom field ID"
+// @Success 200 {object} response.Common
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /custom-fields/{fieldID} [delete]
+func (fc *CustomFieldController) DeleteCustomField(c *fiber.Ctx) error {
+	fieldID, err := uuid.Parse(c.Params("fieldID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid custom field ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := fc.CustomFieldService.DeleteCustomField(c, fieldID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Custom field deleted successfully",
+	})
+}
+
+// Set task custom field values.
+// @Summary Set custom field values of task
+// @Description Only the passed fields are changed, null clears a field.
+// @Tags Custom fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.SetCustomFieldValues true "Values by field ID"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/custom-fields [put]
+func (fc *CustomFieldController) SetTaskValues(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.SetCustomFieldValues
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := fc.CustomFieldService.SetTaskValues(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: "Custom field values updated successfully",
+		Data:    *task,
+	})
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type LabelController struct {
+	LabelService service.LabelService
+}
+
+func NewLabelController(labelService service.LabelService) *LabelController {
+	return &LabelController{
+		LabelService: labelService,
+	}
+}
+
+// Get project labels.
+// @Summary Get project labels
+// @Tags Labels
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} response.SuccessWithData[[]model.Label]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/labels [get]
+func (lc *LabelController) GetLabels(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	labels, err := lc.LabelService.GetLabels(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.Label]{
+		Code:    200,
+		Status:  "success",
+		Message: "Labels retrieved successfully",
+		Data:    labels,
+	})
+}
+
+// Create label.
+// @Summary Create project label
+// @Tags Labels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Param request body validation.CreateLabel true "Label"
+// @Success 201 {object} response.SuccessWithData[model.Label]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /projects/{projectID}/labels [post]
+func (lc *LabelController) CreateLabel(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	var req validation.CreateLabel
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	label, err := lc.LabelService.CreateLabel(c, projectID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.Label]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Label created successfully",
+		Data:    *label,
+	})
+}
+
+// Update label.
+// @Summary Rename or recolor label
+// @Description The change applies to every task of the project. Renaming to an existing name is rejected, merge the labels instead.
+// @Tags Labels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param labelID path string true "Label ID"
+// @Param request body validation.UpdateLabel true "Changes"
+// @Success 200 {object} response.SuccessWithData[model.Label]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /labels/{labelID} [put]
+func (lc *LabelController) UpdateLabel(c *fiber.Ctx) error {
+	labelID, err := uuid.Parse(c.Params("labelID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid label ID")
+	}
+	var req validation.UpdateLabel
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	label, err := lc.LabelService.UpdateLabel(c, labelID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Label]{
+		Code:    200,
+		Status:  "success",
+		Message: "Label updated successfully",
+		Data:    *label,
+	})
+}
+
+// Delete label.
+// @Summary Delete label
+// @Description Delete the label and remove it from all tasks.
+// @Tags Labels
+// @Security BearerAuth
+// @Param labelID path string true "Label ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /labels/{labelID} [delete]
+func (lc *LabelController) DeleteLabel(c *fiber.Ctx) error {
+	labelID, err := uuid.Parse(c.Params("labelID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid label ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := lc.LabelService.DeleteLabel(c, labelID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Label deleted successfully",
+	})
+}
+
+// Merge labels.
+// @Summary Merge label into another
+// @Description Move the label from all tasks to the target label of the same project and delete it.
+// @Tags Labels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param labelID path string true "Label ID"
+// @Param request body validation.MergeLabel true "Target label"
+// @Success 200 {object} response.SuccessWithData[model.Label]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /labels/{labelID}/merge [post]
+func (lc *LabelController) MergeLabel(c *fiber.Ctx) error {
+	labelID, err := uuid.Parse(c.Params("labelID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid label ID")
+	}
+	var req validation.MergeLabel
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	label, err := lc.LabelService.MergeLabel(c, labelID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Label]{
+		Code:    200,
+		Status:  "success",
+		Message: "Labels merged successfully",
+		Data:    *label,
+	})
+}
+
+// Add label to task.
+// @Summary Add label to task
+// @Tags Labels
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param labelID path string true "Label ID"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/labels/{labelID} [post]
+func (lc *LabelController) AddTaskLabel(c *fiber.Ctx) error {
+	return lc.changeTaskLabels(c, lc.LabelService.AddTaskLabel, "Label added successfully")
+}
+
+// Remove label from task.
+// @Summary Remove label from task
+// @Tags Labels
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param labelID path string true "Label ID"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/labels/{labelID} [delete]
+func (lc *LabelController) RemoveTaskLabel(c *fiber.Ctx) error {
+	return lc.changeTaskLabels(c, lc.LabelService.RemoveTaskLabel, "Label removed successfully")
+}
+
+func (lc *LabelController) changeTaskLabels(
+	c *fiber.Ctx, change func(c *fiber.Ctx, taskID, labelID, userID uuid.UUID) (*model.Task, error), message string,
+) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	labelID, err := uuid.Parse(c.Params("labelID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid label ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := change(c, taskID, labelID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: message,
+		Data:    *task,
+	})
+}
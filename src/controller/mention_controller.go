@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type MentionController struct {
+	MentionService service.MentionService
+}
+
+func NewMentionController(mentionService service.MentionService) *MentionController {
+	return &MentionController{
+		MentionService: mentionService,
+	}
+}
+
+// Get my mentions.
+// @Summary Get mentions of the current user
+// @Description Mentions in comments and task descriptions, newest first.
+// @Tags Mentions
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread mentions"
+// @Param limit query int false "Maximum number of mentions" default(20)
+// @Param cursor query string false "Cursor from the previous page"
+// @Success 200 {object} response.SuccessWithCursor[model.Mention]
+// @Failure 400 {object} response.ErrorResponse
+// @Router /me/mentions [get]
+func (mc *MentionController) GetMyMentions(c *fiber.Ctx) error {
+	query := &validation.QueryMentions{
+		Unread: c.QueryBool("unread"),
+		Limit:  c.QueryInt("limit", 20),
+		Cursor: c.Query("cursor"),
+	}
+	user, _ := c.Locals("user").(*model.User)
+	mentions, nextCursor, err := mc.MentionService.GetUserMentions(c, user.ID, query)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithCursor[model.Mention]{
+		Code:       200,
+		Status:     "success",
+		Message:    "Mentions retrieved successfully",
+		Results:    mentions,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	})
+}
+
+// Mark mention as read.
+// @Summary Mark mention as read
+// @Tags Mentions
+// @Security BearerAuth
+// @Param mentionID path string true "Mention ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /me/mentions/{mentionID}/read [put]
+func (mc *MentionController) MarkRead(c *fiber.Ctx) error {
+	mentionID, err := uuid.Parse(c.Params("mentionID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid mention ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := mc.MentionService.MarkRead(c, mentionID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Mention marked as read",
+	})
+}
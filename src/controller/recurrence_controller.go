@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RecurrenceController struct {
+	RecurrenceService service.RecurrenceService
+}
+
+func NewRecurrenceController(recurrenceService service.RecurrenceService) *RecurrenceController {
+	return &RecurrenceController{
+		RecurrenceService: recurrenceService,
+	}
+}
+
+// Get task recurrence.
+// @Summary Get task recurrence
+// @Description Retrieve the recurrence series of a task with its next occurrences.
+// @Tags Recurrence
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.SuccessWithData[response.Recurrence]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/recurrence [get]
+func (rc *RecurrenceController) GetRecurrence(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	recurrence, err := rc.RecurrenceService.GetRecurrence(c, taskID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Recurrence]{
+		Code:    200,
+		Status:  "success",
+		Message: "Recurrence retrieved successfully",
+		Data:    *recurrence,
+	})
+}
+
+// Set task recurrence.
+// @Summary Set task recurrence
+// @Description Make a task recurring with an RFC 5545 RRULE. For a task that is already part of a series the change applies to this and following occurrences.
+// @Tags Recurrence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.SetRecurrence true "Recurrence"
+// @Success 200 {object} response.SuccessWithData[response.Recurrence]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/recurrence [put]
+func (rc *RecurrenceController) SetRecurrence(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.SetRecurrence
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	recurrence, err := rc.RecurrenceService.SetRecurrence(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Recurrence]{
+		Code:    200,
+		Status:  "success",
+		Message: "Recurrence updated successfully",
+		Data:    *recurrence,
+	})
+}
+
+// Stop task recurrence.
+// @Summary Stop task recurrence
+// @Description End the series. Existing occurrences are kept.
+// @Tags Recurrence
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/recurrence [delete]
+func (rc *RecurrenceController) StopRecurrence(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := rc.RecurrenceService.StopRecurrence(c, taskID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Recurrence stopped successfully",
+	})
+}
+
+// Skip an occurrence.
+// @Summary Skip an occurrence
+// @Description Exclude the occurrence on the given date from the series.
+// @Tags Recurrence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.AddRecurrenceException true "Exception"
+// @Success 200 {object} response.SuccessWithData[response.Recurrence]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/recurrence/exceptions [post]
+func (rc *RecurrenceController) AddException(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.AddRecurrenceException
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	recurrence, err := rc.RecurrenceService.AddException(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Recurrence]{
+		Code:    200,
+		Status:  "success",
+		Message: "Occurrence skipped successfully",
+		Data:    *recurrence,
+	})
+}
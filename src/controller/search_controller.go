@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchController struct {
+	SearchService service.SearchService
+}
+
+func NewSearchController(searchService service.SearchService) *SearchController {
+	return &SearchController{
+		SearchService: searchService,
+	}
+}
+
+// @Tags         Search
+// @Summary      Full-text search
+// @Description  Search tasks, comments and projects available to the user. Results are ranked and contain highlighted snippets.
+// @Security BearerAuth
+// @Produce      json
+// @Param        q        query     string  true   "Search query"
+// @Param        types    query     string  false  "Entity types, comma separated: task,comment,project"
+// @Param        page     query     int     false  "Page number"  default(1)
+// @Param        limit    query     int     false  "Maximum number of results"    default(10)
+// @Router       /search [get]
+// @Success      200  {object}  response.SuccessWithPaginate[response.SearchResult]
+// @Failure      400  {object}  response.ErrorResponse
+func (sc *SearchController) Search(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	query := &validation.QuerySearch{
+		Query: c.Query("q"),
+		Types: c.Query("types"),
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", 10),
+	}
+
+	results, totalResults, err := sc.SearchService.Search(c, user.ID, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPaginate[response.SearchResult]{
+			Code:         fiber.StatusOK,
+			Status:       "success",
+			Message:      "Search completed successfully",
+			Results:      results,
+			Page:         query.Page,
+			Limit:        query.Limit,
+			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
+			TotalResults: totalResults,
+		})
+}
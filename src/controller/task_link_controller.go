@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TaskLinkController struct {
+	TaskLinkService service.TaskLinkService
+}
+
+func NewTaskLinkController(taskLinkService service.TaskLinkService) *TaskLinkController {
+	return &TaskLinkController{
+		TaskLinkService: taskLinkService,
+	}
+}
+
+// Get task links.
+// @Summary Get task links
+// @Description Retrieve links of a task and whether it is blocked by open tasks.
+// @Tags TaskLinks
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.SuccessWithData[response.TaskLinks]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/links [get]
+func (lc *TaskLinkController) GetLinks(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	links, err := lc.TaskLinkService.GetLinks(c, taskID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.TaskLinks]{
+		Code:    200,
+		Status:  "success",
+		Message: "Task links retrieved successfully",
+		Data:    *links,
+	})
+}
+
+// Create task link.
+// @Summary Link two tasks
+// @Description Create a blocks, blocked_by, relates_to or duplicates link. Links that would create a dependency cycle are rejected.
+// @Tags TaskLinks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.CreateTaskLink true "Link"
+// @Success 201 {object} response.SuccessWithData[model.TaskLink]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/links [post]
+func (lc *TaskLinkController) CreateLink(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.CreateTaskLink
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	link, err := lc.TaskLinkService.CreateLink(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.TaskLink]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Task link created successfully",
+		Data:    *link,
+	})
+}
+
+// Delete task link.
+// @Summary Delete task link
+// @Tags TaskLinks
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param linkID path string true "Link ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/links/{linkID} [delete]
+func (lc *TaskLinkController) DeleteLink(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	linkID, err := uuid.Parse(c.Params("linkID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid link ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := lc.TaskLinkService.DeleteLink(c, taskID, linkID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Task link deleted successfully",
+	})
+}
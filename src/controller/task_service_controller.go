@@ -7,7 +7,11 @@ import (
 	"app/src/service"
 	"app/src/utils"
 	"app/src/validation"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -25,7 +29,7 @@ func NewTaskController(taskService service.TaskService) *TaskController {
 
 // CreateProject creates a new project.
 // @Summary Create a new project
-// @Description Create a new project with the provided details.
+// @Description Create a new project with the provided details. Pass template_id to create its sections, tasks and groups from a project template.
 // @Tags Projects
 // @Accept json
 // @Produce json
@@ -73,6 +77,7 @@ func (tc *TaskController) CreateProject(c *fiber.Ctx) error {
 // @Param request body validation.CreateTask true "Task creation request"
 // @Success 200 {object} response.SuccessWithData[model.Task]
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
 // @Router /tasks [post]
 func (tc *TaskController) CreateTask(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
@@ -244,13 +249,15 @@ func (tc *TaskController) AddGroupToProject(c *fiber.Ctx) error {
 // @Param request body validation.AddGroupToTask true "Add group to task request"
 // @Success 200 {object} response.Common
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
 // @Router /tasks/add-group [post]
 func (tc *TaskController) AddGroupToTask(c *fiber.Ctx) error {
 	var req validation.AddGroupToTask
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
-	if err := tc.TaskService.AddGroupToTask(c, &req); err != nil {
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TaskService.AddGroupToTask(c, &req, user.ID); err != nil {
 		return err
 	}
 	return c.JSON(response.Common{
@@ -312,37 +319,63 @@ func (tc *TaskController) GetUsersInGroup(c *fiber.Ctx) error {
 
 // Get tasks of user.
 // @Summary Get tasks of user
-// @Description Retrieve a list of tasks.
+// @Description Retrieve tasks available to the user with filtering, sorting and cursor pagination.
 // @Tags Tasks
 // @Accept json
 // @Produce json
 // @Security  BearerAuth
-// @Success 200 {object} response.SuccessWithPaginate[model.Task]
+// @Param status query string false "Statuses, comma separated"
+// @Param priority query string false "Priorities, comma separated"
+// @Param project_id query string false "Project ID"
+// @Param section_id query string false "Section ID"
+// @Param assigned_to query string false "Assignee ID"
+// @Param parent_task_id query string false "Parent task ID or null for top-level tasks"
+// @Param due_from query string false "Due date from (YYYY-MM-DD)"
+// @Param due_to query string false "Due date to, inclusive (YYYY-MM-DD)"
+// @Param sort query string false "Sort column, prefix with - for descending" default(created_at)
+// @Param limit query int false "Maximum number of tasks" default(20)
+// @Param cursor query string false "Cursor from the previous page"
+// @Param labels query string false "Comma-separated label IDs"
+// @Param label_match query string false "any (default) or all of the labels"
+// @Param cf.{fieldID} query string false "Custom field filter: substring for text, value or from..to range for number and date, true/false for checkbox, exact value otherwise. Sort by a field with sort=cf.{fieldID}"
+// @Success 200 {object} response.SuccessWithCursor[model.Task]
 // @Failure 400 {object} response.ErrorResponse
 // @Router /tasks [get]
 func (tc *TaskController) GetTasks(c *fiber.Ctx) error {
-	var tasks []model.Task
-	authHeader := c.Get("Authorization")
-	token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
-
-	if token == "" {
-		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
-	}
-
-	userID, err := utils.VerifyToken(token, config.JWTSecret, config.TokenTypeAccess)
-	if err != nil {
-		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
-	}
-
-	tasks, err = tc.TaskService.GetUserTasks(uuid.MustParse(userID))
+	user, _ := c.Locals("user").(*model.User)
+	query := &validation.QueryTask{
+		Status:       c.Query("status"),
+		Priority:     c.Query("priority"),
+		ProjectID:    c.Query("project_id"),
+		SectionID:    c.Query("section_id"),
+		AssignedTo:   c.Query("assigned_to"),
+		ParentTaskID: c.Query("parent_task_id"),
+		DueFrom:      c.Query("due_from"),
+		DueTo:        c.Query("due_to"),
+		Sort:         c.Query("sort"),
+		Limit:        c.QueryInt("limit", 20),
+		Cursor:       c.Query("cursor"),
+		Labels:       c.Query("labels"),
+		LabelMatch:   c.Query("label_match"),
+		CustomFields: make(map[string]string),
+	}
+	for key, value := range c.Queries() {
+		if fieldID, ok := strings.CutPrefix(key, "cf."); ok {
+			query.CustomFields[fieldID] = value
+		}
+	}
+
+	tasks, nextCursor, err := tc.TaskService.GetUserTasks(c, user.ID, query)
 	if err != nil {
 		return err
 	}
-	return c.JSON(response.SuccessWithPaginate[model.Task]{
-		Code:    200,
-		Status:  "success",
-		Message: "Users in group retrieved successfully",
-		Results: tasks,
+	return c.JSON(response.SuccessWithCursor[model.Task]{
+		Code:       200,
+		Status:     "success",
+		Message:    "Tasks retrieved successfully",
+		Results:    tasks,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
 	})
 }
 
@@ -356,6 +389,7 @@ func (tc *TaskController) GetTasks(c *fiber.Ctx) error {
 // @Param request body validation.GetUsersInGroup true "Get users in group request"
 // @Success 200 {object} response.SuccessWithPaginate[model.User]
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
 // @Router /tasks [put]
 func (tc *TaskController) UpdateTaskTitleOrDescription(c *fiber.Ctx) error {
 
@@ -363,7 +397,8 @@ func (tc *TaskController) UpdateTaskTitleOrDescription(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
-	err := tc.TaskService.UpdateTaskTitleOrDescription(c, req.TaskID, req.Title, req.Description)
+	user, _ := c.Locals("user").(*model.User)
+	err := tc.TaskService.UpdateTaskTitleOrDescription(c, req.TaskID, req.Title, req.Description, user.ID)
 	if err != nil {
 		return err
 	}
@@ -410,45 +445,6 @@ func (tc *TaskController) GetUserProjects(c *fiber.Ctx) error {
 	})
 }
 
-// Comment task.
-// @Summary Comment task
-// @Tags Comments
-// @Accept json
-// @Produce json
-// @Security  BearerAuth
-// @Param request body validation.CreateComment true "Create comment"
-// @Success 200 {object} response.SuccessWithData[model.Comment]
-// @Failure 400 {object} response.ErrorResponse
-// @Router /comments [post]
-func (tc *TaskController) CommentTask(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
-
-	if token == "" {
-		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
-	}
-
-	userID, err := utils.VerifyToken(token, config.JWTSecret, config.TokenTypeAccess)
-	if err != nil {
-		return fiber.NewError(fiber.StatusUnauthorized, "Invalid token")
-	}
-	var req validation.CreateComment
-	if err := c.BodyParser(&req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
-	}
-	comment, err := tc.TaskService.CreateComment(c, &req, uuid.MustParse(userID))
-	if err != nil {
-		return err
-	}
-	return c.JSON(response.SuccessWithData[*model.Comment]{
-		Code:    200,
-		Status:  "success",
-		Message: "Comment send successfully",
-		Data:    comment,
-	})
-
-}
-
 // ReassignTask reassigns a task to a new user.
 // @Summary Reassign task to a new user
 // @Description Change the assignee of a task and notify via WebSocket.
@@ -460,6 +456,7 @@ func (tc *TaskController) CommentTask(c *fiber.Ctx) error {
 // @Param request body validation.ReassignTaskValidation true "New assignee information"
 // @Success 200 {object} response.Common
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
 // @Router /tasks/{taskID}/reassign [put]
 func (tc *TaskController) ReassignTask(c *fiber.Ctx) error {
 	var req validation.ReassignTaskValidation
@@ -467,7 +464,8 @@ func (tc *TaskController) ReassignTask(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request")
 	}
 
-	if err := tc.TaskService.ReassignTask(c, req); err != nil {
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TaskService.ReassignTask(c, req, user.ID); err != nil {
 		return err
 	}
 
@@ -480,7 +478,7 @@ func (tc *TaskController) ReassignTask(c *fiber.Ctx) error {
 
 // Get task by ID.
 // @Summary Get task by ID
-// @Description Retrieve a task by its unique ID.
+// @Description Retrieve a task by its unique ID with its top-level comments. Replies and paging are available from GET /tasks/{taskID}/comments.
 // @Tags Tasks
 // @Produce json
 // @Security BearerAuth
@@ -493,7 +491,8 @@ func (tc *TaskController) GetTaskByID(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
 	}
-	task, err := tc.TaskService.GetTaskByID(taskID)
+	user, _ := c.Locals("user").(*model.User)
+	task, err := tc.TaskService.GetTaskByID(c, taskID, user.ID)
 	if err != nil {
 		return err
 	}
@@ -505,9 +504,46 @@ func (tc *TaskController) GetTaskByID(c *fiber.Ctx) error {
 	})
 }
 
+// Get task history.
+// @Summary Get task history
+// @Description Field-level changes of the task with the author, newest first.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param limit query int false "Maximum number of entries" default(50)
+// @Param cursor query string false "Cursor from the previous page"
+// @Success 200 {object} response.SuccessWithCursor[model.TaskHistory]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/history [get]
+func (tc *TaskController) GetTaskHistory(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	query := &validation.QueryTaskHistory{
+		Limit:  c.QueryInt("limit", 50),
+		Cursor: c.Query("cursor"),
+	}
+	user, _ := c.Locals("user").(*model.User)
+	history, nextCursor, err := tc.TaskService.GetTaskHistory(c, taskID, query, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithCursor[model.TaskHistory]{
+		Code:       200,
+		Status:     "success",
+		Message:    "Task history retrieved successfully",
+		Results:    history,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	})
+}
+
 // Delete task by ID.
 // @Summary Delete task by ID
-// @Description Delete a task by its unique ID.
+// @Description Move a task with its subtasks to the project trash.
 // @Tags Tasks
 // @Security BearerAuth
 // @Param taskID path string true "Task ID"
@@ -519,7 +555,8 @@ func (tc *TaskController) DeleteTask(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
 	}
-	if err := tc.TaskService.DeleteTask(taskID); err != nil {
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TaskService.DeleteTask(taskID, user.ID); err != nil {
 		return err
 	}
 	return c.JSON(response.Common{
@@ -589,9 +626,80 @@ func (tc *TaskController) GetSectionsByUser(c *fiber.Ctx) error {
 	})
 }
 
+// Get project board.
+// @Summary Get project kanban board
+// @Description Sections in board order with their tasks ordered by rank, task counts and assignee summaries. Open tasks are the ones counted against the WIP limit.
+// @Description With swimlane the tasks are returned in lane cells and the columns keep only totals. A task with several labels appears in each label lane.
+// @Tags Sections
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Param swimlane query string false "Group the board into lanes" Enums(assignee, priority, user_group, label, custom_field)
+// @Param field_id query string false "Custom field ID for the custom_field swimlane"
+// @Success 200 {object} response.SuccessWithData[response.Board]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/board [get]
+func (tc *TaskController) GetBoard(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	query := &validation.QueryBoard{
+		Swimlane: c.Query("swimlane"),
+		FieldID:  c.Query("field_id"),
+	}
+	user, _ := c.Locals("user").(*model.User)
+	board, err := tc.TaskService.GetBoard(c, projectID, query, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Board]{
+		Code:    200,
+		Status:  "success",
+		Message: "Board retrieved successfully",
+		Data:    *board,
+	})
+}
+
+// Set section WIP limit.
+// @Summary Set section WIP limit
+// @Description Limit the number of open tasks in a section, 0 removes the limit. A strict limit blocks moves into a full section, otherwise moves only return a warning.
+// @Tags Sections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sectionID path string true "Section ID"
+// @Param request body validation.SectionWIPLimit true "WIP limit"
+// @Success 200 {object} response.SuccessWithData[model.Section]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /sections/{sectionID}/wip-limit [put]
+func (tc *TaskController) SetSectionWIPLimit(c *fiber.Ctx) error {
+	sectionID, err := uuid.Parse(c.Params("sectionID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid section ID")
+	}
+	var req validation.SectionWIPLimit
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	section, err := tc.TaskService.SetSectionWIPLimit(c, sectionID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Section]{
+		Code:    200,
+		Status:  "success",
+		Message: "WIP limit updated successfully",
+		Data:    *section,
+	})
+}
+
 // Delete section by ID.
 // @Summary Delete section by ID
-// @Description Delete a section by its unique ID.
+// @Description Move a section with its tasks to the project trash.
 // @Tags Sections
 // @Security BearerAuth
 // @Param sectionID path string true "Section ID"
@@ -603,7 +711,8 @@ func (tc *TaskController) DeleteSection(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid section ID")
 	}
-	if err := tc.TaskService.DeleteSection(sectionID); err != nil {
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TaskService.DeleteSection(sectionID, user.ID); err != nil {
 		return err
 	}
 	return c.JSON(response.Common{
@@ -612,3 +721,295 @@ func (tc *TaskController) DeleteSection(c *fiber.Ctx) error {
 		Message: "Section deleted successfully",
 	})
 }
+
+// Delete project by ID.
+// @Summary Delete project by ID
+// @Description Move a project with its sections and tasks to the trash. Only the project owner or a manager can do this.
+// @Tags Projects
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} response.Common
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID} [delete]
+func (tc *TaskController) DeleteProject(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TaskService.DeleteProject(projectID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Project deleted successfully",
+	})
+}
+
+// Get subtask tree.
+// @Summary Get subtask tree
+// @Description Retrieve a task with its whole tree of subtasks.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/subtasks [get]
+func (tc *TaskController) GetSubtaskTree(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := tc.TaskService.GetSubtaskTree(c, taskID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: "Subtasks retrieved successfully",
+		Data:    *task,
+	})
+}
+
+// Move subtask.
+// @Summary Move task subtree to a new parent
+// @Description Move a task with all its subtasks under another task. Pass null parent_task_id to make it a top-level task.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.MoveSubtask true "New parent"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/parent [put]
+func (tc *TaskController) MoveSubtask(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.MoveSubtask
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := tc.TaskService.MoveSubtask(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: "Task moved successfully",
+		Data:    *task,
+	})
+}
+
+// Bulk update tasks.
+// @Summary Apply one operation to many tasks
+// @Description Set status, set priority, reassign, move section, add group, set due date or delete up to 500 tasks in one transaction. Failures are reported per task and do not undo the other tasks.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body validation.BulkTask true "Operation"
+// @Success 200 {object} response.SuccessWithData[response.BulkTasks]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/bulk [post]
+func (tc *TaskController) BulkUpdateTasks(c *fiber.Ctx) error {
+	var req validation.BulkTask
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	result, err := tc.TaskService.BulkUpdateTasks(c, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.BulkTasks]{
+		Code:    200,
+		Status:  "success",
+		Message: "Bulk operation applied",
+		Data:    *result,
+	})
+}
+
+// Move task.
+// @Summary Move task within or between sections
+// @Description Place a task into a project section or a user section right after or before a neighbour task. Without a neighbour the task goes to the end.
+// @Description Moving an open task into a section over its WIP limit fails with 409 for strict limits, otherwise the X-WIP-Warning header is set.
+// @Description With swimlane and lane_key the grouping field is changed in the same transaction. For label lanes from_lane_key is replaced by lane_key. Lane keys must match board lanes: project members, groups attached to the project, and known or already used priorities.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.MoveTask true "Target position"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Header 200 {string} X-WIP-Warning "Section WIP limit is exceeded"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/move [put]
+func (tc *TaskController) MoveTask(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.MoveTask
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, warning, err := tc.TaskService.MoveTask(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		c.Set("X-WIP-Warning", warning)
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: "Task moved successfully",
+		Data:    *task,
+	})
+}
+
+// Update task status.
+// @Summary Update task status
+// @Description Move a task to another status following the project workflow.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.UpdateTaskStatus true "New status"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/status [put]
+func (tc *TaskController) UpdateTaskStatus(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.UpdateTaskStatus
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := tc.TaskService.UpdateTaskStatus(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: "Task status updated successfully",
+		Data:    *task,
+	})
+}
+
+// Export project tasks to CSV.
+// @Summary Export project tasks to CSV
+// @Description Tasks in board order with columns title, description, status, priority, due_date, assignee_email, section. The file starts with a UTF-8 BOM so spreadsheets detect the encoding.
+// @Tags Projects
+// @Produce text/csv
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/export.csv [get]
+func (tc *TaskController) ExportTasksCSV(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	stream, err := tc.TaskService.ExportTasksCSV(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	filename := fmt.Sprintf("tasks-%s.csv", time.Now().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Context().SetBodyStreamWriter(stream)
+	return nil
+}
+
+// Import project tasks from CSV.
+// @Summary Import project tasks from CSV
+// @Description Checks every row and reports errors per row. With dry_run (default) nothing is saved. Otherwise all tasks are created in one transaction, and any invalid row fails the whole import with 422 and the same report.
+// @Description Mapping is a JSON object from task field to column header, unmapped fields use the column named after the field. Empty status means the initial workflow status, empty section means the first project section.
+// @Tags Projects
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Param file formData file true "CSV file with a header row"
+// @Param mapping formData string false "Column mapping, e.g. {\"title\":\"Name\",\"assignee_email\":\"Owner\"}"
+// @Param dry_run formData bool false "Only check the rows (default true)"
+// @Success 200 {object} response.SuccessWithData[response.TaskImport]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorDetails
+// @Router /projects/{projectID}/import [post]
+func (tc *TaskController) ImportTasksCSV(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "File is required")
+	}
+
+	req := validation.ImportTasks{DryRun: true}
+	if value := c.FormValue("dry_run"); value != "" {
+		if req.DryRun, err = strconv.ParseBool(value); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid dry_run value")
+		}
+	}
+	if value := c.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &req.Mapping); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid mapping")
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	user, _ := c.Locals("user").(*model.User)
+	result, err := tc.TaskService.ImportTasksCSV(c, projectID, file, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	if result.Failed > 0 && !req.DryRun {
+		return response.Error(c, fiber.StatusUnprocessableEntity, "Import has invalid rows, nothing was imported", result)
+	}
+
+	message := "Tasks imported successfully"
+	if req.DryRun {
+		message = "Import checked, nothing was saved"
+	}
+	return c.JSON(response.SuccessWithData[response.TaskImport]{
+		Code:    200,
+		Status:  "success",
+		Message: message,
+		Data:    *result,
+	})
+}
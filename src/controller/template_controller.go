@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TemplateController struct {
+	TemplateService service.TemplateService
+}
+
+func NewTemplateController(templateService service.TemplateService) *TemplateController {
+	return &TemplateController{
+		TemplateService: templateService,
+	}
+}
+
+// Save project as template.
+// @Summary Save project as template
+// @Description Save sections, tasks with subtasks, groups and due dates (as offsets from the project start) of a project as a reusable template.
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Param request body validation.CreateProjectTemplate true "Template"
+// @Success 201 {object} response.SuccessWithData[model.ProjectTemplate]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/template [post]
+func (tc *TemplateController) CreateProjectTemplate(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	var req validation.CreateProjectTemplate
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	template, err := tc.TemplateService.CreateProjectTemplate(c, projectID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.ProjectTemplate]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Project template created successfully",
+		Data:    *template,
+	})
+}
+
+// Get project templates.
+// @Summary Get project templates
+// @Description Templates created by the user or saved from projects the user is a member of.
+// @Tags Templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessWithData[[]model.ProjectTemplate]
+// @Router /templates/projects [get]
+func (tc *TemplateController) GetProjectTemplates(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	templates, err := tc.TemplateService.GetProjectTemplates(c, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.ProjectTemplate]{
+		Code:    200,
+		Status:  "success",
+		Message: "Project templates retrieved successfully",
+		Data:    templates,
+	})
+}
+
+// Get project template.
+// @Summary Get project template
+// @Tags Templates
+// @Produce json
+// @Security BearerAuth
+// @Param templateID path string true "Template ID"
+// @Success 200 {object} response.SuccessWithData[model.ProjectTemplate]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /templates/projects/{templateID} [get]
+func (tc *TemplateController) GetProjectTemplate(c *fiber.Ctx) error {
+	templateID, err := uuid.Parse(c.Params("templateID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid template ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	template, err := tc.TemplateService.GetProjectTemplate(c, templateID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.ProjectTemplate]{
+		Code:    200,
+		Status:  "success",
+		Message: "Project template retrieved successfully",
+		Data:    *template,
+	})
+}
+
+// Delete project template.
+// @Summary Delete project template
+// @Description Only the author can delete a template. Projects created from it are not affected.
+// @Tags Templates
+// @Security BearerAuth
+// @Param templateID path string true "Template ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /templates/projects/{templateID} [delete]
+func (tc *TemplateController) DeleteProjectTemplate(c *fiber.Ctx) error {
+	templateID, err := uuid.Parse(c.Params("templateID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid template ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TemplateService.DeleteProjectTemplate(c, templateID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Project template deleted successfully",
+	})
+}
+
+// Save task as template.
+// @Summary Save task as template
+// @Description Save a task with its subtasks, groups and due dates (as offsets from the task creation) as a reusable template.
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.CreateTaskTemplate true "Template"
+// @Success 201 {object} response.SuccessWithData[model.TaskTemplate]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/template [post]
+func (tc *TemplateController) CreateTaskTemplate(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.CreateTaskTemplate
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	template, err := tc.TemplateService.CreateTaskTemplate(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.TaskTemplate]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Task template created successfully",
+		Data:    *template,
+	})
+}
+
+// Get task templates.
+// @Summary Get task templates
+// @Description Templates created by the user or saved from projects the user is a member of.
+// @Tags Templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessWithData[[]model.TaskTemplate]
+// @Router /templates/tasks [get]
+func (tc *TemplateController) GetTaskTemplates(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	templates, err := tc.TemplateService.GetTaskTemplates(c, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.TaskTemplate]{
+		Code:    200,
+		Status:  "success",
+		Message: "Task templates retrieved successfully",
+		Data:    templates,
+	})
+}
+
+// Delete task template.
+// @Summary Delete task template
+// @Tags Templates
+// @Security BearerAuth
+// @Param templateID path string true "Template ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /templates/tasks/{templateID} [delete]
+func (tc *TemplateController) DeleteTaskTemplate(c *fiber.Ctx) error {
+	templateID, err := uuid.Parse(c.Params("templateID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid template ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TemplateService.DeleteTaskTemplate(c, templateID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Task template deleted successfully",
+	})
+}
+
+// Create task from template.
+// @Summary Create task from template
+// @Description Create the template task with its subtasks at the end of a project section.
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param templateID path string true "Template ID"
+// @Param request body validation.InstantiateTaskTemplate true "Target"
+// @Success 201 {object} response.SuccessWithData[model.Task]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /templates/tasks/{templateID}/instantiate [post]
+func (tc *TemplateController) InstantiateTaskTemplate(c *fiber.Ctx) error {
+	templateID, err := uuid.Parse(c.Params("templateID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid template ID")
+	}
+	var req validation.InstantiateTaskTemplate
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := tc.TemplateService.InstantiateTaskTemplate(c, templateID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.Task]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Task created from template successfully",
+		Data:    *task,
+	})
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TimeEntryController struct {
+	TimeEntryService service.TimeEntryService
+}
+
+func NewTimeEntryController(timeEntryService service.TimeEntryService) *TimeEntryController {
+	return &TimeEntryController{
+		TimeEntryService: timeEntryService,
+	}
+}
+
+// Start timer.
+// @Summary Start timer on a task
+// @Description Start tracking time on a task. A user can have only one running timer.
+// @Tags TimeTracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.StartTimer false "Timer"
+// @Success 201 {object} response.SuccessWithData[model.TimeEntry]
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/timer [post]
+func (tc *TimeEntryController) StartTimer(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.StartTimer
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+	user, _ := c.Locals("user").(*model.User)
+	entry, err := tc.TimeEntryService.StartTimer(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.TimeEntry]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Timer started successfully",
+		Data:    *entry,
+	})
+}
+
+// Get running timer.
+// @Summary Get running timer
+// @Tags TimeTracking
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessWithData[model.TimeEntry]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /timer [get]
+func (tc *TimeEntryController) GetRunningTimer(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	entry, err := tc.TimeEntryService.GetRunningTimer(c, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.TimeEntry]{
+		Code:    200,
+		Status:  "success",
+		Message: "Running timer retrieved successfully",
+		Data:    *entry,
+	})
+}
+
+// Stop timer.
+// @Summary Stop running timer
+// @Description Stop the running timer of the current user and add its time to the task.
+// @Tags TimeTracking
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessWithData[model.TimeEntry]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /timer/stop [post]
+func (tc *TimeEntryController) StopTimer(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	entry, err := tc.TimeEntryService.StopTimer(c, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.TimeEntry]{
+		Code:    200,
+		Status:  "success",
+		Message: "Timer stopped successfully",
+		Data:    *entry,
+	})
+}
+
+// Get task time entries.
+// @Summary Get task time entries
+// @Tags TimeTracking
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.SuccessWithData[[]model.TimeEntry]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/time-entries [get]
+func (tc *TimeEntryController) GetTaskEntries(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	entries, err := tc.TimeEntryService.GetTaskEntries(c, taskID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]model.TimeEntry]{
+		Code:    200,
+		Status:  "success",
+		Message: "Time entries retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// Log time manually.
+// @Summary Log time manually
+// @Tags TimeTracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Param request body validation.CreateTimeEntry true "Time entry"
+// @Success 201 {object} response.SuccessWithData[model.TimeEntry]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/time-entries [post]
+func (tc *TimeEntryController) CreateEntry(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	var req validation.CreateTimeEntry
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	entry, err := tc.TimeEntryService.CreateEntry(c, taskID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.SuccessWithData[model.TimeEntry]{
+		Code:    fiber.StatusCreated,
+		Status:  "success",
+		Message: "Time entry created successfully",
+		Data:    *entry,
+	})
+}
+
+// Edit time entry.
+// @Summary Edit time entry
+// @Description Change start or duration of an own entry. The reason for the edit is required.
+// @Tags TimeTracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entryID path string true "Time entry ID"
+// @Param request body validation.UpdateTimeEntry true "Changes"
+// @Success 200 {object} response.SuccessWithData[model.TimeEntry]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /time-entries/{entryID} [put]
+func (tc *TimeEntryController) UpdateEntry(c *fiber.Ctx) error {
+	entryID, err := uuid.Parse(c.Params("entryID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid time entry ID")
+	}
+	var req validation.UpdateTimeEntry
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	entry, err := tc.TimeEntryService.UpdateEntry(c, entryID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.TimeEntry]{
+		Code:    200,
+		Status:  "success",
+		Message: "Time entry updated successfully",
+		Data:    *entry,
+	})
+}
+
+// Delete time entry.
+// @Summary Delete time entry
+// @Tags TimeTracking
+// @Security BearerAuth
+// @Param entryID path string true "Time entry ID"
+// @Success 200 {object} response.Common
+// @Failure 404 {object} response.ErrorResponse
+// @Router /time-entries/{entryID} [delete]
+func (tc *TimeEntryController) DeleteEntry(c *fiber.Ctx) error {
+	entryID, err := uuid.Parse(c.Params("entryID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid time entry ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	if err := tc.TimeEntryService.DeleteEntry(c, entryID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(response.Common{
+		Code:    200,
+		Status:  "success",
+		Message: "Time entry deleted successfully",
+	})
+}
+
+// Get timesheet.
+// @Summary Get timesheet
+// @Description Logged time per day and task compared with the user's daily work time. Other users' timesheets require the getUsers right.
+// @Tags TimeTracking
+// @Produce json
+// @Security BearerAuth
+// @Param user query string false "User ID, defaults to the current user"
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date inclusive (YYYY-MM-DD)"
+// @Success 200 {object} response.SuccessWithData[response.Timesheet]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /timesheets [get]
+func (tc *TimeEntryController) GetTimesheet(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	query := &validation.QueryTimesheet{
+		User: c.Query("user"),
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+	timesheet, err := tc.TimeEntryService.GetTimesheet(c, query, user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Timesheet]{
+		Code:    200,
+		Status:  "success",
+		Message: "Timesheet retrieved successfully",
+		Data:    *timesheet,
+	})
+}
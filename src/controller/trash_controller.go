@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TrashController struct {
+	TrashService service.TrashService
+}
+
+func NewTrashController(trashService service.TrashService) *TrashController {
+	return &TrashController{
+		TrashService: trashService,
+	}
+}
+
+// Get project trash.
+// @Summary Get project trash
+// @Description Deleted tasks and sections of a project. Items deleted together with a parent are restored with it and are not listed.
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} response.SuccessWithData[[]response.TrashItem]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/trash [get]
+func (tc *TrashController) GetProjectTrash(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	items, err := tc.TrashService.GetProjectTrash(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]response.TrashItem]{
+		Code:    200,
+		Status:  "success",
+		Message: "Trash retrieved successfully",
+		Data:    items,
+	})
+}
+
+// Get deleted projects.
+// @Summary Get deleted projects
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessWithData[[]response.TrashItem]
+// @Router /trash/projects [get]
+func (tc *TrashController) GetDeletedProjects(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+	items, err := tc.TrashService.GetDeletedProjects(c, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[[]response.TrashItem]{
+		Code:    200,
+		Status:  "success",
+		Message: "Deleted projects retrieved successfully",
+		Data:    items,
+	})
+}
+
+// Restore task.
+// @Summary Restore task from trash
+// @Description Restore a task with the subtasks deleted together with it. The parent task, section and project must not be deleted.
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path string true "Task ID"
+// @Success 200 {object} response.SuccessWithData[model.Task]
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /tasks/{taskID}/restore [post]
+func (tc *TrashController) RestoreTask(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("taskID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid task ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	task, err := tc.TrashService.RestoreTask(c, taskID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Task]{
+		Code:    200,
+		Status:  "success",
+		Message: "Task restored successfully",
+		Data:    *task,
+	})
+}
+
+// Restore section.
+// @Summary Restore section from trash
+// @Description Restore a section with the tasks deleted together with it. The project must not be deleted.
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Param sectionID path string true "Section ID"
+// @Success 200 {object} response.SuccessWithData[model.Section]
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /sections/{sectionID}/restore [post]
+func (tc *TrashController) RestoreSection(c *fiber.Ctx) error {
+	sectionID, err := uuid.Parse(c.Params("sectionID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid section ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	section, err := tc.TrashService.RestoreSection(c, sectionID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Section]{
+		Code:    200,
+		Status:  "success",
+		Message: "Section restored successfully",
+		Data:    *section,
+	})
+}
+
+// Restore project.
+// @Summary Restore project from trash
+// @Description Restore a project with the sections and tasks deleted together with it. Only the project owner or a manager can do this.
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} response.SuccessWithData[model.Project]
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /projects/{projectID}/restore [post]
+func (tc *TrashController) RestoreProject(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	project, err := tc.TrashService.RestoreProject(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[model.Project]{
+		Code:    200,
+		Status:  "success",
+		Message: "Project restored successfully",
+		Data:    *project,
+	})
+}
@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"app/src/config"
 	"app/src/model"
 	"app/src/response"
 	"app/src/service"
 	"app/src/validation"
 	"math"
+	"slices"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -126,7 +128,7 @@ func (u *UserController) CreateUser(c *fiber.Ctx) error {
 
 // @Tags         Users
 // @Summary      Update a user
-// @Description  Logged in users can only update their own information. Only admins can update other users.
+// @Description  Logged in users can only update their own information. Only admins can update other users and change roles.
 // @Security BearerAuth
 // @Produce      json
 // @Param        id  path  string  true  "User id"
@@ -149,6 +151,12 @@ func (u *UserController) UpdateUser(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
+	// Пользователь может править себя, но не свою роль
+	if current, _ := c.Locals("user").(*model.User); req.Role != "" &&
+		(current == nil || !slices.Contains(config.RoleRights[current.Role], "manageUsers")) {
+		return fiber.NewError(fiber.StatusForbidden, "You don't have permission to change roles")
+	}
+
 	user, err := u.UserService.UpdateUser(c, req, userID)
 	if err != nil {
 		return err
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WorkflowController struct {
+	WorkflowService service.WorkflowService
+}
+
+func NewWorkflowController(workflowService service.WorkflowService) *WorkflowController {
+	return &WorkflowController{
+		WorkflowService: workflowService,
+	}
+}
+
+// Get project workflow.
+// @Summary Get project workflow
+// @Description Retrieve task statuses and allowed transitions of a project. Projects without their own workflow get the default one.
+// @Tags Projects
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} response.SuccessWithData[response.Workflow]
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/workflow [get]
+func (wc *WorkflowController) GetWorkflow(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	user, _ := c.Locals("user").(*model.User)
+	workflow, err := wc.WorkflowService.GetWorkflow(c, projectID, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Workflow]{
+		Code:    200,
+		Status:  "success",
+		Message: "Workflow retrieved successfully",
+		Data:    *workflow,
+	})
+}
+
+// Update project workflow.
+// @Summary Update project workflow
+// @Description Replace task statuses and allowed transitions of a project. Only the project owner or a manager can change it.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path string true "Project ID"
+// @Param request body validation.UpdateWorkflow true "Workflow definition"
+// @Success 200 {object} response.SuccessWithData[response.Workflow]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /projects/{projectID}/workflow [put]
+func (wc *WorkflowController) UpdateWorkflow(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid project ID")
+	}
+	var req validation.UpdateWorkflow
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	user, _ := c.Locals("user").(*model.User)
+	workflow, err := wc.WorkflowService.UpdateWorkflow(c, projectID, &req, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(response.SuccessWithData[response.Workflow]{
+		Code:    200,
+		Status:  "success",
+		Message: "Workflow updated successfully",
+		Data:    *workflow,
+	})
+}
@@ -0,0 +1,9 @@
+package database
+
+import "gorm.io/gorm"
+
+// MigrateAuditLog убирает колонки старой схемы журнала аудита, привязанной к задаче.
+// AutoMigrate не удаляет колонки, а NOT NULL на task_id ломает вставку новых записей
+func MigrateAuditLog(db *gorm.DB) error {
+	return db.Exec(`ALTER TABLE audit_logs DROP COLUMN IF EXISTS task_id, DROP COLUMN IF EXISTS body`).Error
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"app/src/config"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SetupFullTextSearch добавляет tsvector-колонки и GIN-индексы для поиска.
+// Колонки генерируемые, поэтому AutoMigrate про них не знает
+func SetupFullTextSearch(db *gorm.DB) error {
+	statements := []string{
+		fmt.Sprintf(`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('%[1]s', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('%[1]s', coalesce(description, '')), 'B')
+			) STORED`, config.SearchConfig),
+		`CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector)`,
+		fmt.Sprintf(`ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (to_tsvector('%s', coalesce(body, ''))) STORED`, config.SearchConfig),
+		`CREATE INDEX IF NOT EXISTS idx_comments_search_vector ON comments USING GIN (search_vector)`,
+		fmt.Sprintf(`ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (to_tsvector('%s', coalesce(title, ''))) STORED`, config.SearchConfig),
+		`CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN (search_vector)`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,12 +6,14 @@ import (
 	"app/src/middleware"
 	"app/src/model"
 
-	"strings"
-
 	"app/src/router"
+	"app/src/service"
+	"app/src/storage"
 	"app/src/utils"
+	"app/src/validation"
 	"context"
 	"fmt"
+	"time"
 
 	"os"
 	"os/signal"
@@ -19,7 +21,6 @@ import (
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cache"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
@@ -37,6 +38,7 @@ import (
 // @in header
 // @name Authorization
 // @description Example Value: Bearer eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9...
+// @securityDefinitions.basic BasicAuth
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -46,6 +48,7 @@ func main() {
 	db := setupDatabase()
 	defer closeDatabase(db)
 	setupRoutes(app, db)
+	startSchedulers(ctx, db)
 
 	address := fmt.Sprintf("%s:%d", config.AppHost, config.AppPort)
 
@@ -71,11 +74,7 @@ func setupFiberApp() *fiber.App {
 	}))
 
 	app.Use(middleware.RecoverConfig())
-	app.Use(cache.New(cache.Config{
-		Next: func(c *fiber.Ctx) bool {
-			return strings.Contains(c.Route().Path, "/ws")
-		},
-	}))
+	app.Use(middleware.CacheConfig())
 	app.Use("/ws", func(c *fiber.Ctx) error {
 		// Проверяем, является ли запрос WebSocket-подключением
 		if websocket.IsWebSocketUpgrade(c) {
@@ -111,7 +110,12 @@ func setupDatabase() *gorm.DB {
 		&model.Task{},
 		&model.TaskHistory{},
 		&model.Comment{},
+		&model.CommentRevision{},
+		&model.CommentReaction{},
+		&model.Mention{},
 		&model.Attachment{},
+		&model.AttachmentThumbnail{},
+		&model.CalendarFeed{},
 		&model.AuditLog{},
 		&model.ProjectPermission{},
 		&model.RolePermission{},
@@ -119,10 +123,25 @@ func setupDatabase() *gorm.DB {
 		&model.UserProjectRole{},
 		&model.ProjectUser{},
 		&model.TaskUser{},
+		&model.WorkflowStatus{},
+		&model.WorkflowTransition{},
+		&model.TaskLink{},
+		&model.TaskRecurrence{},
+		&model.TimeEntry{},
+		&model.ProjectTemplate{},
+		&model.TaskTemplate{},
+		&model.Label{},
+		&model.CustomField{},
 	)
 	if err != nil {
 		panic("Failed to auto migrate database")
 	}
+	if err := database.SetupFullTextSearch(db); err != nil {
+		panic("Failed to set up full-text search")
+	}
+	if err := database.MigrateAuditLog(db); err != nil {
+		panic("Failed to migrate audit log")
+	}
 	return db
 }
 
@@ -131,6 +150,21 @@ func setupRoutes(app *fiber.App, db *gorm.DB) {
 	app.Use(utils.NotFoundHandler)
 }
 
+// startSchedulers запускает фоновые задачи, живущие до отмены ctx
+func startSchedulers(ctx context.Context, db *gorm.DB) {
+	validate := validation.Validator()
+	workflowService := service.NewWorkflowService(db, validate)
+	recurrenceService := service.NewRecurrenceService(db, validate, workflowService)
+	fileStorage, err := storage.New(ctx)
+	if err != nil {
+		panic("Failed to set up storage: " + err.Error())
+	}
+	trashService := service.NewTrashService(db, validate, config.RedisClient(), fileStorage)
+
+	go recurrenceService.RunScheduler(ctx, time.Minute)
+	go trashService.RunPurge(ctx, time.Hour)
+}
+
 func startServer(app *fiber.App, address string, errs chan<- error) {
 	if err := app.Listen(address); err != nil {
 		errs <- fmt.Errorf("error starting server: %w", err)
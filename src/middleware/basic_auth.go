@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Неудачные попытки Basic-авторизации ограничиваются так же, как вход через /v1/auth
+const (
+	basicAuthMaxFailures = 20
+	basicAuthWindow      = 15 * time.Minute
+)
+
+// BasicAuth пускает по email и паролю для клиентов вроде CalDAV, которые не умеют Bearer-токены.
+// Запрос с Bearer-токеном проверяется как в Auth. Неверный пароль пишется в аудит как неудачный вход,
+// после basicAuthMaxFailures ошибок с одного IP пароль не проверяется до конца окна
+func BasicAuth(userService service.UserService, auditService service.AuditService) fiber.Handler {
+	bearer := Auth(userService)
+	failures := &failureCounter{entries: make(map[string]*failureEntry)}
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get(fiber.HeaderAuthorization)
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			return bearer(c)
+		}
+
+		email, password, ok := parseBasicAuth(authHeader)
+		if ok {
+			if failures.blocked(c.IP(), time.Now()) {
+				return c.Status(fiber.StatusTooManyRequests).JSON(response.Common{
+					Code:    fiber.StatusTooManyRequests,
+					Status:  "error",
+					Message: "Too many requests, please try again later",
+				})
+			}
+
+			user, err := userService.GetUserByEmail(c, email)
+			if err == nil && utils.CheckPasswordHash(password, user.Password) {
+				c.Locals("user", user)
+				return c.Next()
+			}
+
+			failures.add(c.IP(), time.Now())
+			entry := &model.AuditLog{
+				ActionType: model.AuditLoginFailed,
+				EntityType: "user",
+				Details:    map[string]interface{}{"email": email, "method": "basic"},
+			}
+			if err == nil {
+				entry.EntityID = &user.ID
+			}
+			auditService.Record(c, entry)
+		}
+
+		// Без заголовка-вызова клиенты не спрашивают у пользователя пароль
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="workmanager", charset="UTF-8"`)
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+}
+
+func parseBasicAuth(header string) (string, string, bool) {
+	encoded, found := strings.CutPrefix(header, "Basic ")
+	if !found {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+	email, password, found := strings.Cut(string(decoded), ":")
+	if !found || email == "" {
+		return "", "", false
+	}
+	return email, password, true
+}
+
+// failureCounter считает неудачные попытки по ключу в фиксированном окне.
+// Запрос без учётных данных (вызов 401 перед вводом пароля) попыткой не считается
+type failureCounter struct {
+	mu      sync.Mutex
+	entries map[string]*failureEntry
+	sweep   time.Time
+}
+
+type failureEntry struct {
+	count int
+	reset time.Time
+}
+
+func (f *failureCounter) blocked(key string, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry, ok := f.entries[key]
+	return ok && now.Before(entry.reset) && entry.count >= basicAuthMaxFailures
+}
+
+func (f *failureCounter) add(key string, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry, ok := f.entries[key]
+	// Раз в окно убираем истёкшие записи, чтобы карта не росла с каждым новым IP
+	if !now.Before(f.sweep) {
+		for k, e := range f.entries {
+			if !now.Before(e.reset) {
+				delete(f.entries, k)
+			}
+		}
+		f.sweep = now.Add(basicAuthWindow)
+	}
+	if !ok || !now.Before(entry.reset) {
+		entry = &failureEntry{reset: now.Add(basicAuthWindow)}
+		f.entries[key] = entry
+	}
+	entry.count++
+}
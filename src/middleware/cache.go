@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cache"
+)
+
+// CacheConfig кэширует ответы анонимным клиентам. Запросы с Authorization проходят мимо кэша:
+// попадание в кэш отдаёт ответ раньше, чем проверка доступа в маршруте
+func CacheConfig() fiber.Handler {
+	handler := cache.New(cache.Config{
+		// По умолчанию ключ - только путь, и разные фильтры и страницы курсора получали бы один ответ
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.OriginalURL()
+		},
+		// Сохраняются только успешные ответы, которые сам маршрут не пометил как личные,
+		// например ленты календаря с токеном в адресе
+		Next: func(c *fiber.Ctx) bool {
+			cacheControl := c.GetRespHeader(fiber.HeaderCacheControl)
+			return c.Response().StatusCode() != fiber.StatusOK ||
+				strings.Contains(cacheControl, "private") || strings.Contains(cacheControl, "no-store")
+		},
+	})
+	return func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) != "" {
+			return c.Next()
+		}
+		return handler(c)
+	}
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,10 +26,10 @@ type BaseModel struct {
 type User struct {
 	BaseModel
 	Name               string              `gorm:"not null" json:"name"`
-	Email              string              `gorm:"uniqueIndex;not null" json:"email"` // Уникальный индекс для email	Password           string              `gorm:"not null" json:"-"`
+	Email              string              `gorm:"uniqueIndex;not null" json:"email"` // Уникальный индекс для email
 	Role               string              `gorm:"default:user;not null" json:"role"`
-	WorkTime           int                 `gorm:""json:"work_time"`
-	Password           string              `gorm:"not null"json:"password"`
+	WorkTime           int                 `json:"work_time"` // Норма часов в рабочий день (пн-пт)
+	Password           string              `gorm:"not null" json:"-"`
 	VerifiedEmail      bool                `gorm:"default:false;not null" json:"verified_email"`
 	ProjectPermissions []ProjectPermission `gorm:"foreignKey:UserID" json:"project_permissions"`
 	Projects           []Project           `gorm:"many2many:project_users;" json:"projects"`
@@ -46,9 +49,11 @@ type Token struct {
 
 type Project struct {
 	BaseModel
-	Title      string      `gorm:"not null" json:"title"`
-	Users      []User      `gorm:"many2many:project_users;" json:"users"`
-	UserGroups []UserGroup `gorm:"many2many:project_user_groups;" json:"user_groups"`
+	Title      string         `gorm:"not null" json:"title"`
+	Users      []User         `gorm:"many2many:project_users;" json:"users"`
+	UserGroups []UserGroup    `gorm:"many2many:project_user_groups;" json:"user_groups"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"` // Проект в корзине
+	DeletedBy  *uuid.UUID     `json:"-"`
 }
 
 type ProjectUser struct {
@@ -58,33 +63,241 @@ type ProjectUser struct {
 
 type Section struct {
 	BaseModel
-	Title     string     `gorm:"not null" json:"title"`
-	ProjectID uuid.UUID  `gorm:"not null" json:"project_id"`
-	UserGroup *uuid.UUID `json:"user_group,omitempty"`
-	Project   Project    `gorm:"foreignKey:ProjectID;onDelete:CASCADE"`
-	Tasks     []Task     `gorm:"foreignKey:SectionID;constraint:OnDelete:CASCADE" json:"tasks,omitempty"`
-	Order     int        `gorm:"not null;default:0" json:"order"`
+	Title     string         `gorm:"not null" json:"title"`
+	ProjectID uuid.UUID      `gorm:"not null" json:"project_id"`
+	UserGroup *uuid.UUID     `json:"user_group,omitempty"`
+	Project   Project        `gorm:"foreignKey:ProjectID;onDelete:CASCADE"`
+	Tasks     []Task         `gorm:"foreignKey:SectionID;constraint:OnDelete:CASCADE" json:"tasks,omitempty"`
+	Order     int            `gorm:"not null;default:0" json:"order"`
+	WIPLimit  int            `gorm:"not null;default:0" json:"wip_limit"`      // Лимит незавершённых задач, 0 - без лимита
+	WIPStrict bool           `gorm:"not null;default:false" json:"wip_strict"` // Запрещать перенос сверх лимита, иначе только предупреждать
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`                           // Секция в корзине
+	DeletedBy *uuid.UUID     `json:"-"`
 }
 
 // ======= Задачи =======
 type Task struct {
 	BaseModel
-	ProjectID     uuid.UUID   `json:"project_id"`
-	Project       Project     `gorm:"foreignKey:ProjectID;onDelete:CASCADE"`
-	Title         string      `gorm:"not null" json:"title"`
-	Description   string      `json:"description"`
-	UserGroup     *uuid.UUID  `json:"user_group,omitempty"`
-	Status        string      `json:"status"`
-	Priority      string      `json:"priority"`
-	DueDate       *time.Time  `json:"due_date,omitempty"`
-	SectionID     uuid.UUID   `gorm:"not null" json:"section_id"`
-	UserSectionID *uuid.UUID  `json:"user_section_id,omitempty"` // Should match UserSection.ID type
-	AssignedTo    *uuid.UUID  `json:"assigned_to,omitempty"`
-	ParentTaskID  *uuid.UUID  `json:"parent_task_id,omitempty"`
-	EstimatedTime int         `json:"estimated_time"`
-	SpentTime     int         `json:"spent_time"`
-	Users         []User      `gorm:"many2many:task_users;" json:"users"`
-	UserGroups    []UserGroup `gorm:"many2many:task_user_groups;" json:"user_groups"`
+	ProjectID     uuid.UUID         `gorm:"index" json:"project_id"`
+	Project       Project           `gorm:"foreignKey:ProjectID;onDelete:CASCADE"`
+	Title         string            `gorm:"not null" json:"title"`
+	Description   string            `json:"description"`
+	UserGroup     *uuid.UUID        `json:"user_group,omitempty"`
+	Status        string            `gorm:"index" json:"status"`
+	Priority      string            `json:"priority"`
+	DueDate       *time.Time        `gorm:"index" json:"due_date,omitempty"`
+	SectionID     uuid.UUID         `gorm:"not null;index" json:"section_id"`
+	UserSectionID *uuid.UUID        `json:"user_section_id,omitempty"`                  // Should match UserSection.ID type
+	Rank          string            `gorm:"not null;default:'';index" json:"rank"`      // Позиция в секции проекта
+	UserRank      string            `gorm:"not null;default:'';index" json:"user_rank"` // Позиция в секции пользователя
+	AssignedTo    *uuid.UUID        `gorm:"index" json:"assigned_to,omitempty"`
+	ParentTaskID  *uuid.UUID        `gorm:"index" json:"parent_task_id,omitempty"`
+	EstimatedTime int               `json:"estimated_time"`
+	SpentTime     int               `json:"spent_time"` // Минуты, считаются по записям времени
+	Users         []User            `gorm:"many2many:task_users;" json:"users"`
+	UserGroups    []UserGroup       `gorm:"many2many:task_user_groups;" json:"user_groups"`
+	Subtasks      []Task            `gorm:"foreignKey:ParentTaskID;constraint:OnDelete:CASCADE" json:"subtasks,omitempty"`
+	RecurrenceID  *uuid.UUID        `gorm:"index" json:"recurrence_id,omitempty"`
+	Labels        []Label           `gorm:"many2many:task_labels;constraint:OnDelete:CASCADE" json:"labels,omitempty"`
+	Comments      []Comment         `gorm:"foreignKey:TaskID" json:"comments,omitempty"` // Только в GET /tasks/:taskID, корневые комментарии
+	CustomFields  CustomFieldValues `gorm:"type:jsonb;not null;default:'{}'" json:"custom_fields"` // Значения по ID поля
+	Blocked       bool              `gorm:"-" json:"blocked"`                                      // Есть незакрытые блокирующие задачи
+	DeletedAt     gorm.DeletedAt    `gorm:"index" json:"-"`                                        // Задача в корзине
+	DeletedBy     *uuid.UUID        `json:"-"`
+}
+
+// Статусы воркфлоу по умолчанию
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusReview     = "review"
+	TaskStatusDone       = "done"
+)
+
+// ======= Воркфлоу статусов проекта =======
+
+type WorkflowStatus struct {
+	BaseModel
+	ProjectID uuid.UUID `gorm:"not null;uniqueIndex:idx_workflow_status_name" json:"project_id"`
+	Project   Project   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_workflow_status_name" json:"name"`
+	Order     int       `gorm:"not null;default:0" json:"order"`
+	IsInitial bool      `gorm:"default:false;not null" json:"is_initial"` // Статус новых задач
+	IsFinal   bool      `gorm:"default:false;not null" json:"is_final"`   // Задача считается выполненной
+}
+
+// Гарды переходов
+const (
+	GuardAssigneeRequired = "assignee_required"
+	GuardSubtasksDone     = "subtasks_done"
+	GuardEstimateRequired = "estimate_required"
+	GuardDueDateRequired  = "due_date_required"
+)
+
+type WorkflowTransition struct {
+	BaseModel
+	ProjectID  uuid.UUID `gorm:"not null;uniqueIndex:idx_workflow_transition" json:"project_id"`
+	Project    Project   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	FromStatus string    `gorm:"not null;uniqueIndex:idx_workflow_transition" json:"from_status"`
+	ToStatus   string    `gorm:"not null;uniqueIndex:idx_workflow_transition" json:"to_status"`
+	Guards     []string  `gorm:"serializer:json" json:"guards"`
+}
+
+// ======= Связи между задачами =======
+
+const (
+	TaskLinkBlocks     = "blocks"
+	TaskLinkRelatesTo  = "relates_to"
+	TaskLinkDuplicates = "duplicates"
+)
+
+type TaskLink struct {
+	BaseModel
+	SourceTaskID uuid.UUID `gorm:"not null;uniqueIndex:idx_task_link" json:"source_task_id"`
+	SourceTask   *Task     `gorm:"foreignKey:SourceTaskID;constraint:OnDelete:CASCADE" json:"source_task,omitempty"`
+	TargetTaskID uuid.UUID `gorm:"not null;uniqueIndex:idx_task_link;index" json:"target_task_id"`
+	TargetTask   *Task     `gorm:"foreignKey:TargetTaskID;constraint:OnDelete:CASCADE" json:"target_task,omitempty"`
+	Type         string    `gorm:"not null;uniqueIndex:idx_task_link" json:"type"`
+	CreatedBy    uuid.UUID `gorm:"not null" json:"created_by"`
+}
+
+// ======= Метки =======
+
+type Label struct {
+	BaseModel
+	ProjectID uuid.UUID `gorm:"not null;uniqueIndex:idx_label_name" json:"project_id"`
+	Project   Project   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_label_name" json:"name"`
+	Color     string    `gorm:"not null;default:'#9e9e9e'" json:"color"`
+}
+
+// ======= Пользовательские поля =======
+
+// Типы пользовательских полей
+const (
+	CustomFieldText        = "text"
+	CustomFieldNumber      = "number"
+	CustomFieldDate        = "date" // Значение в формате 2006-01-02
+	CustomFieldSelect      = "select"
+	CustomFieldMultiSelect = "multi_select"
+	CustomFieldUser        = "user" // ID участника проекта
+	CustomFieldCheckbox    = "checkbox"
+)
+
+type CustomField struct {
+	BaseModel
+	ProjectID uuid.UUID `gorm:"not null;uniqueIndex:idx_custom_field_name" json:"project_id"`
+	Project   Project   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_custom_field_name" json:"name"`
+	Type      string    `gorm:"not null" json:"type"`
+	Options   []string  `gorm:"serializer:json" json:"options,omitempty"` // Варианты для select и multi_select
+	Order     int       `gorm:"not null;default:0" json:"order"`
+}
+
+// CustomFieldValues - значения пользовательских полей задачи, хранятся в jsonb
+type CustomFieldValues map[string]interface{}
+
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (v *CustomFieldValues) Scan(value interface{}) error {
+	var data []byte
+	switch value := value.(type) {
+	case nil:
+		*v = CustomFieldValues{}
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.New("unsupported custom field values type")
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ======= Повторяющиеся задачи =======
+
+type TaskRecurrence struct {
+	BaseModel
+	ProjectID      uuid.UUID   `gorm:"not null;index" json:"project_id"`
+	SectionID      uuid.UUID   `gorm:"not null" json:"section_id"`
+	UserSectionID  *uuid.UUID  `json:"user_section_id,omitempty"`
+	RRule          string      `gorm:"not null" json:"rrule"` // RFC 5545, например FREQ=WEEKLY;BYDAY=MO
+	DTStart        time.Time   `gorm:"not null" json:"dtstart"`
+	ExDates        []time.Time `gorm:"serializer:json" json:"exdates"` // Пропущенные вхождения
+	LastTaskID     uuid.UUID   `gorm:"not null" json:"last_task_id"`   // Последнее созданное вхождение
+	LastOccurrence time.Time   `gorm:"not null" json:"last_occurrence"`
+	EndedAt        *time.Time  `json:"ended_at,omitempty"`
+	// Шаблон следующих вхождений
+	Title         string     `gorm:"not null" json:"title"`
+	Description   string     `json:"description"`
+	Priority      string     `json:"priority"`
+	AssignedTo    *uuid.UUID `json:"assigned_to,omitempty"`
+	EstimatedTime int        `json:"estimated_time"`
+}
+
+// ======= Учёт времени =======
+
+type TimeEntry struct {
+	BaseModel
+	TaskID    uuid.UUID  `gorm:"not null;index" json:"task_id"`
+	Task      *Task      `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"task,omitempty"`
+	UserID    uuid.UUID  `gorm:"not null;index;uniqueIndex:idx_time_entry_running,where:ended_at IS NULL" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	StartedAt time.Time  `gorm:"not null;index" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`                 // nil - таймер запущен
+	Duration  int        `gorm:"not null;default:0" json:"duration"` // Минуты
+	Note      string     `json:"note"`
+	Manual    bool       `gorm:"default:false;not null" json:"manual"`
+	EditNote  string     `json:"edit_note,omitempty"` // Причина последней правки
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+// ======= Шаблоны =======
+
+// TemplateTask - задача шаблона вместе с подзадачами
+type TemplateTask struct {
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Priority      string         `json:"priority"`
+	EstimatedTime int            `json:"estimated_time"`
+	DueOffsetDays *int           `json:"due_offset_days,omitempty"` // Срок в днях от даты начала
+	GroupIDs      []uuid.UUID    `json:"group_ids"`
+	Subtasks      []TemplateTask `json:"subtasks"`
+}
+
+type TemplateSection struct {
+	Title string         `json:"title"`
+	Order int            `json:"order"`
+	Tasks []TemplateTask `json:"tasks"`
+}
+
+// Шаблон видят автор и участники проекта, из которого он сохранён
+type ProjectTemplate struct {
+	BaseModel
+	Title           string            `gorm:"not null" json:"title"`
+	Description     string            `json:"description"`
+	CreatedBy       uuid.UUID         `gorm:"not null;index" json:"created_by"`
+	SourceProjectID *uuid.UUID        `gorm:"index" json:"source_project_id,omitempty"`
+	GroupIDs        []uuid.UUID       `gorm:"serializer:json" json:"group_ids"`
+	Sections        []TemplateSection `gorm:"serializer:json" json:"sections"`
+}
+
+type TaskTemplate struct {
+	BaseModel
+	Title           string       `gorm:"not null" json:"title"`
+	CreatedBy       uuid.UUID    `gorm:"not null;index" json:"created_by"`
+	SourceProjectID *uuid.UUID   `gorm:"index" json:"source_project_id,omitempty"`
+	Task            TemplateTask `gorm:"serializer:json" json:"task"`
 }
 
 // ======= Секции пользователя =======
@@ -105,50 +318,168 @@ type TaskUser struct {
 // ======= История задач =======
 
 type TaskHistory struct {
-	TaskID    uuid.UUID `gorm:"not null" json:"task_id"`
-	UserID    uuid.UUID `gorm:"not null" json:"user_id"`
-	Action    string    `gorm:"not null" json:"action"`
-	Timestamp time.Time `gorm:"autoCreateTime:milli" json:"timestamp"`
+	ID        uuid.UUID     `gorm:"primaryKey;not null" json:"id"`
+	TaskID    uuid.UUID     `gorm:"not null;index" json:"task_id"` // Без внешнего ключа: история переживает удаление задачи
+	UserID    uuid.UUID     `gorm:"not null" json:"user_id"`
+	Action    string        `gorm:"not null" json:"action"`
+	Changes   []FieldChange `gorm:"serializer:json" json:"changes"`
+	Timestamp time.Time     `gorm:"autoCreateTime:milli;index" json:"timestamp"`
+}
+
+// FieldChange - значение поля задачи до и после изменения
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+func (history *TaskHistory) BeforeCreate(tx *gorm.DB) error {
+	history.ID = uuid.New()
+	return nil
 }
 
 // ======= Комментарии =======
 type Comment struct {
 	BaseModel
-	TaskID    uuid.UUID  `gorm:"not null" json:"task_id"`
-	Task      Task       `gorm:"foreignKey:TaskID;onDelete:CASCADE"`
-	UserID    uuid.UUID  `gorm:"not null" json:"user_id"`                        // Добавляем ID пользователя
-	User      User       `gorm:"foreignKey:UserID;onDelete:CASCADE" json:"user"` // Связь с пользователем
-	Body      string     `gorm:"not null" json:"body"`
-	CitateID  *uuid.UUID `json:"citate_id,omitempty"`
-	ReplyToID *uuid.UUID `json:"reply_to_id,omitempty"`
-	IsEdited  bool       `gorm:"default:false" json:"is_edited"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	TaskID    uuid.UUID         `gorm:"not null;index" json:"task_id"`
+	Task      Task              `gorm:"foreignKey:TaskID;onDelete:CASCADE" json:"-"`
+	UserID    uuid.UUID         `gorm:"not null" json:"user_id"`                        // Добавляем ID пользователя
+	User      User              `gorm:"foreignKey:UserID;onDelete:CASCADE" json:"user"` // Связь с пользователем
+	Body      string            `gorm:"not null" json:"body"`                           // У удалённого комментария пустой
+	CitateID  *uuid.UUID        `json:"citate_id,omitempty"`
+	ReplyToID *uuid.UUID        `gorm:"index" json:"reply_to_id,omitempty"`
+	IsEdited  bool              `gorm:"default:false" json:"is_edited"`
+	DeletedAt *time.Time        `json:"deleted_at,omitempty"` // Удалённый комментарий остаётся в ветке как заглушка
+	Replies   []Comment         `gorm:"-" json:"replies,omitempty"`
+	Reactions []ReactionSummary `gorm:"-" json:"reactions,omitempty"`
+}
+
+// CommentReaction - эмодзи-реакция пользователя, каждое эмодзи не больше одного раза
+type CommentReaction struct {
+	BaseModel
+	CommentID uuid.UUID `gorm:"not null;uniqueIndex:idx_comment_reaction" json:"comment_id"`
+	Comment   *Comment  `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID    uuid.UUID `gorm:"not null;uniqueIndex:idx_comment_reaction" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Emoji     string    `gorm:"not null;uniqueIndex:idx_comment_reaction" json:"emoji"`
+}
+
+// ReactionSummary - реакции одним эмодзи на комментарий
+type ReactionSummary struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// CommentRevision - текст комментария до очередной правки
+type CommentRevision struct {
+	BaseModel
+	CommentID uuid.UUID `gorm:"not null;index" json:"comment_id"`
+	Comment   *Comment  `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"-"`
+	Body      string    `gorm:"not null" json:"body"`
+	EditedBy  uuid.UUID `gorm:"not null" json:"edited_by"`
+}
+
+// ======= Упоминания =======
+
+// Где встретилось упоминание
+const (
+	MentionSourceComment = "comment"
+	MentionSourceTask    = "task" // Описание задачи
+)
+
+type Mention struct {
+	BaseModel
+	UserID     uuid.UUID  `gorm:"not null;uniqueIndex:idx_mention_source;index" json:"user_id"` // Кого упомянули
+	User       *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	AuthorID   uuid.UUID  `gorm:"not null" json:"author_id"`
+	Author     *User      `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE" json:"author,omitempty"`
+	TaskID     uuid.UUID  `gorm:"not null;index" json:"task_id"`
+	Task       *Task      `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"task,omitempty"`
+	SourceType string     `gorm:"not null" json:"source_type"`
+	SourceID   uuid.UUID  `gorm:"not null;uniqueIndex:idx_mention_source" json:"source_id"` // Комментарий или задача
+	ReadAt     *time.Time `json:"read_at,omitempty"`
 }
 
 // ======= Вложения (Attachments) =======
 
 type Attachment struct {
 	BaseModel
-	TaskID          uuid.UUID  `gorm:"not null" json:"task_id"`
-	Task            Task       `gorm:"foreignKey:TaskID;onDelete:CASCADE"`
-	UserID          uuid.UUID  `gorm:"not null" json:"user_id"`
-	User            User       `gorm:"foreignKey:UserID;onDelete:CASCADE"`
-	URL             string     `gorm:"not null" json:"url"`
-	Type            string     `gorm:"not null" json:"type"`
-	Size            int        `gorm:"not null" json:"size"`
-	LinkedTaskID    *uuid.UUID `json:"linked_task_id,omitempty"`
-	LinkedCommentID *uuid.UUID `json:"linked_comment_id,omitempty"`
+	TaskID          uuid.UUID             `gorm:"not null;index" json:"task_id"`
+	Task            *Task                 `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID          uuid.UUID             `gorm:"not null" json:"user_id"`
+	User            *User                 `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Name            string                `gorm:"not null" json:"name"` // Исходное имя файла
+	StorageKey      string                `gorm:"not null" json:"-"`    // Ключ объекта в хранилище
+	URL             string                `gorm:"not null" json:"url"`  // Ссылка на скачивание
+	Type            string                `gorm:"not null" json:"type"` // MIME-тип по содержимому файла
+	Size            int                   `gorm:"not null" json:"size"` // Размер в байтах
+	LinkedTaskID    *uuid.UUID            `json:"linked_task_id,omitempty"`
+	LinkedCommentID *uuid.UUID            `json:"linked_comment_id,omitempty"`
+	ThumbnailStatus string                `gorm:"not null;default:none" json:"thumbnail_status"`
+	Thumbnails      []AttachmentThumbnail `gorm:"foreignKey:AttachmentID;constraint:OnDelete:CASCADE" json:"thumbnails,omitempty"`
+}
+
+// Состояние генерации миниатюр вложения
+const (
+	ThumbnailNone    = "none" // Тип файла без превью
+	ThumbnailPending = "pending"
+	ThumbnailReady   = "ready"
+	ThumbnailFailed  = "failed"
+)
+
+// AttachmentThumbnail - уменьшенная копия изображения или первой страницы PDF
+type AttachmentThumbnail struct {
+	BaseModel
+	AttachmentID uuid.UUID `gorm:"not null;uniqueIndex:idx_attachment_thumbnail" json:"attachment_id"`
+	Size         string    `gorm:"not null;uniqueIndex:idx_attachment_thumbnail" json:"size"` // small, medium, large
+	Width        int       `gorm:"not null" json:"width"`
+	Height       int       `gorm:"not null" json:"height"`
+	Type         string    `gorm:"not null" json:"type"`
+	Bytes        int       `gorm:"not null" json:"bytes"`
+	URL          string    `gorm:"not null" json:"url"`
+	StorageKey   string    `gorm:"not null" json:"-"`
+}
+
+// ======= Календарные ленты =======
+
+// CalendarFeed - доступ к iCalendar-ленте сроков задач по секретному токену.
+// Без проекта лента содержит задачи, назначенные пользователю
+type CalendarFeed struct {
+	BaseModel
+	UserID    uuid.UUID  `gorm:"not null;index" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	ProjectID *uuid.UUID `gorm:"index" json:"project_id,omitempty"`
+	Project   *Project   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Token     string     `gorm:"not null;uniqueIndex" json:"-"`
+	URL       string     `gorm:"-" json:"url"` // Адрес ленты для подписки в календаре
 }
 
 // ======= Логи аудита (Audit Logs) =======
 
+// Действия, которые попадают в журнал аудита
+const (
+	AuditLogin          = "auth.login"
+	AuditLoginFailed    = "auth.login_failed"
+	AuditPasswordReset  = "auth.password_reset"
+	AuditUserCreated    = "user.created"
+	AuditUserUpdated    = "user.updated"
+	AuditRoleChanged    = "user.role_changed"
+	AuditUserDeleted    = "user.deleted"
+	AuditGroupMemberAdd = "group.member_added"
+	AuditProjectGrant   = "project.group_granted"
+	AuditTaskGrant      = "task.group_granted"
+)
+
 type AuditLog struct {
 	BaseModel
-	TaskID     uuid.UUID `gorm:"not null" json:"task_id"`
-	Body       string    `gorm:"not null" json:"body"`
-	ActionType string    `gorm:"not null" json:"action_type"`
-	EntityType string    `gorm:"not null" json:"entity_type"`
-	EntityID   uuid.UUID `gorm:"not null" json:"entity_id"`
+	ActorID    *uuid.UUID             `gorm:"index" json:"actor_id,omitempty"` // nil - действие без входа, например неудачный вход
+	ActionType string                 `gorm:"not null;index" json:"action_type"`
+	EntityType string                 `gorm:"not null" json:"entity_type"`
+	EntityID   *uuid.UUID             `gorm:"index" json:"entity_id,omitempty"`
+	IP         string                 `json:"ip"`
+	UserAgent  string                 `json:"user_agent"`
+	Details    map[string]interface{} `gorm:"serializer:json" json:"details,omitempty"`
 }
 
 // ======= Разрешения =======
@@ -161,6 +492,12 @@ type ProjectPermission struct {
 	Role      string    `gorm:"not null" json:"role"`
 }
 
+// Роли в ProjectPermission, которым можно менять настройки проекта
+const (
+	ProjectRoleOwner   = "owner"
+	ProjectRoleManager = "manager"
+)
+
 type RolePermission struct {
 	RoleID uuid.UUID `gorm:"not null" json:"role_id"`
 	UserID uuid.UUID `gorm:"not null" json:"user_id"`
@@ -0,0 +1,55 @@
+package response
+
+import (
+	"app/src/model"
+
+	"github.com/google/uuid"
+)
+
+type BoardAssignee struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Tasks  int       `json:"tasks"`
+}
+
+// BoardColumn - секция проекта с задачами в порядке доски.
+// OpenTasks - незавершённые задачи, именно они считаются в WIP-лимит
+type BoardColumn struct {
+	ID         uuid.UUID       `json:"id"`
+	Title      string          `json:"title"`
+	Order      int             `json:"order"`
+	WIPLimit   int             `json:"wip_limit"`
+	WIPStrict  bool            `json:"wip_strict"`
+	TotalTasks int             `json:"total_tasks"`
+	OpenTasks  int             `json:"open_tasks"`
+	OverLimit  bool            `json:"over_limit"`
+	Assignees  []BoardAssignee `json:"assignees"`
+	Tasks      []model.Task    `json:"tasks"`
+}
+
+// BoardCell - задачи дорожки в одной секции
+type BoardCell struct {
+	SectionID  uuid.UUID    `json:"section_id"`
+	TotalTasks int          `json:"total_tasks"`
+	OpenTasks  int          `json:"open_tasks"`
+	Tasks      []model.Task `json:"tasks"`
+}
+
+// BoardLane - горизонтальная дорожка доски. Пустой Key - задачи без значения поля
+type BoardLane struct {
+	Key        string      `json:"key"`
+	Title      string      `json:"title"`
+	TotalTasks int         `json:"total_tasks"`
+	OpenTasks  int         `json:"open_tasks"`
+	Cells      []BoardCell `json:"cells"`
+}
+
+// Board - доска проекта. С дорожками задачи лежат в ячейках дорожек,
+// а колонки содержат только итоги по секциям
+type Board struct {
+	ProjectID     uuid.UUID     `json:"project_id"`
+	FinalStatuses []string      `json:"final_statuses"`
+	Columns       []BoardColumn `json:"columns"`
+	Swimlane      string        `json:"swimlane,omitempty"`
+	Lanes         []BoardLane   `json:"lanes,omitempty"`
+}
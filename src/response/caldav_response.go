@@ -0,0 +1,17 @@
+package response
+
+import "github.com/google/uuid"
+
+// CalDAVCalendar - проект как коллекция CalDAV. CTag меняется при любом изменении задач проекта
+type CalDAVCalendar struct {
+	ProjectID uuid.UUID
+	Title     string
+	CTag      string
+}
+
+// CalDAVTodo - задача как ресурс VTODO, Data - готовый документ iCalendar
+type CalDAVTodo struct {
+	TaskID uuid.UUID
+	ETag   string
+	Data   []byte
+}
@@ -0,0 +1,16 @@
+package response
+
+import (
+	"app/src/model"
+
+	"github.com/google/uuid"
+)
+
+// CommentReactions - изменение реакций, оно же уходит в канал комментариев
+type CommentReactions struct {
+	CommentID uuid.UUID               `json:"comment_id"`
+	TaskID    uuid.UUID               `json:"task_id"`
+	UserID    uuid.UUID               `json:"user_id"`
+	Emoji     string                  `json:"emoji"`
+	Reactions []model.ReactionSummary `json:"reactions"` // Актуальные реакции комментария
+}
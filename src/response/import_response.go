@@ -0,0 +1,20 @@
+package response
+
+import "github.com/google/uuid"
+
+// TaskImportRow - результат проверки или импорта строки CSV
+type TaskImportRow struct {
+	Row    int        `json:"row"` // Номер строки в файле, заголовок - строка 1
+	Title  string     `json:"title"`
+	TaskID *uuid.UUID `json:"task_id,omitempty"`
+	Errors []string   `json:"errors,omitempty"`
+}
+
+type TaskImport struct {
+	DryRun  bool            `json:"dry_run"`
+	Total   int             `json:"total"`
+	Valid   int             `json:"valid"`
+	Failed  int             `json:"failed"`
+	Created int             `json:"created"`
+	Rows    []TaskImportRow `json:"rows"`
+}
@@ -1,6 +1,11 @@
 package response
 
-import "app/src/model"
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SuccessWithProject struct {
 	Code    int           `json:"code"`
@@ -56,6 +61,62 @@ type SuccessWithPaginateGroups struct {
 	TotalResults int64           `json:"total_results"`
 }
 
+type Workflow struct {
+	Statuses    []model.WorkflowStatus     `json:"statuses"`
+	Transitions []model.WorkflowTransition `json:"transitions"`
+}
+
+type TaskLinks struct {
+	Blocked bool             `json:"blocked"`
+	Links   []model.TaskLink `json:"links"`
+}
+
+type Recurrence struct {
+	model.TaskRecurrence
+	Upcoming []time.Time `json:"upcoming"`
+}
+
+type BulkTaskResult struct {
+	TaskID  uuid.UUID `json:"task_id"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+	Warning string    `json:"warning,omitempty"` // Например, превышен WIP-лимит секции
+}
+
+type BulkTasks struct {
+	Operation string           `json:"operation"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkTaskResult `json:"results"`
+	Tasks     []model.Task     `json:"tasks"` // Изменённые задачи, для delete - пусто
+}
+
+// Время в днях и задачах - в минутах, итоги - в часах
+type TimesheetDay struct {
+	Date     string `json:"date"`
+	Logged   int    `json:"logged"`
+	Expected int    `json:"expected"`
+}
+
+type TimesheetTask struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Title  string    `json:"title"`
+	Logged int       `json:"logged"`
+}
+
+type Timesheet struct {
+	UserID          uuid.UUID         `json:"user_id"`
+	From            string            `json:"from"`
+	To              string            `json:"to"`
+	WorkTime        int               `json:"work_time"`
+	LoggedHours     float64           `json:"logged_hours"`
+	ExpectedHours   float64           `json:"expected_hours"`
+	DifferenceHours float64           `json:"difference_hours"`
+	Days            []TimesheetDay    `json:"days"`
+	Tasks           []TimesheetTask   `json:"tasks"`
+	Entries         []model.TimeEntry `json:"entries"`
+}
+
 type Common struct {
 	Code    int    `json:"code"`
 	Status  string `json:"status"`
@@ -99,6 +160,14 @@ type SuccessWithPaginate[T any] struct {
 	TotalPages   int64  `json:"total_pages"`
 	TotalResults int64  `json:"total_results"`
 }
+type SuccessWithCursor[T any] struct {
+	Code       int    `json:"code"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Results    []T    `json:"results"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
 
 type ErrorDetails struct {
 	Code    int         `json:"code"`
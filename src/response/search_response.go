@@ -0,0 +1,14 @@
+package response
+
+import "github.com/google/uuid"
+
+type SearchResult struct {
+	Entity    string     `json:"entity"` // "task", "comment", "project"
+	ID        uuid.UUID  `json:"id"`
+	ProjectID uuid.UUID  `json:"project_id"`
+	TaskID    *uuid.UUID `json:"task_id,omitempty"` // Задача, к которой относится комментарий
+	Title     string     `json:"title"`
+	Snippet   string     `json:"snippet"` // HTML-экранирован, совпадения выделены тегом <mark>
+	Rank      float64    `json:"rank"`
+	Total     int64      `json:"-"`
+}
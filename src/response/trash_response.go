@@ -0,0 +1,16 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashItem struct {
+	Type      string     `json:"type"` // "task", "section", "project"
+	ID        uuid.UUID  `json:"id"`
+	Title     string     `json:"title"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	DeletedBy *uuid.UUID `json:"deleted_by,omitempty"`
+	PurgeAt   time.Time  `json:"purge_at" gorm:"-"` // Дата окончательного удаления
+}
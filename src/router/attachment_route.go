@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AttachmentRoutes(v1 fiber.Router, as service.AttachmentService, u service.UserService) {
+	attachmentController := controller.NewAttachmentController(as)
+
+	v1.Post("/tasks/:taskID/attachments", m.Auth(u), attachmentController.UploadAttachment)
+	v1.Get("/tasks/:taskID/attachments", m.Auth(u), attachmentController.GetTaskAttachments)
+	v1.Get("/attachments/:attachmentID/download", m.Auth(u), attachmentController.DownloadAttachment)
+	v1.Get("/attachments/:attachmentID/thumbnail", m.Auth(u), attachmentController.GetThumbnail)
+	v1.Delete("/attachments/:attachmentID", m.Auth(u), attachmentController.DeleteAttachment)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AuditRoutes(v1 fiber.Router, a service.AuditService, u service.UserService) {
+	auditController := controller.NewAuditController(a)
+
+	v1.Get("/audit-logs", m.Auth(u, "getAuditLogs"), auditController.GetAuditLogs)
+}
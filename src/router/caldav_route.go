@@ -0,0 +1,26 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CalDAVRoutes(v1 fiber.Router, cs service.CalDAVService, u service.UserService, a service.AuditService) {
+	caldavController := controller.NewCalDAVController(cs)
+
+	// CalDAV-клиенты умеют только Basic-авторизацию, входят по email и паролю
+	auth := m.BasicAuth(u, a)
+	v1.Options("/caldav*", caldavController.Options)
+	v1.Add("PROPFIND", "/caldav", auth, caldavController.PropfindRoot)
+	v1.Add("PROPFIND", "/caldav/principal", auth, caldavController.PropfindPrincipal)
+	v1.Add("PROPFIND", "/caldav/projects", auth, caldavController.PropfindHome)
+	v1.Add("PROPFIND", "/caldav/projects/:projectID", auth, caldavController.PropfindCalendar)
+	v1.Add("REPORT", "/caldav/projects/:projectID", auth, caldavController.Report)
+	v1.Add("PROPFIND", "/caldav/projects/:projectID/:taskID.ics", auth, caldavController.PropfindTodo)
+	v1.Get("/caldav/projects/:projectID/:taskID.ics", auth, caldavController.GetTodo)
+	v1.Put("/caldav/projects/:projectID/:taskID.ics", auth, caldavController.PutTodo)
+	v1.Delete("/caldav/projects/:projectID/:taskID.ics", auth, caldavController.DeleteTodo)
+}
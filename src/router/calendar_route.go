@@ -0,0 +1,20 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CalendarRoutes(v1 fiber.Router, cs service.CalendarService, u service.UserService) {
+	calendarController := controller.NewCalendarController(cs)
+
+	v1.Get("/calendar/feeds", m.Auth(u), calendarController.GetFeeds)
+	v1.Post("/calendar/feeds", m.Auth(u), calendarController.CreateFeed)
+	v1.Post("/calendar/feeds/:feedID/regenerate", m.Auth(u), calendarController.RegenerateToken)
+	v1.Delete("/calendar/feeds/:feedID", m.Auth(u), calendarController.DeleteFeed)
+	// Календарные приложения не умеют передавать заголовки, доступ даёт токен в адресе
+	v1.Get("/calendar/:token.ics", calendarController.GetFeed)
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CommentRoutes(v1 fiber.Router, cs service.CommentService, u service.UserService) {
+	commentController := controller.NewCommentController(cs)
+
+	v1.Post("/comments", m.Auth(u), commentController.CreateComment)
+	v1.Put("/comments/:commentID", m.Auth(u), commentController.UpdateComment)
+	v1.Delete("/comments/:commentID", m.Auth(u), commentController.DeleteComment)
+	v1.Get("/comments/:commentID/revisions", m.Auth(u), commentController.GetCommentRevisions)
+	v1.Post("/comments/:commentID/reactions", m.Auth(u), commentController.AddReaction)
+	v1.Delete("/comments/:commentID/reactions/:emoji", m.Auth(u), commentController.RemoveReaction)
+	v1.Get("/tasks/:taskID/comments", m.Auth(u), commentController.GetTaskComments)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CustomFieldRoutes(v1 fiber.Router, f service.CustomFieldService, u service.UserService) {
+	customFieldController := controller.NewCustomFieldController(f)
+
+	v1.Get("/projects/:projectID/custom-fields", m.Auth(u), customFieldController.GetCustomFields)
+	v1.Post("/projects/:projectID/custom-fields", m.Auth(u), customFieldController.CreateCustomField)
+	v1.Put("/custom-fields/:fieldID", m.Auth(u), customFieldController.UpdateCustomField)
+	v1.Delete("/custom-fields/:fieldID", m.Auth(u), customFieldController.DeleteCustomField)
+
+	v1.Put("/tasks/:taskID/custom-fields", m.Auth(u), customFieldController.SetTaskValues)
+}
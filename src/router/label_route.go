@@ -0,0 +1,22 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func LabelRoutes(v1 fiber.Router, l service.LabelService, u service.UserService) {
+	labelController := controller.NewLabelController(l)
+
+	v1.Get("/projects/:projectID/labels", m.Auth(u), labelController.GetLabels)
+	v1.Post("/projects/:projectID/labels", m.Auth(u), labelController.CreateLabel)
+	v1.Put("/labels/:labelID", m.Auth(u), labelController.UpdateLabel)
+	v1.Delete("/labels/:labelID", m.Auth(u), labelController.DeleteLabel)
+	v1.Post("/labels/:labelID/merge", m.Auth(u), labelController.MergeLabel)
+
+	v1.Post("/tasks/:taskID/labels/:labelID", m.Auth(u), labelController.AddTaskLabel)
+	v1.Delete("/tasks/:taskID/labels/:labelID", m.Auth(u), labelController.RemoveTaskLabel)
+}
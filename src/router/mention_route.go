@@ -0,0 +1,16 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MentionRoutes(v1 fiber.Router, ms service.MentionService, u service.UserService) {
+	mentionController := controller.NewMentionController(ms)
+
+	v1.Get("/me/mentions", m.Auth(u), mentionController.GetMyMentions)
+	v1.Put("/me/mentions/:mentionID/read", m.Auth(u), mentionController.MarkRead)
+}
@@ -8,28 +8,42 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func ProjectRoutes(v1 fiber.Router, t service.TaskService, u service.UserService) {
+func ProjectRoutes(v1 fiber.Router, t service.TaskService, u service.UserService, w service.WorkflowService) {
 	taskController := controller.NewTaskController(t)
+	workflowController := controller.NewWorkflowController(w)
 
 	// Проекты
 	v1.Post("/projects", m.Auth(u), taskController.CreateProject)
 	v1.Get("/projects", m.Auth(u), taskController.GetUserProjects)
+	v1.Delete("/projects/:projectID", m.Auth(u), taskController.DeleteProject)
 	v1.Get("/projects/:projectID/sections", m.Auth(u), taskController.GetSectionsByProject)
+	v1.Get("/projects/:projectID/board", m.Auth(u), taskController.GetBoard)
+	v1.Get("/projects/:projectID/export.csv", m.Auth(u), taskController.ExportTasksCSV)
+	v1.Post("/projects/:projectID/import", m.Auth(u), taskController.ImportTasksCSV)
 	v1.Post("/projects/add-group", m.Auth(u), taskController.AddGroupToProject)
+	v1.Get("/projects/:projectID/workflow", m.Auth(u), workflowController.GetWorkflow)
+	v1.Put("/projects/:projectID/workflow", m.Auth(u), workflowController.UpdateWorkflow)
 
 	// Секции
 	v1.Post("/projects/section", m.Auth(u), taskController.CreateSection)
 	v1.Delete("/sections/:sectionID", m.Auth(u), taskController.DeleteSection)
+	v1.Put("/sections/:sectionID/wip-limit", m.Auth(u), taskController.SetSectionWIPLimit)
 	v1.Get("/sections", m.Auth(u), taskController.GetSectionsByUser)
 
 	// Задачи
 	v1.Post("/tasks", m.Auth(u), taskController.CreateTask)
 	v1.Get("/tasks", m.Auth(u), taskController.GetTasks)
+	v1.Post("/tasks/bulk", m.Auth(u), taskController.BulkUpdateTasks)
 	v1.Get("/tasks/:taskID", m.Auth(u), taskController.GetTaskByID)
 	v1.Put("/tasks/:taskID", m.Auth(u), taskController.UpdateTaskTitleOrDescription)
 	v1.Put("/tasks/:taskID/reassign", m.Auth(u), taskController.ReassignTask)
+	v1.Put("/tasks/:taskID/status", m.Auth(u), taskController.UpdateTaskStatus)
 	v1.Delete("/tasks/:taskID", m.Auth(u), taskController.DeleteTask)
+	v1.Get("/tasks/:taskID/history", m.Auth(u), taskController.GetTaskHistory)
 	v1.Get("/tasks/:taskID/users", m.Auth(u), taskController.GetUsersWithAccess)
+	v1.Get("/tasks/:taskID/subtasks", m.Auth(u), taskController.GetSubtaskTree)
+	v1.Put("/tasks/:taskID/parent", m.Auth(u), taskController.MoveSubtask)
+	v1.Put("/tasks/:taskID/move", m.Auth(u), taskController.MoveTask)
 	v1.Post("/tasks/add-group", m.Auth(u), taskController.AddGroupToTask)
 
 	// Группы пользователей
@@ -37,7 +51,4 @@ func ProjectRoutes(v1 fiber.Router, t service.TaskService, u service.UserService
 	v1.Post("/user-groups/add-user", m.Auth(u), taskController.AddUserToGroup)
 	v1.Get("/user-groups", m.Auth(u), taskController.GetUserGroups)
 	v1.Post("/user-groups/users", m.Auth(u), taskController.GetUsersInGroup)
-
-	// Комментарии
-	v1.Post("/comments", m.Auth(u), taskController.CommentTask)
 }
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RecurrenceRoutes(v1 fiber.Router, r service.RecurrenceService, u service.UserService) {
+	recurrenceController := controller.NewRecurrenceController(r)
+
+	v1.Get("/tasks/:taskID/recurrence", m.Auth(u), recurrenceController.GetRecurrence)
+	v1.Put("/tasks/:taskID/recurrence", m.Auth(u), recurrenceController.SetRecurrence)
+	v1.Delete("/tasks/:taskID/recurrence", m.Auth(u), recurrenceController.StopRecurrence)
+	v1.Post("/tasks/:taskID/recurrence/exceptions", m.Auth(u), recurrenceController.AddException)
+}
@@ -2,8 +2,12 @@ package router
 
 import (
 	"app/src/config"
+	m "app/src/middleware"
+	"app/src/model"
 	"app/src/service"
+	"app/src/storage"
 	"app/src/validation"
+	"context"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
@@ -13,19 +17,59 @@ import (
 func Routes(app *fiber.App, db *gorm.DB) {
 	validate := validation.Validator()
 	redisClient := config.RedisClient() // Добавляем Redis
+	fileStorage, err := storage.New(context.Background())
+	if err != nil {
+		panic("Failed to set up storage: " + err.Error())
+	}
 
 	healthCheckService := service.NewHealthCheckService(db)
 	emailService := service.NewEmailService()
-	userService := service.NewUserService(db, validate)
+	auditService := service.NewAuditService(db, validate)
+	userService := service.NewUserService(db, validate, auditService)
 	tokenService := service.NewTokenService(db, validate, userService)
-	authService := service.NewAuthService(db, validate, userService, tokenService)
-	taskService := service.NewTaskService(db, validate, redisClient) // Передаём Redis-клиент
+	authService := service.NewAuthService(db, validate, userService, tokenService, auditService)
+	workflowService := service.NewWorkflowService(db, validate)
+	taskLinkService := service.NewTaskLinkService(db, validate, workflowService)
+	recurrenceService := service.NewRecurrenceService(db, validate, workflowService)
+	templateService := service.NewTemplateService(db, validate, workflowService)
+	customFieldService := service.NewCustomFieldService(db, validate, redisClient)
+	mentionService := service.NewMentionService(db, validate, redisClient, emailService)
+	taskService := service.NewTaskService(
+		db, validate, redisClient, workflowService, taskLinkService, recurrenceService, templateService,
+		customFieldService, auditService, mentionService,
+	) // Передаём Redis-клиент
+	searchService := service.NewSearchService(db, validate)
+	timeEntryService := service.NewTimeEntryService(db, validate)
+	labelService := service.NewLabelService(db, validate, redisClient)
+	trashService := service.NewTrashService(db, validate, redisClient, fileStorage)
+	commentService := service.NewCommentService(db, validate, redisClient, mentionService)
+	attachmentService := service.NewAttachmentService(db, validate, redisClient, fileStorage)
+	calendarService := service.NewCalendarService(db, validate, workflowService)
+	caldavService := service.NewCalDAVService(db, taskService, workflowService)
 
 	v1 := app.Group("/v1")
 	HealthCheckRoutes(v1, healthCheckService)
 	AuthRoutes(v1, authService, userService, tokenService, emailService)
-	ProjectRoutes(v1, taskService, userService)
+	ProjectRoutes(v1, taskService, userService, workflowService)
 	UserRoutes(v1, userService, tokenService, taskService)
+	TaskLinkRoutes(v1, taskLinkService, userService)
+	SearchRoutes(v1, searchService, userService)
+	RecurrenceRoutes(v1, recurrenceService, userService)
+	TimeEntryRoutes(v1, timeEntryService, userService)
+	TemplateRoutes(v1, templateService, userService)
+	LabelRoutes(v1, labelService, userService)
+	CustomFieldRoutes(v1, customFieldService, userService)
+	AuditRoutes(v1, auditService, userService)
+	TrashRoutes(v1, trashService, userService)
+	CommentRoutes(v1, commentService, userService)
+	MentionRoutes(v1, mentionService, userService)
+	AttachmentRoutes(v1, attachmentService, userService)
+	CalendarRoutes(v1, calendarService, userService)
+	CalDAVRoutes(v1, caldavService, userService, auditService)
+	// Клиенты ищут CalDAV-сервер по адресу из RFC 6764
+	app.All("/.well-known/caldav", func(c *fiber.Ctx) error {
+		return c.Redirect("/v1/caldav/", fiber.StatusMovedPermanently)
+	})
 
 	// Настроим WebSocket
 	app.Use("/ws", func(c *fiber.Ctx) error {
@@ -45,6 +89,11 @@ func Routes(app *fiber.App, db *gorm.DB) {
 	app.Get("/ws/projects", websocket.New(func(c *websocket.Conn) {
 		taskService.HandleProjectUpdates(c)
 	}))
+	// Личные уведомления, пользователь определяется по токену до апгрейда
+	app.Get("/ws/notifications", m.Auth(userService), websocket.New(func(c *websocket.Conn) {
+		user, _ := c.Locals("user").(*model.User)
+		mentionService.HandleNotifications(c, user.ID)
+	}))
 	if !config.IsProd {
 		DocsRoutes(v1)
 	}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SearchRoutes(v1 fiber.Router, s service.SearchService, u service.UserService) {
+	searchController := controller.NewSearchController(s)
+
+	v1.Get("/search", m.Auth(u), searchController.Search)
+}
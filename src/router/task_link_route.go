@@ -0,0 +1,17 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TaskLinkRoutes(v1 fiber.Router, l service.TaskLinkService, u service.UserService) {
+	taskLinkController := controller.NewTaskLinkController(l)
+
+	v1.Get("/tasks/:taskID/links", m.Auth(u), taskLinkController.GetLinks)
+	v1.Post("/tasks/:taskID/links", m.Auth(u), taskLinkController.CreateLink)
+	v1.Delete("/tasks/:taskID/links/:linkID", m.Auth(u), taskLinkController.DeleteLink)
+}
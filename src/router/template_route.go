@@ -0,0 +1,23 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TemplateRoutes(v1 fiber.Router, t service.TemplateService, u service.UserService) {
+	templateController := controller.NewTemplateController(t)
+
+	v1.Post("/projects/:projectID/template", m.Auth(u), templateController.CreateProjectTemplate)
+	v1.Get("/templates/projects", m.Auth(u), templateController.GetProjectTemplates)
+	v1.Get("/templates/projects/:templateID", m.Auth(u), templateController.GetProjectTemplate)
+	v1.Delete("/templates/projects/:templateID", m.Auth(u), templateController.DeleteProjectTemplate)
+
+	v1.Post("/tasks/:taskID/template", m.Auth(u), templateController.CreateTaskTemplate)
+	v1.Get("/templates/tasks", m.Auth(u), templateController.GetTaskTemplates)
+	v1.Delete("/templates/tasks/:templateID", m.Auth(u), templateController.DeleteTaskTemplate)
+	v1.Post("/templates/tasks/:templateID/instantiate", m.Auth(u), templateController.InstantiateTaskTemplate)
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TimeEntryRoutes(v1 fiber.Router, t service.TimeEntryService, u service.UserService) {
+	timeEntryController := controller.NewTimeEntryController(t)
+
+	v1.Post("/tasks/:taskID/timer", m.Auth(u), timeEntryController.StartTimer)
+	v1.Get("/timer", m.Auth(u), timeEntryController.GetRunningTimer)
+	v1.Post("/timer/stop", m.Auth(u), timeEntryController.StopTimer)
+
+	v1.Get("/tasks/:taskID/time-entries", m.Auth(u), timeEntryController.GetTaskEntries)
+	v1.Post("/tasks/:taskID/time-entries", m.Auth(u), timeEntryController.CreateEntry)
+	v1.Put("/time-entries/:entryID", m.Auth(u), timeEntryController.UpdateEntry)
+	v1.Delete("/time-entries/:entryID", m.Auth(u), timeEntryController.DeleteEntry)
+
+	v1.Get("/timesheets", m.Auth(u), timeEntryController.GetTimesheet)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TrashRoutes(v1 fiber.Router, t service.TrashService, u service.UserService) {
+	trashController := controller.NewTrashController(t)
+
+	v1.Get("/projects/:projectID/trash", m.Auth(u), trashController.GetProjectTrash)
+	v1.Get("/trash/projects", m.Auth(u), trashController.GetDeletedProjects)
+
+	v1.Post("/tasks/:taskID/restore", m.Auth(u), trashController.RestoreTask)
+	v1.Post("/sections/:sectionID/restore", m.Auth(u), trashController.RestoreSection)
+	v1.Post("/projects/:projectID/restore", m.Auth(u), trashController.RestoreProject)
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/storage"
+	"app/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AttachmentService interface {
+	UploadAttachment(c *fiber.Ctx, taskID uuid.UUID, file *multipart.FileHeader, commentID *uuid.UUID, userID uuid.UUID) (*model.Attachment, error)
+	GetTaskAttachments(c *fiber.Ctx, taskID, userID uuid.UUID) ([]model.Attachment, error)
+	DownloadAttachment(c *fiber.Ctx, attachmentID, userID uuid.UUID) (*model.Attachment, io.ReadCloser, error)
+	DeleteAttachment(c *fiber.Ctx, attachmentID, userID uuid.UUID) error
+	GetThumbnail(c *fiber.Ctx, attachmentID uuid.UUID, size string, userID uuid.UUID) (*model.AttachmentThumbnail, io.ReadCloser, error)
+}
+
+type attachmentService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+	Redis    *redis.Client
+	Storage  storage.Storage
+}
+
+func NewAttachmentService(
+	db *gorm.DB, validate *validator.Validate, redisClient *redis.Client, fileStorage storage.Storage,
+) AttachmentService {
+	return &attachmentService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+		Redis:    redisClient,
+		Storage:  fileStorage,
+	}
+}
+
+const maxAttachmentNameLen = 255
+
+func (s *attachmentService) publish(action string, attachment *model.Attachment) {
+	err := publishMessage(context.Background(), s.Redis, taskUpdatesChannel, WSMessage{
+		Entity:    "attachment",
+		Action:    action,
+		Data:      attachment,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to publish attachment %s: %v", action, err)
+	}
+}
+
+// attachmentName оставляет от имени файла только последний элемент пути
+func attachmentName(filename string) string {
+	name := filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if name == "." || name == "/" {
+		name = "file"
+	}
+	if runes := []rune(name); len(runes) > maxAttachmentNameLen {
+		name = string(runes[:maxAttachmentNameLen])
+	}
+	return name
+}
+
+// accessibleAttachment возвращает вложение живой задачи, доступной пользователю
+func (s *attachmentService) accessibleAttachment(db *gorm.DB, attachmentID, userID uuid.UUID) (*model.Attachment, error) {
+	var attachment model.Attachment
+	if err := db.First(&attachment, "id = ?", attachmentID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Attachment not found")
+	}
+	if _, err := findAccessibleTask(db, attachment.TaskID, userID); err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Attachment not found")
+	}
+	return &attachment, nil
+}
+
+// UploadAttachment сохраняет файл в хранилище и создаёт запись о нём.
+// Тип определяется по содержимому файла, а не по заголовку клиента
+func (s *attachmentService) UploadAttachment(
+	c *fiber.Ctx, taskID uuid.UUID, file *multipart.FileHeader, commentID *uuid.UUID, userID uuid.UUID,
+) (*model.Attachment, error) {
+	if file.Size == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "File is empty")
+	}
+	if file.Size > int64(config.AttachmentMaxSize) {
+		return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("File exceeds the %d MB limit", config.AttachmentMaxSize/(1024*1024)))
+	}
+
+	db := s.DB.WithContext(c.Context())
+	task, err := findAccessibleTask(db, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTarget(db, commentID, task.ID, "comment_id"); err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+	defer src.Close()
+
+	detected, err := mimetype.DetectReader(src)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+	if !utils.MatchMIME(detected.String(), config.AttachmentTypes) {
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("File type %s is not allowed", strings.Split(detected.String(), ";")[0]))
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("tasks/%s/%s", task.ID, uuid.New())
+	if err := s.Storage.Put(c.Context(), key, src, file.Size, detected.String()); err != nil {
+		s.Log.Errorf("Failed to store attachment: %+v", err)
+		return nil, err
+	}
+
+	attachment := &model.Attachment{
+		TaskID:          task.ID,
+		UserID:          userID,
+		Name:            attachmentName(file.Filename),
+		StorageKey:      key,
+		Type:            detected.String(),
+		Size:            int(file.Size),
+		LinkedCommentID: commentID,
+		ThumbnailStatus: model.ThumbnailNone,
+	}
+	if hasThumbnails(attachment.Type) {
+		attachment.ThumbnailStatus = model.ThumbnailPending
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attachment).Error; err != nil {
+			return err
+		}
+		attachment.URL = fmt.Sprintf("/v1/attachments/%s/download", attachment.ID)
+		return tx.Model(attachment).Update("url", attachment.URL).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to create attachment: %+v", err)
+		if err := s.Storage.Delete(context.Background(), key); err != nil {
+			s.Log.Errorf("Failed to remove orphaned attachment %s: %v", key, err)
+		}
+		return nil, err
+	}
+
+	go s.publish("created", attachment)
+	if attachment.ThumbnailStatus == model.ThumbnailPending {
+		go s.generateThumbnails(*attachment)
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) GetTaskAttachments(c *fiber.Ctx, taskID, userID uuid.UUID) ([]model.Attachment, error) {
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleTask(db, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	var attachments []model.Attachment
+	if err := db.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "email")
+		}).
+		Preload("Thumbnails", func(db *gorm.DB) *gorm.DB {
+			return db.Order("width")
+		}).
+		Where("task_id = ?", taskID).
+		Order("created_at, id").
+		Find(&attachments).Error; err != nil {
+		s.Log.Errorf("Failed to get attachments: %+v", err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DownloadAttachment открывает файл вложения на чтение. Закрывает поток вызывающий
+func (s *attachmentService) DownloadAttachment(
+	c *fiber.Ctx, attachmentID, userID uuid.UUID,
+) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.accessibleAttachment(s.DB.WithContext(c.Context()), attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Поток читается после выхода из обработчика, поэтому контекст запроса не подходит
+	body, err := s.Storage.Get(context.Background(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.Log.Errorf("Attachment %s has no stored file", attachment.ID)
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Attachment file not found")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to open attachment: %+v", err)
+		return nil, nil, err
+	}
+	return attachment, body, nil
+}
+
+// DeleteAttachment удаляет вложение. Удалить может только тот, кто его загрузил
+func (s *attachmentService) DeleteAttachment(c *fiber.Ctx, attachmentID, userID uuid.UUID) error {
+	db := s.DB.WithContext(c.Context())
+	attachment, err := s.accessibleAttachment(db, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+	if attachment.UserID != userID {
+		return fiber.NewError(fiber.StatusForbidden, "Only the uploader can delete an attachment")
+	}
+
+	var thumbnails []model.AttachmentThumbnail
+	if err := db.Where("attachment_id = ?", attachment.ID).Find(&thumbnails).Error; err != nil {
+		return err
+	}
+	// Миниатюры удаляются каскадом
+	if err := db.Delete(attachment).Error; err != nil {
+		s.Log.Errorf("Failed to delete attachment: %+v", err)
+		return err
+	}
+	// Запись уже удалена, поэтому оставшиеся файлы только логируются
+	if err := s.Storage.Delete(c.Context(), attachment.StorageKey); err != nil {
+		s.Log.Errorf("Failed to remove attachment file %s: %v", attachment.StorageKey, err)
+	}
+	s.removeThumbnails(thumbnails)
+
+	go s.publish("deleted", attachment)
+	return nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/storage"
+	"app/src/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Размеры миниатюр - длина большей стороны в пикселях
+var thumbnailSizes = []struct {
+	Name    string
+	MaxSide int
+}{
+	{"small", 64},
+	{"medium", 256},
+	{"large", 1024},
+}
+
+const (
+	defaultThumbnailSize = "medium"
+	thumbnailTimeout     = 2 * time.Minute
+)
+
+// thumbnailSlots ограничивает число одновременных генераций, декодирование изображений тяжёлое
+var thumbnailSlots = make(chan struct{}, 2)
+
+// isThumbnailSize проверяет, что миниатюра такого размера генерируется
+func isThumbnailSize(size string) bool {
+	for _, s := range thumbnailSizes {
+		if s.Name == size {
+			return true
+		}
+	}
+	return false
+}
+
+// hasThumbnails - можно ли построить превью для файла такого типа
+func hasThumbnails(mimeType string) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp", "image/bmp", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// sourceImage загружает вложение и декодирует его, для PDF рендерится первая страница
+func (s *attachmentService) sourceImage(ctx context.Context, attachment *model.Attachment) (image.Image, string, error) {
+	body, err := s.Storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, int64(config.AttachmentMaxSize)+1))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if strings.HasPrefix(attachment.Type, "application/pdf") {
+		img, err := utils.RenderPDFPage(ctx, data, thumbnailSizes[len(thumbnailSizes)-1].MaxSide)
+		return img, "pdf", err
+	}
+	return utils.DecodeImage(data)
+}
+
+// generateThumbnails строит миниатюры всех размеров и сохраняет их рядом с вложением.
+// Запускается в фоне после загрузки, результат уходит в канал задач
+func (s *attachmentService) generateThumbnails(attachment model.Attachment) {
+	thumbnailSlots <- struct{}{}
+	defer func() { <-thumbnailSlots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), thumbnailTimeout)
+	defer cancel()
+
+	thumbnails, err := s.buildThumbnails(ctx, &attachment)
+	status := model.ThumbnailReady
+	switch {
+	case errors.Is(err, utils.ErrPDFUnsupported):
+		status = model.ThumbnailNone
+		s.Log.Warnf("Skipping PDF preview for attachment %s: %v", attachment.ID, err)
+	case err != nil:
+		status = model.ThumbnailFailed
+		s.Log.Errorf("Failed to generate thumbnails for attachment %s: %+v", attachment.ID, err)
+	}
+
+	db := s.DB.WithContext(ctx)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if len(thumbnails) > 0 {
+			if err := tx.Create(&thumbnails).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&model.Attachment{}).Where("id = ?", attachment.ID).Update("thumbnail_status", status)
+		if result.Error == nil && result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return result.Error
+	})
+	if err != nil {
+		// Вложение удалили, пока строились миниатюры, или запись не удалась
+		s.removeThumbnails(thumbnails)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.Log.Errorf("Failed to save thumbnails: %+v", err)
+		}
+		return
+	}
+
+	attachment.ThumbnailStatus = status
+	attachment.Thumbnails = thumbnails
+	s.publish("thumbnails_"+status, &attachment)
+}
+
+// buildThumbnails кодирует и сохраняет миниатюры в хранилище. При ошибке уже сохранённые удаляются
+func (s *attachmentService) buildThumbnails(ctx context.Context, attachment *model.Attachment) ([]model.AttachmentThumbnail, error) {
+	src, format, err := s.sourceImage(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make([]model.AttachmentThumbnail, 0, len(thumbnailSizes))
+	for _, size := range thumbnailSizes {
+		img := utils.ResizeToFit(src, size.MaxSide)
+		var buf bytes.Buffer
+		contentType, err := utils.EncodeThumbnail(&buf, img, format)
+		if err != nil {
+			s.removeThumbnails(thumbnails)
+			return nil, err
+		}
+
+		thumbnail := model.AttachmentThumbnail{
+			AttachmentID: attachment.ID,
+			Size:         size.Name,
+			Width:        img.Bounds().Dx(),
+			Height:       img.Bounds().Dy(),
+			Type:         contentType,
+			Bytes:        buf.Len(),
+			URL:          fmt.Sprintf("/v1/attachments/%s/thumbnail?size=%s", attachment.ID, size.Name),
+			StorageKey:   attachment.StorageKey + "-" + size.Name,
+		}
+		if err := s.Storage.Put(ctx, thumbnail.StorageKey, &buf, int64(thumbnail.Bytes), contentType); err != nil {
+			s.removeThumbnails(thumbnails)
+			return nil, err
+		}
+		thumbnails = append(thumbnails, thumbnail)
+	}
+	return thumbnails, nil
+}
+
+func (s *attachmentService) removeThumbnails(thumbnails []model.AttachmentThumbnail) {
+	for _, thumbnail := range thumbnails {
+		if err := s.Storage.Delete(context.Background(), thumbnail.StorageKey); err != nil {
+			s.Log.Errorf("Failed to remove thumbnail %s: %v", thumbnail.StorageKey, err)
+		}
+	}
+}
+
+// GetThumbnail открывает миниатюру вложения на чтение. Закрывает поток вызывающий
+func (s *attachmentService) GetThumbnail(
+	c *fiber.Ctx, attachmentID uuid.UUID, size string, userID uuid.UUID,
+) (*model.AttachmentThumbnail, io.ReadCloser, error) {
+	if size == "" {
+		size = defaultThumbnailSize
+	}
+	if !isThumbnailSize(size) {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Unknown thumbnail size")
+	}
+
+	db := s.DB.WithContext(c.Context())
+	attachment, err := s.accessibleAttachment(db, attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.ThumbnailStatus == model.ThumbnailPending {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Thumbnail is not ready yet")
+	}
+
+	var thumbnail model.AttachmentThumbnail
+	if err := db.First(&thumbnail, "attachment_id = ? AND size = ?", attachment.ID, size).Error; err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Attachment has no thumbnail")
+	}
+
+	body, err := s.Storage.Get(context.Background(), thumbnail.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Attachment has no thumbnail")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to open thumbnail: %+v", err)
+		return nil, nil, err
+	}
+	return &thumbnail, body, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AuditService interface {
+	Record(c *fiber.Ctx, entry *model.AuditLog)
+	GetAuditLogs(c *fiber.Ctx, params *validation.QueryAuditLog) ([]model.AuditLog, string, error)
+	ExportAuditLogs(c *fiber.Ctx, params *validation.QueryAuditLog) (func(w *bufio.Writer), error)
+}
+
+type auditService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewAuditService(db *gorm.DB, validate *validator.Validate) AuditService {
+	return &auditService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+	}
+}
+
+const (
+	defaultAuditLimit = 50
+	auditExportBatch  = 500
+)
+
+// Record дописывает в запись IP и User-Agent запроса и сохраняет её.
+// Автор по умолчанию - авторизованный пользователь запроса.
+// Ошибка записи не прерывает основное действие, она только логируется
+func (s *auditService) Record(c *fiber.Ctx, entry *model.AuditLog) {
+	if entry.ActorID == nil {
+		if user, ok := c.Locals("user").(*model.User); ok && user != nil {
+			entry.ActorID = &user.ID
+		}
+	}
+	entry.IP = c.IP()
+	entry.UserAgent = c.Get(fiber.HeaderUserAgent)
+
+	if err := s.DB.WithContext(c.Context()).Create(entry).Error; err != nil {
+		s.Log.Errorf("Failed to write audit log %s: %+v", entry.ActionType, err)
+	}
+}
+
+// filter собирает условия выборки из параметров запроса
+func (s *auditService) filter(params *validation.QueryAuditLog) (func(db *gorm.DB) *gorm.DB, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, err
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if params.ActorID != "" {
+			db = db.Where("actor_id = ?", params.ActorID)
+		}
+		if params.Action != "" {
+			db = db.Where("action_type IN ?", strings.Split(params.Action, ","))
+		}
+		if params.EntityType != "" {
+			db = db.Where("entity_type = ?", params.EntityType)
+		}
+		if params.EntityID != "" {
+			db = db.Where("entity_id = ?", params.EntityID)
+		}
+		if params.IP != "" {
+			db = db.Where("ip = ?", params.IP)
+		}
+		if params.From != "" {
+			from, _ := time.Parse(time.DateOnly, params.From)
+			db = db.Where("created_at >= ?", from)
+		}
+		if params.To != "" {
+			to, _ := time.Parse(time.DateOnly, params.To)
+			db = db.Where("created_at < ?", to.AddDate(0, 0, 1))
+		}
+		return db
+	}, nil
+}
+
+// GetAuditLogs возвращает записи от новых к старым с курсорной пагинацией
+func (s *auditService) GetAuditLogs(
+	c *fiber.Ctx, params *validation.QueryAuditLog,
+) ([]model.AuditLog, string, error) {
+	filter, err := s.filter(params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := s.DB.WithContext(c.Context()).Scopes(filter)
+	if params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		value, err := cursorValue("time", cursor.Value)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		query = query.Where("(created_at < @value OR (created_at = @value AND id < @id))",
+			sql.Named("value", value), sql.Named("id", cursor.ID))
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+
+	var logs []model.AuditLog
+	if err := query.Order("created_at DESC, id DESC").Limit(limit + 1).Find(&logs).Error; err != nil {
+		s.Log.Errorf("Failed to get audit logs: %+v", err)
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(logs) > limit {
+		logs = logs[:limit]
+		last := logs[limit-1]
+		encoded, err := utils.EncodeCursor(last.CreatedAt, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		nextCursor = encoded
+	}
+	return logs, nextCursor, nil
+}
+
+// ExportAuditLogs возвращает функцию, которая пишет все подходящие записи в NDJSON
+// от старых к новым. Записи читаются пачками, чтобы не держать выгрузку в памяти
+func (s *auditService) ExportAuditLogs(
+	c *fiber.Ctx, params *validation.QueryAuditLog,
+) (func(w *bufio.Writer), error) {
+	filter, err := s.filter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Поток пишется после выхода из обработчика, контекст запроса к этому моменту уже недоступен
+	db := s.DB.WithContext(context.Background())
+	return func(w *bufio.Writer) {
+		encoder := json.NewEncoder(w)
+		var last *model.AuditLog
+		for {
+			query := db.Scopes(filter)
+			if last != nil {
+				query = query.Where("(created_at > @value OR (created_at = @value AND id > @id))",
+					sql.Named("value", last.CreatedAt), sql.Named("id", last.ID))
+			}
+
+			var batch []model.AuditLog
+			if err := query.Order("created_at, id").Limit(auditExportBatch).Find(&batch).Error; err != nil {
+				s.Log.Errorf("Failed to export audit logs: %+v", err)
+				return
+			}
+			for i := range batch {
+				if err := encoder.Encode(&batch[i]); err != nil {
+					return
+				}
+			}
+			// Ошибка записи - клиент отключился
+			if err := w.Flush(); err != nil || len(batch) < auditExportBatch {
+				return
+			}
+			last = &batch[len(batch)-1]
+		}
+	}, nil
+}
@@ -31,10 +31,12 @@ type authService struct {
 	Validate     *validator.Validate
 	UserService  UserService
 	TokenService TokenService
+	AuditService AuditService
 }
 
 func NewAuthService(
 	db *gorm.DB, validate *validator.Validate, userService UserService, tokenService TokenService,
+	auditService AuditService,
 ) AuthService {
 	return &authService{
 		Log:          utils.Log,
@@ -42,6 +44,7 @@ func NewAuthService(
 		Validate:     validate,
 		UserService:  userService,
 		TokenService: tokenService,
+		AuditService: auditService,
 	}
 }
 func (s *authService) Register(c *fiber.Ctx, req *validation.Register) (*model.User, error) {
@@ -95,13 +98,30 @@ func (s *authService) Login(c *fiber.Ctx, req *validation.Login) (*model.User, e
 
 	user, err := s.UserService.GetUserByEmail(c, req.Email)
 	if err != nil {
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditLoginFailed,
+			EntityType: "user",
+			Details:    map[string]interface{}{"email": req.Email},
+		})
 		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
 	}
 
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditLoginFailed,
+			EntityType: "user",
+			EntityID:   &user.ID,
+			Details:    map[string]interface{}{"email": req.Email},
+		})
 		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
 	}
 
+	s.AuditService.Record(c, &model.AuditLog{
+		ActorID:    &user.ID,
+		ActionType: model.AuditLogin,
+		EntityType: "user",
+		EntityID:   &user.ID,
+	})
 	return user, nil
 }
 
@@ -166,6 +186,12 @@ func (s *authService) ResetPassword(c *fiber.Ctx, query *validation.Token, req *
 		return errToken
 	}
 
+	s.AuditService.Record(c, &model.AuditLog{
+		ActorID:    &user.ID,
+		ActionType: model.AuditPasswordReset,
+		EntityType: "user",
+		EntityID:   &user.ID,
+	})
 	return nil
 }
 
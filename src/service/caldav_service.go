@@ -0,0 +1,240 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CalDAVService interface {
+	GetCalendars(c *fiber.Ctx, userID uuid.UUID) ([]response.CalDAVCalendar, error)
+	GetCalendar(c *fiber.Ctx, projectID, userID uuid.UUID) (*response.CalDAVCalendar, error)
+	GetTodos(c *fiber.Ctx, projectID, userID uuid.UUID, taskIDs []uuid.UUID) ([]response.CalDAVTodo, error)
+	PutTodo(c *fiber.Ctx, projectID, taskID, userID uuid.UUID, data []byte, ifMatch string) (string, error)
+	DeleteTodo(c *fiber.Ctx, projectID, taskID, userID uuid.UUID, ifMatch string) error
+}
+
+type caldavService struct {
+	Log             *logrus.Logger
+	DB              *gorm.DB
+	TaskService     TaskService
+	WorkflowService WorkflowService
+}
+
+func NewCalDAVService(db *gorm.DB, taskService TaskService, workflowService WorkflowService) CalDAVService {
+	return &caldavService{
+		Log:             utils.Log,
+		DB:              db,
+		TaskService:     taskService,
+		WorkflowService: workflowService,
+	}
+}
+
+const caldavProdID = "-//workmanager//Tasks//EN"
+
+// calendarVersion - сводка по задачам проекта для CTag. Задачи в корзине тоже учитываются,
+// поэтому удаление и восстановление меняют версию, а окончательная очистка меняет количество
+type calendarVersion struct {
+	ProjectID uuid.UUID
+	Count     int64
+	Updated   *time.Time
+	Deleted   *time.Time
+}
+
+func (v *calendarVersion) ctag() string {
+	var updated, deleted int64
+	if v.Updated != nil {
+		updated = v.Updated.UnixMicro()
+	}
+	if v.Deleted != nil {
+		deleted = v.Deleted.UnixMicro()
+	}
+	return fmt.Sprintf(`"%d-%x-%x"`, v.Count, updated, deleted)
+}
+
+func (s *caldavService) calendars(db *gorm.DB, projects []model.Project) ([]response.CalDAVCalendar, error) {
+	ids := make([]uuid.UUID, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+	var versions []calendarVersion
+	if len(ids) > 0 {
+		if err := db.Table("tasks").
+			Select("project_id, COUNT(*) AS count, MAX(updated_at) AS updated, MAX(deleted_at) AS deleted").
+			Where("project_id IN ?", ids).
+			Group("project_id").
+			Scan(&versions).Error; err != nil {
+			return nil, err
+		}
+	}
+	byProject := make(map[uuid.UUID]*calendarVersion, len(versions))
+	for i := range versions {
+		byProject[versions[i].ProjectID] = &versions[i]
+	}
+
+	calendars := make([]response.CalDAVCalendar, len(projects))
+	for i := range projects {
+		version := byProject[projects[i].ID]
+		if version == nil {
+			version = &calendarVersion{}
+		}
+		calendars[i] = response.CalDAVCalendar{
+			ProjectID: projects[i].ID,
+			Title:     projects[i].Title,
+			CTag:      version.ctag(),
+		}
+	}
+	return calendars, nil
+}
+
+// GetCalendars возвращает проекты пользователя как календари задач
+func (s *caldavService) GetCalendars(c *fiber.Ctx, userID uuid.UUID) ([]response.CalDAVCalendar, error) {
+	db := s.DB.WithContext(c.Context())
+	var projects []model.Project
+	if err := db.
+		Where("EXISTS (SELECT 1 FROM project_users pu WHERE pu.project_id = projects.id AND pu.user_id = ?)", userID).
+		Order("created_at, id").
+		Find(&projects).Error; err != nil {
+		s.Log.Errorf("Failed to get CalDAV calendars: %+v", err)
+		return nil, err
+	}
+	return s.calendars(db, projects)
+}
+
+func (s *caldavService) GetCalendar(c *fiber.Ctx, projectID, userID uuid.UUID) (*response.CalDAVCalendar, error) {
+	db := s.DB.WithContext(c.Context())
+	project, err := findAccessibleProject(db, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	calendars, err := s.calendars(db, []model.Project{*project})
+	if err != nil {
+		return nil, err
+	}
+	return &calendars[0], nil
+}
+
+// GetTodos возвращает задачи проекта как VTODO. taskIDs == nil - все задачи проекта
+func (s *caldavService) GetTodos(
+	c *fiber.Ctx, projectID, userID uuid.UUID, taskIDs []uuid.UUID,
+) ([]response.CalDAVTodo, error) {
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleProject(db, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	query := db.Where("project_id = ?", projectID)
+	if taskIDs != nil {
+		query = query.Where("id IN ?", taskIDs)
+	}
+	var tasks []model.Task
+	if err := query.Order("created_at, id").Find(&tasks).Error; err != nil {
+		s.Log.Errorf("Failed to get CalDAV todos: %+v", err)
+		return nil, err
+	}
+
+	final, err := s.WorkflowService.FinalStatuses(db, projectID)
+	if err != nil {
+		return nil, err
+	}
+	todos := make([]response.CalDAVTodo, len(tasks))
+	for i := range tasks {
+		todos[i] = response.CalDAVTodo{
+			TaskID: tasks[i].ID,
+			ETag:   taskETag(&tasks[i]),
+			Data:   renderTodo(&tasks[i], slices.Contains(final, tasks[i].Status)),
+		}
+	}
+	return todos, nil
+}
+
+// renderTodo собирает ресурс задачи. DTSTAMP берётся из updated_at,
+// чтобы содержимое не менялось без смены ETag
+func renderTodo(task *model.Task, done bool) []byte {
+	var w utils.ICalWriter
+	w.Line("BEGIN", "VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", caldavProdID)
+	writeTaskComponent(&w, task, done, true, task.UpdatedAt)
+	w.Line("END", "VCALENDAR")
+	return w.Bytes()
+}
+
+func (s *caldavService) projectTask(db *gorm.DB, projectID, taskID, userID uuid.UUID) (*model.Task, error) {
+	if _, err := findAccessibleProject(db, projectID, userID); err != nil {
+		return nil, err
+	}
+	var task model.Task
+	if err := db.First(&task, "id = ? AND project_id = ?", taskID, projectID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Task not found")
+	}
+	return &task, nil
+}
+
+// PutTodo применяет изменённый клиентом VTODO к задаче и возвращает новый ETag.
+// Из ресурса берутся название, описание, срок и отметка о выполнении, остальное игнорируется
+func (s *caldavService) PutTodo(
+	c *fiber.Ctx, projectID, taskID, userID uuid.UUID, data []byte, ifMatch string,
+) (string, error) {
+	task, err := s.projectTask(s.DB.WithContext(c.Context()), projectID, taskID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	calendar, err := utils.ParseICal(data)
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "Invalid iCalendar data")
+	}
+	todo := calendar.Component("VTODO")
+	if todo == nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "VTODO component is required")
+	}
+	if uid := todo.Property("UID"); uid != nil && uid.Value != taskUID(task.ID) {
+		return "", fiber.NewError(fiber.StatusBadRequest, "UID does not match the task")
+	}
+
+	req := validation.UpdateTaskTodo{ETag: ifMatch}
+	if summary := todo.Property("SUMMARY"); summary != nil {
+		req.Title = utils.ICalUnescape(summary.Value)
+	}
+	if description := todo.Property("DESCRIPTION"); description != nil {
+		req.Description = utils.ICalUnescape(description.Value)
+	}
+	if due := todo.Property("DUE"); due != nil {
+		dueDate, _, err := utils.ParseICalTime(due)
+		if err != nil {
+			return "", fiber.NewError(fiber.StatusBadRequest, "Invalid DUE value")
+		}
+		req.DueDate = &dueDate
+	}
+	// Без STATUS выполнение определяется по метке времени завершения
+	if status := todo.Property("STATUS"); status != nil {
+		req.Completed = status.Value == "COMPLETED"
+	} else {
+		req.Completed = todo.Property("COMPLETED") != nil
+	}
+
+	updated, err := s.TaskService.UpdateTaskFromTodo(c, task.ID, &req, userID)
+	if err != nil {
+		return "", err
+	}
+	return taskETag(updated), nil
+}
+
+// DeleteTodo переносит задачу в корзину
+func (s *caldavService) DeleteTodo(c *fiber.Ctx, projectID, taskID, userID uuid.UUID, ifMatch string) error {
+	task, err := s.projectTask(s.DB.WithContext(c.Context()), projectID, taskID, userID)
+	if err != nil {
+		return err
+	}
+	return s.TaskService.DeleteTaskFromTodo(c, task.ID, ifMatch, userID)
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CalendarService interface {
+	GetFeeds(c *fiber.Ctx, userID uuid.UUID) ([]model.CalendarFeed, error)
+	CreateFeed(c *fiber.Ctx, req *validation.CreateCalendarFeed, userID uuid.UUID) (*model.CalendarFeed, bool, error)
+	RegenerateToken(c *fiber.Ctx, feedID, userID uuid.UUID) (*model.CalendarFeed, error)
+	DeleteFeed(c *fiber.Ctx, feedID, userID uuid.UUID) error
+	RenderFeed(c *fiber.Ctx, token string, todo bool) ([]byte, error)
+}
+
+type calendarService struct {
+	Log             *logrus.Logger
+	DB              *gorm.DB
+	Validate        *validator.Validate
+	WorkflowService WorkflowService
+}
+
+func NewCalendarService(db *gorm.DB, validate *validator.Validate, workflowService WorkflowService) CalendarService {
+	return &calendarService{
+		Log:             utils.Log,
+		DB:              db,
+		Validate:        validate,
+		WorkflowService: workflowService,
+	}
+}
+
+const calendarProdID = "-//workmanager//Task deadlines//EN"
+
+// newFeedToken - 32 символа, достаточно длинный, чтобы его нельзя было подобрать
+func newFeedToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func withFeedURL(feed *model.CalendarFeed) *model.CalendarFeed {
+	feed.URL = config.AppURL + "/v1/calendar/" + feed.Token + ".ics"
+	return feed
+}
+
+func (s *calendarService) GetFeeds(c *fiber.Ctx, userID uuid.UUID) ([]model.CalendarFeed, error) {
+	var feeds []model.CalendarFeed
+	if err := s.DB.WithContext(c.Context()).
+		Where("user_id = ?", userID).
+		Order("project_id NULLS FIRST, created_at").
+		Find(&feeds).Error; err != nil {
+		s.Log.Errorf("Failed to get calendar feeds: %+v", err)
+		return nil, err
+	}
+	for i := range feeds {
+		withFeedURL(&feeds[i])
+	}
+	return feeds, nil
+}
+
+// CreateFeed возвращает ленту пользователя для проекта или личную, создавая её при первом запросе.
+// Второе значение - была ли лента создана сейчас
+func (s *calendarService) CreateFeed(
+	c *fiber.Ctx, req *validation.CreateCalendarFeed, userID uuid.UUID,
+) (*model.CalendarFeed, bool, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, false, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	if req.ProjectID != nil {
+		if _, err := findAccessibleProject(db, *req.ProjectID, userID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	feed := &model.CalendarFeed{UserID: userID, ProjectID: req.ProjectID}
+	query := db.Where("user_id = ?", userID)
+	if req.ProjectID != nil {
+		query = query.Where("project_id = ?", *req.ProjectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+	err := query.First(feed).Error
+	if err == nil {
+		return withFeedURL(feed), false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	if feed.Token, err = newFeedToken(); err != nil {
+		return nil, false, err
+	}
+	if err := db.Create(feed).Error; err != nil {
+		s.Log.Errorf("Failed to create calendar feed: %+v", err)
+		return nil, false, err
+	}
+	return withFeedURL(feed), true, nil
+}
+
+func (s *calendarService) ownFeed(db *gorm.DB, feedID, userID uuid.UUID) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	if err := db.First(&feed, "id = ? AND user_id = ?", feedID, userID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Calendar feed not found")
+	}
+	return &feed, nil
+}
+
+// RegenerateToken выпускает новый токен, старая ссылка сразу перестаёт работать
+func (s *calendarService) RegenerateToken(c *fiber.Ctx, feedID, userID uuid.UUID) (*model.CalendarFeed, error) {
+	db := s.DB.WithContext(c.Context())
+	feed, err := s.ownFeed(db, feedID, userID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Model(feed).Update("token", token).Error; err != nil {
+		s.Log.Errorf("Failed to regenerate calendar token: %+v", err)
+		return nil, err
+	}
+	return withFeedURL(feed), nil
+}
+
+func (s *calendarService) DeleteFeed(c *fiber.Ctx, feedID, userID uuid.UUID) error {
+	db := s.DB.WithContext(c.Context())
+	feed, err := s.ownFeed(db, feedID, userID)
+	if err != nil {
+		return err
+	}
+	return db.Delete(feed).Error
+}
+
+// RenderFeed собирает календарь по токену. Доступ к проекту проверяется при каждом запросе,
+// поэтому участник, которого убрали из проекта, перестаёт видеть его задачи
+func (s *calendarService) RenderFeed(c *fiber.Ctx, token string, todo bool) ([]byte, error) {
+	db := s.DB.WithContext(c.Context())
+	var feed model.CalendarFeed
+	if err := db.First(&feed, "token = ?", token).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Calendar feed not found")
+	}
+
+	name := "My tasks"
+	query := db.Model(&model.Task{}).
+		Preload("Project", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title")
+		}).
+		Where("tasks.due_date IS NOT NULL").
+		Where("EXISTS (SELECT 1 FROM projects p WHERE p.id = tasks.project_id AND p.deleted_at IS NULL)")
+	if feed.ProjectID != nil {
+		project, err := findAccessibleProject(db, *feed.ProjectID, feed.UserID)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Calendar feed not found")
+		}
+		name = project.Title
+		query = query.Where("tasks.project_id = ?", project.ID)
+	} else {
+		// Назначение остаётся за пользователем и после исключения из проекта, поэтому участие проверяется отдельно
+		query = query.Where("tasks.assigned_to = ?", feed.UserID).
+			Where("EXISTS (SELECT 1 FROM project_users pu WHERE pu.project_id = tasks.project_id AND pu.user_id = ?)", feed.UserID)
+	}
+
+	var tasks []model.Task
+	if err := query.Order("tasks.due_date, tasks.id").Find(&tasks).Error; err != nil {
+		s.Log.Errorf("Failed to get calendar tasks: %+v", err)
+		return nil, err
+	}
+
+	// Завершённые задачи помечаются по финальным статусам их проектов
+	final := make(map[uuid.UUID][]string)
+	for _, task := range tasks {
+		if _, ok := final[task.ProjectID]; ok {
+			continue
+		}
+		statuses, err := s.WorkflowService.FinalStatuses(db, task.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		final[task.ProjectID] = statuses
+	}
+
+	var w utils.ICalWriter
+	w.Line("BEGIN", "VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", calendarProdID)
+	w.Line("CALSCALE", "GREGORIAN")
+	w.Line("METHOD", "PUBLISH")
+	w.Text("X-WR-CALNAME", name)
+	w.Line("X-PUBLISHED-TTL", "PT1H")
+	now := time.Now()
+	for i := range tasks {
+		writeTaskComponent(&w, &tasks[i], slices.Contains(final[tasks[i].ProjectID], tasks[i].Status), todo, now)
+	}
+	w.Line("END", "VCALENDAR")
+	return w.Bytes(), nil
+}
+
+func taskUID(taskID uuid.UUID) string {
+	return taskID.String() + "@workmanager"
+}
+
+// writeTaskComponent пишет задачу как VEVENT или VTODO. Срок ровно в полночь UTC
+// считается датой без времени и превращается в событие на весь день.
+// Без срока можно писать только VTODO
+func writeTaskComponent(w *utils.ICalWriter, task *model.Task, done, todo bool, now time.Time) {
+	component := "VEVENT"
+	if todo {
+		component = "VTODO"
+	}
+	w.Line("BEGIN", component)
+	w.Line("UID", taskUID(task.ID))
+	w.Line("DTSTAMP", utils.ICalTime(now))
+	w.Line("CREATED", utils.ICalTime(task.CreatedAt))
+	w.Line("LAST-MODIFIED", utils.ICalTime(task.UpdatedAt))
+	w.Text("SUMMARY", task.Title)
+	if task.Description != "" {
+		w.Text("DESCRIPTION", task.Description)
+	}
+	if task.Project.Title != "" {
+		w.Text("CATEGORIES", task.Project.Title)
+	}
+
+	var due time.Time
+	if task.DueDate != nil {
+		due = task.DueDate.UTC()
+	}
+	allDay := due.Equal(due.Truncate(24 * time.Hour))
+	switch {
+	case task.DueDate == nil:
+	case todo && allDay:
+		w.Line("DUE;VALUE=DATE", utils.ICalDate(due))
+	case todo:
+		w.Line("DUE", utils.ICalTime(due))
+	case allDay:
+		w.Line("DTSTART;VALUE=DATE", utils.ICalDate(due))
+		w.Line("DTEND;VALUE=DATE", utils.ICalDate(due.AddDate(0, 0, 1)))
+		w.Line("TRANSP", "TRANSPARENT")
+	default:
+		// Событие без длительности - момент дедлайна
+		w.Line("DTSTART", utils.ICalTime(due))
+		w.Line("TRANSP", "TRANSPARENT")
+	}
+
+	if todo {
+		if done {
+			w.Line("STATUS", "COMPLETED")
+			// Момент завершения не хранится, берётся последнее изменение задачи
+			w.Line("COMPLETED", utils.ICalTime(task.UpdatedAt))
+		} else {
+			w.Line("STATUS", "NEEDS-ACTION")
+		}
+	} else if done {
+		// У событий нет статуса выполнения, завершённые помечаются нестандартным свойством
+		w.Line("X-WORKMANAGER-STATUS", "COMPLETED")
+	}
+	w.Line("END", component)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// loadReactions собирает реакции комментариев по эмодзи в порядке первой реакции
+func loadReactions(db *gorm.DB, commentIDs []uuid.UUID) (map[uuid.UUID][]model.ReactionSummary, error) {
+	summaries := make(map[uuid.UUID][]model.ReactionSummary)
+	if len(commentIDs) == 0 {
+		return summaries, nil
+	}
+
+	var reactions []model.CommentReaction
+	if err := db.Where("comment_id IN ?", commentIDs).Order("created_at, id").Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactions {
+		list := summaries[reaction.CommentID]
+		i := 0
+		for i < len(list) && list[i].Emoji != reaction.Emoji {
+			i++
+		}
+		if i == len(list) {
+			list = append(list, model.ReactionSummary{Emoji: reaction.Emoji, UserIDs: []uuid.UUID{}})
+		}
+		list[i].Count++
+		list[i].UserIDs = append(list[i].UserIDs, reaction.UserID)
+		summaries[reaction.CommentID] = list
+	}
+	return summaries, nil
+}
+
+func (s *commentService) publishReaction(action string, event *response.CommentReactions) {
+	err := publishMessage(context.Background(), s.Redis, commentUpdatesChannel, WSMessage{
+		Entity:    "reaction",
+		Action:    action,
+		Data:      event,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to publish reaction %s: %v", action, err)
+	}
+}
+
+// commentReactions возвращает изменение вместе с актуальными реакциями комментария
+func (s *commentService) commentReactions(
+	db *gorm.DB, comment *model.Comment, userID uuid.UUID, emoji string,
+) (*response.CommentReactions, error) {
+	summaries, err := loadReactions(db, []uuid.UUID{comment.ID})
+	if err != nil {
+		return nil, err
+	}
+	reactions := summaries[comment.ID]
+	if reactions == nil {
+		reactions = []model.ReactionSummary{}
+	}
+	return &response.CommentReactions{
+		CommentID: comment.ID,
+		TaskID:    comment.TaskID,
+		UserID:    userID,
+		Emoji:     emoji,
+		Reactions: reactions,
+	}, nil
+}
+
+// AddReaction ставит реакцию на живой комментарий доступной задачи
+func (s *commentService) AddReaction(
+	c *fiber.Ctx, commentID uuid.UUID, req *validation.CommentReaction, userID uuid.UUID,
+) (*response.CommentReactions, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	comment, err := s.accessibleComment(db, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Comment is deleted")
+	}
+
+	if err := db.Create(&model.CommentReaction{
+		CommentID: comment.ID,
+		UserID:    userID,
+		Emoji:     req.Emoji,
+	}).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fiber.NewError(fiber.StatusConflict, "Reaction already exists")
+		}
+		s.Log.Errorf("Failed to add reaction: %+v", err)
+		return nil, err
+	}
+
+	event, err := s.commentReactions(db, comment, userID, req.Emoji)
+	if err != nil {
+		return nil, err
+	}
+	go s.publishReaction("added", event)
+	return event, nil
+}
+
+// RemoveReaction снимает реакцию пользователя
+func (s *commentService) RemoveReaction(
+	c *fiber.Ctx, commentID uuid.UUID, emoji string, userID uuid.UUID,
+) (*response.CommentReactions, error) {
+	db := s.DB.WithContext(c.Context())
+	comment, err := s.accessibleComment(db, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := db.Where("comment_id = ? AND user_id = ? AND emoji = ?", comment.ID, userID, emoji).
+		Delete(&model.CommentReaction{})
+	if result.Error != nil {
+		s.Log.Errorf("Failed to remove reaction: %+v", result.Error)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Reaction not found")
+	}
+
+	event, err := s.commentReactions(db, comment, userID, emoji)
+	if err != nil {
+		return nil, err
+	}
+	go s.publishReaction("removed", event)
+	return event, nil
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CommentService interface {
+	CreateComment(c *fiber.Ctx, req *validation.CreateComment, userID uuid.UUID) (*model.Comment, error)
+	GetTaskComments(c *fiber.Ctx, taskID uuid.UUID, params *validation.QueryComments, userID uuid.UUID) ([]model.Comment, string, error)
+	UpdateComment(c *fiber.Ctx, commentID uuid.UUID, req *validation.UpdateComment, userID uuid.UUID) (*model.Comment, error)
+	DeleteComment(c *fiber.Ctx, commentID, userID uuid.UUID) error
+	GetCommentRevisions(c *fiber.Ctx, commentID, userID uuid.UUID) ([]model.CommentRevision, error)
+	AddReaction(c *fiber.Ctx, commentID uuid.UUID, req *validation.CommentReaction, userID uuid.UUID) (*response.CommentReactions, error)
+	RemoveReaction(c *fiber.Ctx, commentID uuid.UUID, emoji string, userID uuid.UUID) (*response.CommentReactions, error)
+}
+
+type commentService struct {
+	Log            *logrus.Logger
+	DB             *gorm.DB
+	Validate       *validator.Validate
+	Redis          *redis.Client
+	MentionService MentionService
+}
+
+func NewCommentService(
+	db *gorm.DB, validate *validator.Validate, redisClient *redis.Client, mentionService MentionService,
+) CommentService {
+	return &commentService{
+		Log:            utils.Log,
+		DB:             db,
+		Validate:       validate,
+		Redis:          redisClient,
+		MentionService: mentionService,
+	}
+}
+
+const defaultCommentLimit = 20
+
+func (s *commentService) publish(action string, comment *model.Comment) {
+	err := publishMessage(context.Background(), s.Redis, commentUpdatesChannel, WSMessage{
+		Entity:    "comment",
+		Action:    action,
+		Data:      comment,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to publish comment %s: %v", action, err)
+	}
+}
+
+// accessibleComment возвращает комментарий к задаче, доступной пользователю
+func (s *commentService) accessibleComment(db *gorm.DB, commentID, userID uuid.UUID) (*model.Comment, error) {
+	var comment model.Comment
+	if err := db.First(&comment, "id = ?", commentID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Comment not found")
+	}
+	if _, err := findAccessibleTask(db, comment.TaskID, userID); err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Comment not found")
+	}
+	return &comment, nil
+}
+
+// ownComment возвращает живой комментарий, который может менять только его автор
+func (s *commentService) ownComment(db *gorm.DB, commentID, userID uuid.UUID) (*model.Comment, error) {
+	comment, err := s.accessibleComment(db, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Only the author can change a comment")
+	}
+	if comment.DeletedAt != nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Comment is deleted")
+	}
+	return comment, nil
+}
+
+// checkTarget проверяет, что ответ или цитата ссылаются на живой комментарий той же задачи
+func checkTarget(db *gorm.DB, targetID *uuid.UUID, taskID uuid.UUID, field string) error {
+	if targetID == nil {
+		return nil
+	}
+	var target model.Comment
+	if err := db.Select("id", "task_id", "deleted_at").First(&target, "id = ?", *targetID).Error; err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, field+" references an unknown comment")
+	}
+	if target.TaskID != taskID {
+		return fiber.NewError(fiber.StatusBadRequest, field+" must reference a comment of the same task")
+	}
+	if target.DeletedAt != nil {
+		return fiber.NewError(fiber.StatusConflict, field+" references a deleted comment")
+	}
+	return nil
+}
+
+func (s *commentService) CreateComment(c *fiber.Ctx, req *validation.CreateComment, userID uuid.UUID) (*model.Comment, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	task, err := findAccessibleTask(db, req.TaskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTarget(db, req.ReplyToID, req.TaskID, "reply_to_id"); err != nil {
+		return nil, err
+	}
+	if err := checkTarget(db, req.CitateID, req.TaskID, "citate_id"); err != nil {
+		return nil, err
+	}
+
+	comment := &model.Comment{
+		Body:      req.Body,
+		TaskID:    req.TaskID,
+		UserID:    userID,
+		ReplyToID: req.ReplyToID,
+		CitateID:  req.CitateID,
+	}
+	var mentions []model.Mention
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		var err error
+		mentions, err = s.MentionService.SyncMentions(tx, model.MentionSourceComment, comment.ID, task, userID, comment.Body)
+		return err
+	})
+	if err != nil {
+		s.Log.Errorf("Ошибка создания комментария: %+v", err)
+		return nil, err
+	}
+	if err := db.Preload("User").First(comment, "id = ?", comment.ID).Error; err != nil {
+		return nil, err
+	}
+
+	// Публикация комментария в Redis
+	go s.publish("created", comment)
+	go s.MentionService.Notify(mentions, comment.Body)
+	return comment, nil
+}
+
+// GetTaskComments возвращает ветки обсуждения задачи. Пагинация идёт по комментариям верхнего уровня
+// в порядке создания, каждая ветка приходит целиком с вложенными ответами и реакциями
+func (s *commentService) GetTaskComments(
+	c *fiber.Ctx, taskID uuid.UUID, params *validation.QueryComments, userID uuid.UUID,
+) ([]model.Comment, string, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, "", err
+	}
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleTask(db, taskID, userID); err != nil {
+		return nil, "", err
+	}
+
+	query := db.Where("task_id = ? AND reply_to_id IS NULL", taskID)
+	if params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		value, err := cursorValue("time", cursor.Value)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		query = query.Where("(created_at > @value OR (created_at = @value AND id > @id))",
+			sql.Named("value", value), sql.Named("id", cursor.ID))
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultCommentLimit
+	}
+
+	var roots []model.Comment
+	if err := query.Preload("User").Order("created_at, id").Limit(limit + 1).Find(&roots).Error; err != nil {
+		s.Log.Errorf("Failed to get comments: %+v", err)
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(roots) > limit {
+		roots = roots[:limit]
+		last := roots[limit-1]
+		encoded, err := utils.EncodeCursor(last.CreatedAt, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		nextCursor = encoded
+	}
+	if len(roots) == 0 {
+		return roots, nextCursor, nil
+	}
+
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+
+	// Все ответы страницы одним рекурсивным запросом
+	var replyIDs []uuid.UUID
+	if err := db.Raw(`
+		WITH RECURSIVE thread AS (
+			SELECT id FROM comments WHERE reply_to_id IN ?
+			UNION ALL
+			SELECT c.id FROM comments c
+			INNER JOIN thread t ON c.reply_to_id = t.id
+		)
+		SELECT id FROM thread
+	`, rootIDs).Scan(&replyIDs).Error; err != nil {
+		s.Log.Errorf("Failed to load comment replies: %+v", err)
+		return nil, "", err
+	}
+
+	var replies []model.Comment
+	if len(replyIDs) > 0 {
+		if err := db.Preload("User").Where("id IN ?", replyIDs).
+			Order("created_at, id").Find(&replies).Error; err != nil {
+			return nil, "", err
+		}
+	}
+
+	commentIDs := append(rootIDs, replyIDs...)
+	reactions, err := loadReactions(db, commentIDs)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range roots {
+		roots[i].Reactions = reactions[roots[i].ID]
+	}
+
+	children := make(map[uuid.UUID][]model.Comment)
+	for _, reply := range replies {
+		reply.Reactions = reactions[reply.ID]
+		children[*reply.ReplyToID] = append(children[*reply.ReplyToID], reply)
+	}
+	for i := range roots {
+		attachReplies(&roots[i], children)
+	}
+	return roots, nextCursor, nil
+}
+
+func attachReplies(comment *model.Comment, children map[uuid.UUID][]model.Comment) {
+	comment.Replies = children[comment.ID]
+	for i := range comment.Replies {
+		attachReplies(&comment.Replies[i], children)
+	}
+}
+
+// UpdateComment меняет текст комментария, прежний текст сохраняется в ревизиях
+func (s *commentService) UpdateComment(
+	c *fiber.Ctx, commentID uuid.UUID, req *validation.UpdateComment, userID uuid.UUID,
+) (*model.Comment, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var comment *model.Comment
+	var mentions []model.Mention
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if comment, err = s.ownComment(tx, commentID, userID); err != nil {
+			return err
+		}
+		if comment.Body == req.Body {
+			return nil
+		}
+
+		if err := tx.Create(&model.CommentRevision{
+			CommentID: comment.ID,
+			Body:      comment.Body,
+			EditedBy:  userID,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(comment).Updates(map[string]interface{}{"body": req.Body, "is_edited": true}).Error; err != nil {
+			return err
+		}
+		// Уведомление получают только новые упомянутые
+		task := &model.Task{}
+		if err := tx.Select("id", "project_id").First(task, "id = ?", comment.TaskID).Error; err != nil {
+			return err
+		}
+		mentions, err = s.MentionService.SyncMentions(tx, model.MentionSourceComment, comment.ID, task, userID, req.Body)
+		return err
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to update comment: %+v", err)
+		return nil, err
+	}
+	if err := s.DB.WithContext(c.Context()).Preload("User").First(comment, "id = ?", comment.ID).Error; err != nil {
+		return nil, err
+	}
+
+	go s.publish("updated", comment)
+	go s.MentionService.Notify(mentions, comment.Body)
+	return comment, nil
+}
+
+// DeleteComment оставляет на месте комментария заглушку, чтобы не рвать ветку ответов.
+// Текст, ревизии, упоминания и реакции удаляются
+func (s *commentService) DeleteComment(c *fiber.Ctx, commentID, userID uuid.UUID) error {
+	var comment *model.Comment
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if comment, err = s.ownComment(tx, commentID, userID); err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&model.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("source_id = ?", comment.ID).Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&model.CommentReaction{}).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		comment.Body = ""
+		comment.DeletedAt = &now
+		return tx.Model(comment).Updates(map[string]interface{}{"body": "", "deleted_at": now}).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to delete comment: %+v", err)
+		return err
+	}
+
+	go s.publish("deleted", comment)
+	return nil
+}
+
+// GetCommentRevisions возвращает прежние версии текста от новых к старым
+func (s *commentService) GetCommentRevisions(c *fiber.Ctx, commentID, userID uuid.UUID) ([]model.CommentRevision, error) {
+	db := s.DB.WithContext(c.Context())
+	if _, err := s.accessibleComment(db, commentID, userID); err != nil {
+		return nil, err
+	}
+
+	var revisions []model.CommentRevision
+	if err := db.Where("comment_id = ?", commentID).Order("created_at DESC").Find(&revisions).Error; err != nil {
+		s.Log.Errorf("Failed to get comment revisions: %+v", err)
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomFieldService interface {
+	GetCustomFields(c *fiber.Ctx, projectID, userID uuid.UUID) ([]model.CustomField, error)
+	CreateCustomField(c *fiber.Ctx, projectID uuid.UUID, req *validation.CreateCustomField, userID uuid.UUID) (*model.CustomField, error)
+	UpdateCustomField(c *fiber.Ctx, fieldID uuid.UUID, req *validation.UpdateCustomField, userID uuid.UUID) (*model.CustomField, error)
+	DeleteCustomField(c *fiber.Ctx, fieldID, userID uuid.UUID) error
+	SetTaskValues(c *fiber.Ctx, taskID uuid.UUID, req *validation.SetCustomFieldValues, userID uuid.UUID) (*model.Task, error)
+	ValidateValues(db *gorm.DB, projectID uuid.UUID, current model.CustomFieldValues, values map[string]interface{}) (model.CustomFieldValues, error)
+}
+
+type customFieldService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+	Redis    *redis.Client
+}
+
+func NewCustomFieldService(db *gorm.DB, validate *validator.Validate, redisClient *redis.Client) CustomFieldService {
+	return &customFieldService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+		Redis:    redisClient,
+	}
+}
+
+const maxCustomTextLength = 1000
+
+func (s *customFieldService) publish(channel, entity, action string, data interface{}) {
+	err := publishMessage(context.Background(), s.Redis, channel, WSMessage{
+		Entity:    entity,
+		Action:    action,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to publish %s %s: %v", entity, action, err)
+	}
+}
+
+// manageableField возвращает поле из проекта, настройки которого пользователь может менять
+func (s *customFieldService) manageableField(db *gorm.DB, fieldID, userID uuid.UUID) (*model.CustomField, error) {
+	var field model.CustomField
+	if err := db.First(&field, "id = ?", fieldID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Custom field not found")
+	}
+	if _, err := findManageableProject(db, field.ProjectID, userID); err != nil {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusNotFound {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Custom field not found")
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (s *customFieldService) GetCustomFields(c *fiber.Ctx, projectID, userID uuid.UUID) ([]model.CustomField, error) {
+	if _, err := findAccessibleProject(s.DB.WithContext(c.Context()), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	var fields []model.CustomField
+	if err := s.DB.WithContext(c.Context()).
+		Where("project_id = ?", projectID).
+		Order(`"order", name`).
+		Find(&fields).Error; err != nil {
+		s.Log.Errorf("Failed to get custom fields: %+v", err)
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (s *customFieldService) CreateCustomField(
+	c *fiber.Ctx, projectID uuid.UUID, req *validation.CreateCustomField, userID uuid.UUID,
+) (*model.CustomField, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if _, err := findManageableProject(s.DB.WithContext(c.Context()), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	field := &model.CustomField{
+		ProjectID: projectID,
+		Name:      req.Name,
+		Type:      req.Type,
+		Order:     req.Order,
+	}
+	if hasOptions(field.Type) {
+		field.Options = req.Options
+	} else if len(req.Options) > 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Only select fields have options")
+	}
+
+	result := s.DB.WithContext(c.Context()).Create(field)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Custom field already exists")
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to create custom field: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	go s.publish(projectUpdatesChannel, "custom_field", "created", field)
+	return field, nil
+}
+
+// UpdateCustomField меняет название, порядок или варианты поля. Тип поля не меняется,
+// варианты, выбранные в задачах, удалить нельзя
+func (s *customFieldService) UpdateCustomField(
+	c *fiber.Ctx, fieldID uuid.UUID, req *validation.UpdateCustomField, userID uuid.UUID,
+) (*model.CustomField, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var field *model.CustomField
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if field, err = s.manageableField(tx, fieldID, userID); err != nil {
+			return err
+		}
+
+		if req.Name != nil {
+			field.Name = *req.Name
+		}
+		if req.Order != nil {
+			field.Order = *req.Order
+		}
+		if len(req.Options) > 0 {
+			if !hasOptions(field.Type) {
+				return fiber.NewError(fiber.StatusBadRequest, "Only select fields have options")
+			}
+			for _, option := range field.Options {
+				if slices.Contains(req.Options, option) {
+					continue
+				}
+				var used int64
+				if err := tx.Model(&model.Task{}).
+					Where("project_id = ? AND custom_fields -> ? @> to_jsonb(?::text)", field.ProjectID, field.ID.String(), option).
+					Count(&used).Error; err != nil {
+					return err
+				}
+				if used > 0 {
+					return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("Option %q is in use", option))
+				}
+			}
+			field.Options = req.Options
+		}
+
+		return tx.Model(field).Select("name", "order", "options").Updates(field).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Custom field with this name already exists")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to update custom field: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(projectUpdatesChannel, "custom_field", "updated", field)
+	return field, nil
+}
+
+// DeleteCustomField удаляет поле вместе со значениями в задачах проекта
+func (s *customFieldService) DeleteCustomField(c *fiber.Ctx, fieldID, userID uuid.UUID) error {
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		field, err := s.manageableField(tx, fieldID, userID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(
+			"UPDATE tasks SET custom_fields = custom_fields - ? WHERE project_id = ?", field.ID.String(), field.ProjectID,
+		).Error; err != nil {
+			return err
+		}
+		return tx.Delete(field).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to delete custom field: %+v", err)
+		return err
+	}
+
+	go s.publish(projectUpdatesChannel, "custom_field", "deleted", fiber.Map{"id": fieldID})
+	return nil
+}
+
+// SetTaskValues меняет значения переданных полей задачи, остальные поля не трогает
+func (s *customFieldService) SetTaskValues(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.SetCustomFieldValues, userID uuid.UUID,
+) (*model.Task, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID); err != nil {
+			return err
+		}
+		values, err := s.ValidateValues(tx, task.ProjectID, task.CustomFields, req.Values)
+		if err != nil {
+			return err
+		}
+		before := *task
+		task.CustomFields = values
+		if err := tx.Model(task).Update("custom_fields", values).Error; err != nil {
+			return err
+		}
+		return recordHistory(tx, task.ID, userID, historyCustomFields, diffTasks(&before, task))
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to set custom field values: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(taskUpdatesChannel, "task", "custom_fields_updated", task)
+	return task, nil
+}
+
+// ValidateValues проверяет значения по определениям полей проекта и накладывает их на текущие.
+// null удаляет значение поля
+func (s *customFieldService) ValidateValues(
+	db *gorm.DB, projectID uuid.UUID, current model.CustomFieldValues, values map[string]interface{},
+) (model.CustomFieldValues, error) {
+	result := make(model.CustomFieldValues, len(current)+len(values))
+	maps.Copy(result, current)
+	if len(values) == 0 {
+		return result, nil
+	}
+
+	var fields []model.CustomField
+	if err := db.Where("project_id = ?", projectID).Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.CustomField, len(fields))
+	for i := range fields {
+		byID[fields[i].ID.String()] = &fields[i]
+	}
+
+	for key, value := range values {
+		field, ok := byID[key]
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown custom field %q", key))
+		}
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		checked, err := s.checkValue(db, field, value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = checked
+	}
+	return result, nil
+}
+
+// checkValue проверяет тип значения и валидирует его тегом, собранным по определению поля
+func (s *customFieldService) checkValue(db *gorm.DB, field *model.CustomField, value interface{}) (interface{}, error) {
+	invalid := fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid value for custom field %q", field.Name))
+
+	var tag string
+	switch field.Type {
+	case model.CustomFieldNumber:
+		if _, ok := value.(float64); !ok {
+			return nil, invalid
+		}
+	case model.CustomFieldCheckbox:
+		if _, ok := value.(bool); !ok {
+			return nil, invalid
+		}
+	case model.CustomFieldMultiSelect:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, invalid
+		}
+		options := make([]string, 0, len(items))
+		for _, item := range items {
+			option, ok := item.(string)
+			if !ok {
+				return nil, invalid
+			}
+			options = append(options, option)
+		}
+		value, tag = options, "unique,dive,"+oneOfTag(field.Options)
+	default:
+		if _, ok := value.(string); !ok {
+			return nil, invalid
+		}
+		switch field.Type {
+		case model.CustomFieldText:
+			tag = fmt.Sprintf("max=%d", maxCustomTextLength)
+		case model.CustomFieldDate:
+			tag = "datetime=2006-01-02"
+		case model.CustomFieldUser:
+			tag = "uuid"
+		case model.CustomFieldSelect:
+			tag = oneOfTag(field.Options)
+		}
+	}
+
+	if tag != "" {
+		if err := s.Validate.Var(value, tag); err != nil {
+			return nil, invalid
+		}
+	}
+
+	// Ссылаться можно только на участника проекта
+	if field.Type == model.CustomFieldUser {
+		if _, err := findAccessibleProject(db, field.ProjectID, uuid.MustParse(value.(string))); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("Custom field %q must reference a project member", field.Name))
+		}
+	}
+	return value, nil
+}
+
+func hasOptions(fieldType string) bool {
+	return fieldType == model.CustomFieldSelect || fieldType == model.CustomFieldMultiSelect
+}
+
+// oneOfTag собирает тег oneof из вариантов поля, варианты с пробелами берутся в кавычки
+func oneOfTag(options []string) string {
+	quoted := make([]string, len(options))
+	for i, option := range options {
+		quoted[i] = "'" + option + "'"
+	}
+	return "oneof=" + strings.Join(quoted, " ")
+}
+
+// customFieldColumn возвращает SQL-выражение значения поля задачи.
+// ID поля подставляется в запрос напрямую, это безопасно - это разобранный UUID
+func customFieldColumn(field *model.CustomField) string {
+	column := fmt.Sprintf("tasks.custom_fields->>'%s'", field.ID)
+	switch field.Type {
+	case model.CustomFieldNumber:
+		return "(" + column + ")::numeric"
+	case model.CustomFieldCheckbox:
+		return "(" + column + ")::boolean"
+	}
+	return column
+}
+
+// customFieldSortKind возвращает тип значения поля в курсоре
+func customFieldSortKind(field *model.CustomField) (string, bool) {
+	switch field.Type {
+	case model.CustomFieldMultiSelect:
+		return "", false
+	case model.CustomFieldNumber:
+		return "float", true
+	case model.CustomFieldCheckbox:
+		return "bool", true
+	}
+	return "string", true
+}
+
+// filterByCustomField добавляет условие по значению поля:
+// text - подстрока, number и date - точное значение или диапазон "from..to" с открытыми концами,
+// multi_select - содержит вариант, checkbox - true или false, остальные - равенство
+func filterByCustomField(query *gorm.DB, field *model.CustomField, value string) (*gorm.DB, error) {
+	invalid := fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid filter for custom field %q", field.Name))
+	column := customFieldColumn(field)
+
+	switch field.Type {
+	case model.CustomFieldText:
+		return query.Where(column+" ILIKE ?", "%"+value+"%"), nil
+	case model.CustomFieldNumber, model.CustomFieldDate:
+		from, to, isRange := strings.Cut(value, "..")
+		if !isRange {
+			to = from
+		}
+		if from == "" && to == "" {
+			return nil, invalid
+		}
+		bounds := []struct {
+			value, operator string
+		}{{from, ">="}, {to, "<="}}
+		for _, bound := range bounds {
+			if bound.value == "" {
+				continue
+			}
+			var parsed interface{} = bound.value
+			if field.Type == model.CustomFieldNumber {
+				number, err := strconv.ParseFloat(bound.value, 64)
+				if err != nil {
+					return nil, invalid
+				}
+				parsed = number
+			} else if _, err := time.Parse(time.DateOnly, bound.value); err != nil {
+				return nil, invalid
+			}
+			query = query.Where(column+" "+bound.operator+" ?", parsed)
+		}
+		return query, nil
+	case model.CustomFieldMultiSelect:
+		return query.Where("tasks.custom_fields -> ? @> to_jsonb(?::text)", field.ID.String(), value), nil
+	case model.CustomFieldCheckbox:
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, invalid
+		}
+		// Незаполненный флажок считается снятым
+		return query.Where("COALESCE("+column+", false) = ?", checked), nil
+	}
+	return query.Where(column+" = ?", value), nil
+}
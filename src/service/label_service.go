@@ -0,0 +1,279 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LabelService interface {
+	GetLabels(c *fiber.Ctx, projectID, userID uuid.UUID) ([]model.Label, error)
+	CreateLabel(c *fiber.Ctx, projectID uuid.UUID, req *validation.CreateLabel, userID uuid.UUID) (*model.Label, error)
+	UpdateLabel(c *fiber.Ctx, labelID uuid.UUID, req *validation.UpdateLabel, userID uuid.UUID) (*model.Label, error)
+	DeleteLabel(c *fiber.Ctx, labelID, userID uuid.UUID) error
+	MergeLabel(c *fiber.Ctx, labelID uuid.UUID, req *validation.MergeLabel, userID uuid.UUID) (*model.Label, error)
+	AddTaskLabel(c *fiber.Ctx, taskID, labelID, userID uuid.UUID) (*model.Task, error)
+	RemoveTaskLabel(c *fiber.Ctx, taskID, labelID, userID uuid.UUID) (*model.Task, error)
+}
+
+type labelService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+	Redis    *redis.Client
+}
+
+func NewLabelService(db *gorm.DB, validate *validator.Validate, redisClient *redis.Client) LabelService {
+	return &labelService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+		Redis:    redisClient,
+	}
+}
+
+// Цвет метки по умолчанию
+const defaultLabelColor = "#9e9e9e"
+
+func (s *labelService) publish(channel, entity, action string, data interface{}) {
+	err := publishMessage(context.Background(), s.Redis, channel, WSMessage{
+		Entity:    entity,
+		Action:    action,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to publish %s %s: %v", entity, action, err)
+	}
+}
+
+// accessibleLabel возвращает метку из проекта, в котором участвует пользователь
+func (s *labelService) accessibleLabel(db *gorm.DB, labelID, userID uuid.UUID) (*model.Label, error) {
+	var label model.Label
+	if err := db.First(&label, "id = ?", labelID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Label not found")
+	}
+	if _, err := findAccessibleProject(db, label.ProjectID, userID); err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Label not found")
+	}
+	return &label, nil
+}
+
+func (s *labelService) GetLabels(c *fiber.Ctx, projectID, userID uuid.UUID) ([]model.Label, error) {
+	if _, err := findAccessibleProject(s.DB.WithContext(c.Context()), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	var labels []model.Label
+	if err := s.DB.WithContext(c.Context()).
+		Where("project_id = ?", projectID).
+		Order("name").
+		Find(&labels).Error; err != nil {
+		s.Log.Errorf("Failed to get labels: %+v", err)
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (s *labelService) CreateLabel(
+	c *fiber.Ctx, projectID uuid.UUID, req *validation.CreateLabel, userID uuid.UUID,
+) (*model.Label, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if _, err := findAccessibleProject(s.DB.WithContext(c.Context()), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	label := &model.Label{
+		ProjectID: projectID,
+		Name:      req.Name,
+		Color:     req.Color,
+	}
+	if label.Color == "" {
+		label.Color = defaultLabelColor
+	}
+
+	result := s.DB.WithContext(c.Context()).Create(label)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Label already exists")
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to create label: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	go s.publish(projectUpdatesChannel, "label", "created", label)
+	return label, nil
+}
+
+// UpdateLabel переименовывает или перекрашивает метку во всём проекте
+func (s *labelService) UpdateLabel(
+	c *fiber.Ctx, labelID uuid.UUID, req *validation.UpdateLabel, userID uuid.UUID,
+) (*model.Label, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	label, err := s.accessibleLabel(s.DB.WithContext(c.Context()), labelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		label.Name = *req.Name
+	}
+	if req.Color != nil {
+		label.Color = *req.Color
+	}
+
+	result := s.DB.WithContext(c.Context()).Model(label).Updates(map[string]interface{}{
+		"name":  label.Name,
+		"color": label.Color,
+	})
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Label with this name already exists, merge them instead")
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to update label: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	go s.publish(projectUpdatesChannel, "label", "updated", label)
+	return label, nil
+}
+
+func (s *labelService) DeleteLabel(c *fiber.Ctx, labelID, userID uuid.UUID) error {
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		label, err := s.accessibleLabel(tx, labelID, userID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_labels WHERE label_id = ?", label.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(label).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to delete label: %+v", err)
+		return err
+	}
+
+	go s.publish(projectUpdatesChannel, "label", "deleted", fiber.Map{"id": labelID})
+	return nil
+}
+
+// MergeLabel переносит метку со всех задач на целевую и удаляет исходную
+func (s *labelService) MergeLabel(
+	c *fiber.Ctx, labelID uuid.UUID, req *validation.MergeLabel, userID uuid.UUID,
+) (*model.Label, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.TargetLabelID == labelID {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Label cannot be merged into itself")
+	}
+
+	var target *model.Label
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		source, err := s.accessibleLabel(tx, labelID, userID)
+		if err != nil {
+			return err
+		}
+		if target, err = s.accessibleLabel(tx, req.TargetLabelID, userID); err != nil {
+			return err
+		}
+		if source.ProjectID != target.ProjectID {
+			return fiber.NewError(fiber.StatusBadRequest, "Labels belong to different projects")
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO task_labels (task_id, label_id)
+			SELECT task_id, ? FROM task_labels WHERE label_id = ?
+			ON CONFLICT DO NOTHING
+		`, target.ID, source.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_labels WHERE label_id = ?", source.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(source).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to merge labels: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(projectUpdatesChannel, "label", "merged", fiber.Map{"id": labelID, "target": target})
+	return target, nil
+}
+
+func (s *labelService) AddTaskLabel(c *fiber.Ctx, taskID, labelID, userID uuid.UUID) (*model.Task, error) {
+	return s.changeTaskLabels(c, taskID, labelID, userID, historyLabelAdded, func(tx *gorm.DB, task *model.Task) error {
+		return tx.Exec(
+			"INSERT INTO task_labels (task_id, label_id) VALUES (?, ?) ON CONFLICT DO NOTHING", task.ID, labelID,
+		).Error
+	})
+}
+
+func (s *labelService) RemoveTaskLabel(c *fiber.Ctx, taskID, labelID, userID uuid.UUID) (*model.Task, error) {
+	return s.changeTaskLabels(c, taskID, labelID, userID, historyLabelRemoved, func(tx *gorm.DB, task *model.Task) error {
+		return tx.Exec("DELETE FROM task_labels WHERE task_id = ? AND label_id = ?", task.ID, labelID).Error
+	})
+}
+
+func (s *labelService) changeTaskLabels(
+	c *fiber.Ctx, taskID, labelID, userID uuid.UUID, action string, change func(tx *gorm.DB, task *model.Task) error,
+) (*model.Task, error) {
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID); err != nil {
+			return err
+		}
+
+		var label model.Label
+		if err := tx.First(&label, "id = ? AND project_id = ?", labelID, task.ProjectID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Label not found")
+		}
+		var had int64
+		if err := tx.Table("task_labels").Where("task_id = ? AND label_id = ?", task.ID, labelID).
+			Count(&had).Error; err != nil {
+			return err
+		}
+		if err := change(tx, task); err != nil {
+			return err
+		}
+		if err := tx.Model(task).Association("Labels").Find(&task.Labels); err != nil {
+			return err
+		}
+		// В историю попадает только реальное изменение, как при переносе по дорожкам меток
+		has := slices.ContainsFunc(task.Labels, func(l model.Label) bool { return l.ID == labelID })
+		if (had > 0) == has {
+			return nil
+		}
+		fieldChange := model.FieldChange{Field: "label", New: labelID}
+		if !has {
+			fieldChange = model.FieldChange{Field: "label", Old: labelID}
+		}
+		return recordHistory(tx, task.ID, userID, action, []model.FieldChange{fieldChange})
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to change task labels: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(taskUpdatesChannel, "task", action, task)
+	return task, nil
+}
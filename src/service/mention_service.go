@@ -0,0 +1,311 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type MentionService interface {
+	SyncMentions(tx *gorm.DB, sourceType string, sourceID uuid.UUID, task *model.Task, authorID uuid.UUID, body string) ([]model.Mention, error)
+	Notify(mentions []model.Mention, body string)
+	GetUserMentions(c *fiber.Ctx, userID uuid.UUID, params *validation.QueryMentions) ([]model.Mention, string, error)
+	MarkRead(c *fiber.Ctx, mentionID, userID uuid.UUID) error
+	HandleNotifications(c *websocket.Conn, userID uuid.UUID)
+}
+
+type mentionService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	Redis        *redis.Client
+	EmailService EmailService
+}
+
+func NewMentionService(
+	db *gorm.DB, validate *validator.Validate, redisClient *redis.Client, emailService EmailService,
+) MentionService {
+	return &mentionService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		Redis:        redisClient,
+		EmailService: emailService,
+	}
+}
+
+const (
+	defaultMentionLimit = 20
+	mentionExcerptLen   = 500
+)
+
+// notificationsChannel - личный канал уведомлений пользователя
+func notificationsChannel(userID uuid.UUID) string {
+	return "notifications:" + userID.String()
+}
+
+// mentionHandles возвращает варианты, по которым можно упомянуть пользователя
+func mentionHandles(user *model.User) []string {
+	email := strings.ToLower(user.Email)
+	local, _, _ := strings.Cut(email, "@")
+	return []string{email, local, strings.ToLower(strings.ReplaceAll(user.Name, " ", ""))}
+}
+
+// resolveMentions сопоставляет упоминания с участниками проекта.
+// Упоминание, подходящее нескольким участникам, пропускается
+func resolveMentions(members []model.User, handles []string) []uuid.UUID {
+	byHandle := make(map[string][]uuid.UUID)
+	for i := range members {
+		seen := make(map[string]bool)
+		for _, handle := range mentionHandles(&members[i]) {
+			if handle != "" && !seen[handle] {
+				seen[handle] = true
+				byHandle[handle] = append(byHandle[handle], members[i].ID)
+			}
+		}
+	}
+
+	var ids []uuid.UUID
+	found := make(map[uuid.UUID]bool)
+	for _, handle := range handles {
+		matched := byHandle[handle]
+		if len(matched) != 1 || found[matched[0]] {
+			continue
+		}
+		found[matched[0]] = true
+		ids = append(ids, matched[0])
+	}
+	return ids
+}
+
+// SyncMentions приводит сохранённые упоминания источника в соответствие с текстом
+// и возвращает только новые - по ним нужно отправить уведомления. Автор себя не упоминает
+func (s *mentionService) SyncMentions(
+	tx *gorm.DB, sourceType string, sourceID uuid.UUID, task *model.Task, authorID uuid.UUID, body string,
+) ([]model.Mention, error) {
+	var userIDs []uuid.UUID
+	if handles := utils.ParseMentions(body); len(handles) > 0 {
+		var members []model.User
+		if err := tx.Joins("JOIN project_users pu ON pu.user_id = users.id").
+			Where("pu.project_id = ?", task.ProjectID).
+			Find(&members).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range resolveMentions(members, handles) {
+			if id != authorID {
+				userIDs = append(userIDs, id)
+			}
+		}
+	}
+
+	var existing []model.Mention
+	if err := tx.Where("source_id = ?", sourceID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	kept := make(map[uuid.UUID]bool)
+	var removed []uuid.UUID
+	for _, mention := range existing {
+		if slices.Contains(userIDs, mention.UserID) {
+			kept[mention.UserID] = true
+		} else {
+			removed = append(removed, mention.ID)
+		}
+	}
+	if len(removed) > 0 {
+		if err := tx.Delete(&model.Mention{}, "id IN ?", removed).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	created := make([]model.Mention, 0)
+	for _, userID := range userIDs {
+		if kept[userID] {
+			continue
+		}
+		created = append(created, model.Mention{
+			UserID:     userID,
+			AuthorID:   authorID,
+			TaskID:     task.ID,
+			SourceType: sourceType,
+			SourceID:   sourceID,
+		})
+	}
+	if len(created) > 0 {
+		if err := tx.Create(&created).Error; err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+// Notify отправляет упомянутым уведомление в личный канал и письмо.
+// Вызывается после коммита, ошибки только логируются
+func (s *mentionService) Notify(mentions []model.Mention, body string) {
+	if len(mentions) == 0 {
+		return
+	}
+	ctx := context.Background()
+
+	var author model.User
+	if err := s.DB.WithContext(ctx).Select("id", "name").First(&author, "id = ?", mentions[0].AuthorID).Error; err != nil {
+		s.Log.Errorf("Failed to load mention author: %+v", err)
+		return
+	}
+	var task model.Task
+	if err := s.DB.WithContext(ctx).Select("id", "title", "project_id").First(&task, "id = ?", mentions[0].TaskID).Error; err != nil {
+		s.Log.Errorf("Failed to load mentioned task: %+v", err)
+		return
+	}
+
+	excerpt := []rune(body)
+	if len(excerpt) > mentionExcerptLen {
+		excerpt = append(excerpt[:mentionExcerptLen], '…')
+	}
+
+	for i := range mentions {
+		mention := mentions[i]
+		mention.Author = &author
+		mention.Task = &task
+		if err := publishMessage(ctx, s.Redis, notificationsChannel(mention.UserID), WSMessage{
+			Entity:    "mention",
+			Action:    "created",
+			Data:      mention,
+			Timestamp: time.Now(),
+		}); err != nil {
+			s.Log.Errorf("Failed to publish mention: %v", err)
+		}
+
+		var user model.User
+		if err := s.DB.WithContext(ctx).Select("id", "email").First(&user, "id = ?", mention.UserID).Error; err != nil {
+			s.Log.Errorf("Failed to load mentioned user: %+v", err)
+			continue
+		}
+		subject := fmt.Sprintf("%s mentioned you in %q", author.Name, task.Title)
+		text := fmt.Sprintf("%s mentioned you in %q:\n\n%s", author.Name, task.Title, string(excerpt))
+		// Ошибку отправки уже залогировал EmailService
+		_ = s.EmailService.SendEmail(user.Email, subject, text)
+	}
+}
+
+// GetUserMentions возвращает упоминания пользователя от новых к старым. Задачи из корзины не попадают
+func (s *mentionService) GetUserMentions(
+	c *fiber.Ctx, userID uuid.UUID, params *validation.QueryMentions,
+) ([]model.Mention, string, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, "", err
+	}
+
+	query := s.DB.WithContext(c.Context()).
+		Where("mentions.user_id = ?", userID).
+		Where("EXISTS (SELECT 1 FROM tasks WHERE tasks.id = mentions.task_id AND tasks.deleted_at IS NULL)")
+	if params.Unread {
+		query = query.Where("mentions.read_at IS NULL")
+	}
+	if params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		value, err := cursorValue("time", cursor.Value)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		query = query.Where("(mentions.created_at < @value OR (mentions.created_at = @value AND mentions.id < @id))",
+			sql.Named("value", value), sql.Named("id", cursor.ID))
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultMentionLimit
+	}
+
+	var mentions []model.Mention
+	if err := query.
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "email")
+		}).
+		Preload("Task", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title", "project_id")
+		}).
+		Order("mentions.created_at DESC, mentions.id DESC").
+		Limit(limit + 1).
+		Find(&mentions).Error; err != nil {
+		s.Log.Errorf("Failed to get mentions: %+v", err)
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(mentions) > limit {
+		mentions = mentions[:limit]
+		last := mentions[limit-1]
+		encoded, err := utils.EncodeCursor(last.CreatedAt, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		nextCursor = encoded
+	}
+	return mentions, nextCursor, nil
+}
+
+func (s *mentionService) MarkRead(c *fiber.Ctx, mentionID, userID uuid.UUID) error {
+	var mention model.Mention
+	if err := s.DB.WithContext(c.Context()).
+		First(&mention, "id = ? AND user_id = ?", mentionID, userID).Error; err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Mention not found")
+	}
+	if mention.ReadAt != nil {
+		return nil
+	}
+	return s.DB.WithContext(c.Context()).Model(&mention).Update("read_at", time.Now()).Error
+}
+
+// HandleNotifications пересылает в сокет личные уведомления пользователя. Канал только на чтение
+func (s *mentionService) HandleNotifications(c *websocket.Conn, userID uuid.UUID) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer c.Close()
+
+	pubsub := s.Redis.Subscribe(ctx, notificationsChannel(userID))
+	defer pubsub.Close()
+	ch := pubsub.Channel()
+
+	// Входящие сообщения не нужны, чтение только отслеживает закрытие сокета
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-ch:
+			if err := c.WriteJSON(WSMessage{
+				Timestamp: time.Now(),
+				Data:      json.RawMessage(msg.Payload),
+			}); err != nil {
+				s.Log.Errorf("WebSocket write error: %v", err)
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
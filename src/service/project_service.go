@@ -2,9 +2,18 @@ package service
 
 import (
 	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
 	"app/src/validation"
+	"bufio"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
 
 	"time"
 
@@ -15,6 +24,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskService interface {
@@ -25,40 +35,70 @@ type TaskService interface {
 	CreateUserGroup(c *fiber.Ctx, req *validation.CreateUserGroup) (*model.UserGroup, error)
 	AddUserToGroup(c *fiber.Ctx, req *validation.AddUserToGroup) error
 	AddGroupToProject(c *fiber.Ctx, req *validation.AddGroupToProject) error
-	AddGroupToTask(c *fiber.Ctx, req *validation.AddGroupToTask) error
+	AddGroupToTask(c *fiber.Ctx, req *validation.AddGroupToTask, userID uuid.UUID) error
 	GetUserGroups(c *fiber.Ctx) ([]model.UserGroup, error)
 	GetUsersInGroup(c *fiber.Ctx, req *validation.GetUsersInGroup) ([]model.User, error)
 	HandleTaskUpdates(c *websocket.Conn)
 	HandleProjectUpdates(c *websocket.Conn)
-	GetUserTasks(userID uuid.UUID) ([]model.Task, error)
+	GetUserTasks(c *fiber.Ctx, userID uuid.UUID, params *validation.QueryTask) ([]model.Task, string, error)
 	HandleCommentUpdates(c *websocket.Conn)
 	GetUserProjects(userID uuid.UUID) ([]model.Project, error)
-	UpdateTaskTitleOrDescription(c *fiber.Ctx, taskID uuid.UUID, title, description string) error
-	CreateComment(c *fiber.Ctx, req *validation.CreateComment, userID uuid.UUID) (*model.Comment, error)
-	ReassignTask(c *fiber.Ctx, req validation.ReassignTaskValidation) error
-	GetTaskByID(taskID uuid.UUID) (*model.Task, error)
-	DeleteTask(taskID uuid.UUID) error
+	UpdateTaskTitleOrDescription(c *fiber.Ctx, taskID uuid.UUID, title, description string, userID uuid.UUID) error
+	ReassignTask(c *fiber.Ctx, req validation.ReassignTaskValidation, userID uuid.UUID) error
+	GetTaskByID(c *fiber.Ctx, taskID, userID uuid.UUID) (*model.Task, error)
+	GetTaskHistory(c *fiber.Ctx, taskID uuid.UUID, params *validation.QueryTaskHistory, userID uuid.UUID) ([]model.TaskHistory, string, error)
+	DeleteTask(taskID, userID uuid.UUID) error
 	GetSectionsByProject(projectID uuid.UUID) ([]model.Section, error)
 	GetSectionsByUser(userID uuid.UUID) ([]model.UserSection, error)
-	DeleteSection(sectionID uuid.UUID) error
-
+	DeleteSection(sectionID, userID uuid.UUID) error
+	DeleteProject(projectID, userID uuid.UUID) error
+	GetSubtaskTree(c *fiber.Ctx, taskID, userID uuid.UUID) (*model.Task, error)
+	MoveSubtask(c *fiber.Ctx, taskID uuid.UUID, req *validation.MoveSubtask, userID uuid.UUID) (*model.Task, error)
+	MoveTask(c *fiber.Ctx, taskID uuid.UUID, req *validation.MoveTask, userID uuid.UUID) (*model.Task, string, error)
+	GetBoard(c *fiber.Ctx, projectID uuid.UUID, params *validation.QueryBoard, userID uuid.UUID) (*response.Board, error)
+	SetSectionWIPLimit(c *fiber.Ctx, sectionID uuid.UUID, req *validation.SectionWIPLimit, userID uuid.UUID) (*model.Section, error)
+	BulkUpdateTasks(c *fiber.Ctx, req *validation.BulkTask, userID uuid.UUID) (*response.BulkTasks, error)
+	UpdateTaskStatus(c *fiber.Ctx, taskID uuid.UUID, req *validation.UpdateTaskStatus, userID uuid.UUID) (*model.Task, error)
+	UpdateTaskFromTodo(c *fiber.Ctx, taskID uuid.UUID, req *validation.UpdateTaskTodo, userID uuid.UUID) (*model.Task, error)
+	DeleteTaskFromTodo(c *fiber.Ctx, taskID uuid.UUID, etag string, userID uuid.UUID) error
+	ExportTasksCSV(c *fiber.Ctx, projectID, userID uuid.UUID) (func(w *bufio.Writer), error)
+	ImportTasksCSV(c *fiber.Ctx, projectID uuid.UUID, file io.Reader, req *validation.ImportTasks, userID uuid.UUID) (*response.TaskImport, error)
 }
 
-func NewTaskService(db *gorm.DB, validate *validator.Validate, redisClient *redis.Client) TaskService {
+func NewTaskService(
+	db *gorm.DB, validate *validator.Validate, redisClient *redis.Client,
+	workflowService WorkflowService, taskLinkService TaskLinkService, recurrenceService RecurrenceService,
+	templateService TemplateService, customFieldService CustomFieldService, auditService AuditService,
+	mentionService MentionService,
+) TaskService {
 	return &taskService{
-		Log:      logrus.New(),
-		DB:       db,
-		Validate: validate,
-		Redis:    redisClient,
+		Log:                logrus.New(),
+		DB:                 db,
+		Validate:           validate,
+		Redis:              redisClient,
+		WorkflowService:    workflowService,
+		TaskLinkService:    taskLinkService,
+		RecurrenceService:  recurrenceService,
+		TemplateService:    templateService,
+		CustomFieldService: customFieldService,
+		AuditService:       auditService,
+		MentionService:     mentionService,
 	}
 }
 
 type taskService struct {
-	Log       *logrus.Logger
-	DB        *gorm.DB
-	Validate  *validator.Validate
-	Redis     *redis.Client
-	WebSocket *websocket.Conn
+	Log                *logrus.Logger
+	DB                 *gorm.DB
+	Validate           *validator.Validate
+	Redis              *redis.Client
+	WebSocket          *websocket.Conn
+	WorkflowService    WorkflowService
+	TaskLinkService    TaskLinkService
+	RecurrenceService  RecurrenceService
+	TemplateService    TemplateService
+	CustomFieldService CustomFieldService
+	AuditService       AuditService
+	MentionService     MentionService
 }
 
 
@@ -85,25 +125,44 @@ func (s *taskService) CreateProject(c *fiber.Ctx, req *validation.CreateProject,
 		Title: req.Title,
 	}
 
-	tx := s.DB.WithContext(c.Context()).Begin()
-	if err := tx.Create(project).Error; err != nil {
-		tx.Rollback()
-		s.Log.Errorf("Failed to create project: %+v", err)
-		return nil, err
-	}
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
 
-	// Добавляем пользователя в проект
-	projectUser := &model.ProjectUser{
-		ProjectID: project.ID,
-		UserID:    userID,
-	}
-	if err := tx.Create(projectUser).Error; err != nil {
-		tx.Rollback()
-		s.Log.Errorf("Failed to add user to project: %+v", err)
+		// Добавляем пользователя в проект
+		projectUser := &model.ProjectUser{
+			ProjectID: project.ID,
+			UserID:    userID,
+		}
+		if err := tx.Create(projectUser).Error; err != nil {
+			return err
+		}
+		// Автор проекта может менять его настройки
+		permission := &model.ProjectPermission{
+			ProjectID: project.ID,
+			UserID:    userID,
+			Role:      model.ProjectRoleOwner,
+		}
+		if err := tx.Create(permission).Error; err != nil {
+			return err
+		}
+
+		// Секции, задачи и группы из шаблона
+		if req.TemplateID == nil {
+			return nil
+		}
+		start := time.Now()
+		if req.StartDate != nil {
+			start = *req.StartDate
+		}
+		return s.TemplateService.ApplyProjectTemplate(tx, project, *req.TemplateID, start, userID)
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to create project: %+v", err)
 		return nil, err
 	}
 
-	tx.Commit()
 	return project, nil
 }
 func (s *taskService) CreateTask(c *fiber.Ctx, req *validation.CreateTask, userID uuid.UUID) (*model.Task, error) {
@@ -111,9 +170,8 @@ func (s *taskService) CreateTask(c *fiber.Ctx, req *validation.CreateTask, userI
 		return nil, err
 	}
 
-	var project model.Project
-	if err := s.DB.First(&project, "id = ?", req.ProjectID).Error; err != nil {
-		return nil, fiber.NewError(fiber.StatusNotFound, "Project not found")
+	if _, err := findAccessibleProject(s.DB, req.ProjectID, userID); err != nil {
+		return nil, err
 	}
 
 	// Назначаем создателя задачи, если не передан другой пользователь
@@ -131,19 +189,69 @@ func (s *taskService) CreateTask(c *fiber.Ctx, req *validation.CreateTask, userI
 		return nil, fiber.NewError(fiber.StatusNotFound, "User section not found")
 	}
 
+	// На доске проекта задача попадает в первую секцию
+	var section model.Section
+	if err := s.DB.Where("project_id = ?", req.ProjectID).Order(`"order", created_at`).First(&section).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Project has no sections")
+	}
+
+	// Проверяем родительскую задачу, если это подзадача
+	if req.ParentTaskID != nil {
+		if err := s.checkParentTask(s.DB, *req.ParentTaskID, req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Новая задача получает начальный статус воркфлоу проекта
+	status, err := s.WorkflowService.InitialStatus(s.DB, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Создаем таск
 	task := &model.Task{
-		Title:       req.Title,
-		Description: req.Description,
-		ProjectID:   req.ProjectID,
-		AssignedTo:  assignedTo,
-		SectionID:   userSection.ID, // Назначаем в первую секцию
+		Title:         req.Title,
+		Description:   req.Description,
+		ProjectID:     req.ProjectID,
+		Status:        status,
+		AssignedTo:    assignedTo,
+		SectionID:     section.ID,
+		UserSectionID: &userSection.ID,
+		ParentTaskID:  req.ParentTaskID,
+		EstimatedTime: req.EstimatedTime,
 	}
 
-	if err := s.DB.WithContext(c.Context()).Create(task).Error; err != nil {
+	var mentions []model.Mention
+	err = s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		// Новая задача встаёт в конец секции проекта и секции исполнителя
+		var err error
+		if task.Rank, err = sectionScope(task.SectionID).last(tx); err != nil {
+			return err
+		}
+		if task.UserRank, err = userSectionScope(userSection.ID).last(tx); err != nil {
+			return err
+		}
+		if task.CustomFields, err = s.CustomFieldService.ValidateValues(tx, task.ProjectID, nil, req.CustomFields); err != nil {
+			return err
+		}
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		if err := recordHistory(tx, task.ID, userID, historyCreated, diffTasks(&model.Task{}, task)); err != nil {
+			return err
+		}
+		if mentions, err = s.MentionService.SyncMentions(
+			tx, model.MentionSourceTask, task.ID, task, userID, task.Description,
+		); err != nil {
+			return err
+		}
+		return rollUpTimes(tx, task.ParentTaskID)
+	})
+	if err != nil {
 		s.Log.Errorf("Failed to create task: %+v", err)
 		return nil, err
 	}
+	go s.MentionService.Notify(mentions, task.Description)
 
 	// Отправка WebSocket-обновления
 	go s.publishUpdate(context.Background(), commentUpdatesChannel, WSMessage{
@@ -156,12 +264,7 @@ func (s *taskService) CreateTask(c *fiber.Ctx, req *validation.CreateTask, userI
 }
 
 // Функция переназначения таска
-func (s *taskService) ReassignTask(c *fiber.Ctx, req validation.ReassignTaskValidation) error {
-	var task model.Task
-	if err := s.DB.First(&task, "id = ?", req.TaskID).Error; err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Task not found")
-	}
-
+func (s *taskService) ReassignTask(c *fiber.Ctx, req validation.ReassignTaskValidation, userID uuid.UUID) error {
 	// Ищем новую секцию "Recently Assigned" для нового исполнителя
 	var userSection model.UserSection
 	if err := s.DB.
@@ -171,11 +274,27 @@ func (s *taskService) ReassignTask(c *fiber.Ctx, req validation.ReassignTaskVali
 		return fiber.NewError(fiber.StatusNotFound, "User section not found")
 	}
 
-	// Обновляем исполнителя и секцию
-	task.AssignedTo = &req.NewUserID
-	task.SectionID = userSection.ID
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), req.TaskID, userID); err != nil {
+			return err
+		}
 
-	if err := s.DB.WithContext(c.Context()).Save(&task).Error; err != nil {
+		// Обновляем исполнителя и его личную секцию, секция проекта остаётся прежней
+		rank, err := userSectionScope(userSection.ID).last(tx)
+		if err != nil {
+			return err
+		}
+		before := *task
+		task.AssignedTo = &req.NewUserID
+		task.UserSectionID, task.UserRank = &userSection.ID, rank
+		if err := tx.Save(task).Error; err != nil {
+			return err
+		}
+		return recordHistory(tx, task.ID, userID, historyReassigned, diffTasks(&before, task))
+	})
+	if err != nil {
 		s.Log.Errorf("Failed to reassign task: %+v", err)
 		return err
 	}
@@ -220,7 +339,7 @@ func (s *taskService) GetUsersWithAccess(taskID uuid.UUID) []model.User {
         FROM users u
         INNER JOIN user_groups ug ON u.id = ug.user_id
         INNER JOIN tasks t ON ug.id = t.user_group
-        WHERE t.id = ?
+        WHERE t.id = ? AND t.deleted_at IS NULL
     `, taskID).Scan(&users).Error
 
 	if err != nil {
@@ -231,24 +350,295 @@ func (s *taskService) GetUsersWithAccess(taskID uuid.UUID) []model.User {
 	return users
 }
 
-func (s *taskService) GetUserTasks(userID uuid.UUID) ([]model.Task, error) {
-	var tasks []model.Task
+// Задачи, доступные пользователю @user: назначенные ему, где он участник, или из его проектов
+const taskAccessCondition = `(
+	tasks.assigned_to = @user
+	OR EXISTS (SELECT 1 FROM task_users tu WHERE tu.task_id = tasks.id AND tu.user_id = @user)
+	OR EXISTS (SELECT 1 FROM project_users pu WHERE pu.project_id = tasks.project_id AND pu.user_id = @user)
+)`
 
-	// Проверяем, существует ли пользователь
-	var user model.User
-	if err := s.DB.First(&user, "id = ?", userID).Error; err != nil {
-		return nil, fiber.NewError(fiber.StatusNotFound, "Group not found")
+// findAccessibleTask возвращает задачу, если она доступна пользователю
+func findAccessibleTask(db *gorm.DB, taskID, userID uuid.UUID) (*model.Task, error) {
+	var task model.Task
+	if err := db.Where("tasks.id = ?", taskID).
+		Where(taskAccessCondition, sql.Named("user", userID)).
+		First(&task).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Task not found")
+	}
+	return &task, nil
+}
+
+// findAccessibleProject возвращает проект, если пользователь в нём участвует
+func findAccessibleProject(db *gorm.DB, projectID, userID uuid.UUID) (*model.Project, error) {
+	var project model.Project
+	if err := db.Where("projects.id = ?", projectID).
+		Where("EXISTS (SELECT 1 FROM project_users pu WHERE pu.project_id = projects.id AND pu.user_id = ?)", userID).
+		First(&project).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Project not found")
 	}
+	return &project, nil
+}
 
-	// Получаем задачи, связанные с этим пользователем
-	if err := s.DB.
-		Joins("JOIN user_tasks ON user_tasks.task_id = tasks.id").
-		Where("user_tasks.user_id = ?", userID).
+// findManageableProject возвращает проект, если пользователь может менять его настройки:
+// он владелец или менеджер проекта. В проектах без назначенных ролей (созданных до их появления)
+// настройки может менять любой участник
+func findManageableProject(db *gorm.DB, projectID, userID uuid.UUID) (*model.Project, error) {
+	project, err := findAccessibleProject(db, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var allowed bool
+	if err := db.Raw(`SELECT NOT EXISTS (SELECT 1 FROM project_permissions WHERE project_id = @project)
+		OR EXISTS (
+			SELECT 1 FROM project_permissions
+			WHERE project_id = @project AND user_id = @user AND role IN @roles
+		)`,
+		sql.Named("project", projectID),
+		sql.Named("user", userID),
+		sql.Named("roles", []string{model.ProjectRoleOwner, model.ProjectRoleManager}),
+	).Scan(&allowed).Error; err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fiber.NewError(fiber.StatusForbidden, "You don't have permission to manage this project")
+	}
+	return project, nil
+}
+
+// Колонки, по которым можно сортировать задачи, и тип их значений в курсоре
+var sortableTaskColumns = map[string]string{
+	"created_at":     "time",
+	"updated_at":     "time",
+	"due_date":       "time",
+	"title":          "string",
+	"status":         "string",
+	"priority":       "string",
+	"estimated_time": "int",
+	"spent_time":     "int",
+}
+
+const defaultTaskLimit = 20
+
+func (s *taskService) GetUserTasks(
+	c *fiber.Ctx, userID uuid.UUID, params *validation.QueryTask,
+) ([]model.Task, string, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, "", err
+	}
+
+	query := s.DB.WithContext(c.Context()).Model(&model.Task{}).
+		Where(taskAccessCondition, sql.Named("user", userID))
+
+	if params.Status != "" {
+		query = query.Where("tasks.status IN ?", strings.Split(params.Status, ","))
+	}
+	if params.Priority != "" {
+		query = query.Where("tasks.priority IN ?", strings.Split(params.Priority, ","))
+	}
+	if params.ProjectID != "" {
+		query = query.Where("tasks.project_id = ?", params.ProjectID)
+	}
+	if params.SectionID != "" {
+		query = query.Where("tasks.section_id = ?", params.SectionID)
+	}
+	if params.AssignedTo != "" {
+		query = query.Where("tasks.assigned_to = ?", params.AssignedTo)
+	}
+	if params.ParentTaskID == "null" {
+		query = query.Where("tasks.parent_task_id IS NULL")
+	} else if params.ParentTaskID != "" {
+		query = query.Where("tasks.parent_task_id = ?", params.ParentTaskID)
+	}
+	if params.DueFrom != "" {
+		dueFrom, _ := time.Parse(time.DateOnly, params.DueFrom)
+		query = query.Where("tasks.due_date >= ?", dueFrom)
+	}
+	if params.DueTo != "" {
+		dueTo, _ := time.Parse(time.DateOnly, params.DueTo)
+		query = query.Where("tasks.due_date < ?", dueTo.AddDate(0, 0, 1))
+	}
+	if params.Labels != "" {
+		labelIDs := make([]uuid.UUID, 0)
+		seen := make(map[uuid.UUID]bool)
+		for _, id := range strings.Split(params.Labels, ",") {
+			labelID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid label ID")
+			}
+			if !seen[labelID] {
+				seen[labelID] = true
+				labelIDs = append(labelIDs, labelID)
+			}
+		}
+		if params.LabelMatch == "all" {
+			query = query.Where(`(SELECT COUNT(*) FROM task_labels tl
+				WHERE tl.task_id = tasks.id AND tl.label_id IN ?) = ?`, labelIDs, len(labelIDs))
+		} else {
+			query = query.Where("EXISTS (SELECT 1 FROM task_labels tl WHERE tl.task_id = tasks.id AND tl.label_id IN ?)", labelIDs)
+		}
+	}
+
+	for fieldID, value := range params.CustomFields {
+		field, err := s.customField(s.DB.WithContext(c.Context()), fieldID)
+		if err != nil {
+			return nil, "", err
+		}
+		if query, err = filterByCustomField(query, field, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Сортировка: "due_date" или "-due_date", по пользовательскому полю - "cf.<id>"
+	column, desc := "created_at", false
+	if params.Sort != "" {
+		column = strings.TrimPrefix(params.Sort, "-")
+		desc = strings.HasPrefix(params.Sort, "-")
+	}
+	expr := "tasks." + column
+	kind, ok := sortableTaskColumns[column]
+	var sortField *model.CustomField
+	if fieldID, isCustom := strings.CutPrefix(column, "cf."); isCustom {
+		field, err := s.customField(s.DB.WithContext(c.Context()), fieldID)
+		if err != nil {
+			return nil, "", err
+		}
+		sortField, expr = field, customFieldColumn(field)
+		kind, ok = customFieldSortKind(field)
+	}
+	if !ok {
+		return nil, "", fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Cannot sort by %q", column))
+	}
+	direction, compare := "ASC", ">"
+	if desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		// NULL-значения идут в конце выборки
+		if cursor.Value == nil {
+			query = query.Where(fmt.Sprintf("%s IS NULL AND tasks.id %s ?", expr, compare), cursor.ID)
+		} else {
+			value, err := cursorValue(kind, cursor.Value)
+			if err != nil {
+				return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+			}
+			query = query.Where(fmt.Sprintf(
+				"(%[1]s %[2]s @value OR (%[1]s = @value AND tasks.id %[2]s @id) OR %[1]s IS NULL)",
+				expr, compare,
+			), sql.Named("value", value), sql.Named("id", cursor.ID))
+		}
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultTaskLimit
+	}
+
+	var tasks []model.Task
+	if err := query.
+		Preload("Labels").
+		Order(fmt.Sprintf("%s %s NULLS LAST, tasks.id %s", expr, direction, direction)).
+		Limit(limit + 1).
 		Find(&tasks).Error; err != nil {
-		return nil, fiber.NewError(fiber.StatusNotFound, "Group not found")
+		s.Log.Errorf("Failed to get user tasks: %+v", err)
+		return nil, "", err
 	}
 
-	return tasks, nil
+	// Следующая страница есть, если вернулось больше limit записей
+	var nextCursor string
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		last := tasks[limit-1]
+		value := taskSortValue(&last, column)
+		if sortField != nil {
+			value = last.CustomFields[sortField.ID.String()]
+		}
+		encoded, err := utils.EncodeCursor(value, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		nextCursor = encoded
+	}
+
+	if err := s.markBlocked(s.DB.WithContext(c.Context()), tasks); err != nil {
+		return nil, "", err
+	}
+
+	return tasks, nextCursor, nil
+}
+
+// customField загружает определение пользовательского поля для фильтра или сортировки
+func (s *taskService) customField(db *gorm.DB, fieldID string) (*model.CustomField, error) {
+	id, err := uuid.Parse(fieldID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid custom field ID")
+	}
+	var field model.CustomField
+	if err := db.First(&field, "id = ?", id).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown custom field")
+	}
+	return &field, nil
+}
+
+func taskSortValue(task *model.Task, column string) interface{} {
+	switch column {
+	case "updated_at":
+		return task.UpdatedAt
+	case "due_date":
+		if task.DueDate == nil {
+			return nil
+		}
+		return *task.DueDate
+	case "title":
+		return task.Title
+	case "status":
+		return task.Status
+	case "priority":
+		return task.Priority
+	case "estimated_time":
+		return task.EstimatedTime
+	case "spent_time":
+		return task.SpentTime
+	default:
+		return task.CreatedAt
+	}
+}
+
+// cursorValue приводит значение из JSON курсора к типу колонки
+func cursorValue(kind string, value interface{}) (interface{}, error) {
+	switch kind {
+	case "time":
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("cursor value is not a time")
+		}
+		return time.Parse(time.RFC3339Nano, str)
+	case "int", "float":
+		number, ok := value.(float64)
+		if !ok {
+			return nil, errors.New("cursor value is not a number")
+		}
+		if kind == "float" {
+			return number, nil
+		}
+		return int(number), nil
+	case "bool":
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("cursor value is not a boolean")
+		}
+		return flag, nil
+	default:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("cursor value is not a string")
+		}
+		return str, nil
+	}
 }
 
 func (s *taskService) CreateUserGroup(c *fiber.Ctx, req *validation.CreateUserGroup) (*model.UserGroup, error) {
@@ -284,11 +674,20 @@ func (s *taskService) AddUserToGroup(c *fiber.Ctx, req *validation.AddUserToGrou
 	}
 
 	// Добавляем пользователя в группу
-	if err := s.DB.Exec("INSERT INTO user_group_users (user_group_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", req.GroupID, req.UserID).Error; err != nil {
+	result := s.DB.Exec("INSERT INTO user_group_users (user_group_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", req.GroupID, req.UserID)
+	if err := result.Error; err != nil {
 		s.Log.Errorf("Failed to add user to group: %+v", err)
 		return err
 	}
 
+	if result.RowsAffected > 0 {
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditGroupMemberAdd,
+			EntityType: "user_group",
+			EntityID:   &group.ID,
+			Details:    map[string]interface{}{"user_id": user.ID},
+		})
+	}
 	return nil
 }
 
@@ -314,16 +713,22 @@ func (s *taskService) AddGroupToProject(c *fiber.Ctx, req *validation.AddGroupTo
 		return err
 	}
 
+	s.AuditService.Record(c, &model.AuditLog{
+		ActionType: model.AuditProjectGrant,
+		EntityType: "project",
+		EntityID:   &project.ID,
+		Details:    map[string]interface{}{"group_id": group.ID},
+	})
 	return nil
 }
-func (s *taskService) AddGroupToTask(c *fiber.Ctx, req *validation.AddGroupToTask) error {
+func (s *taskService) AddGroupToTask(c *fiber.Ctx, req *validation.AddGroupToTask, userID uuid.UUID) error {
 	if err := s.Validate.Struct(req); err != nil {
 		return err
 	}
-	// Проверяем, существует ли задача
-	var task model.Task
-	if err := s.DB.First(&task, "id = ?", req.TaskID).Error; err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Task not found")
+	// Проверяем, что задача доступна пользователю
+	task, err := findAccessibleTask(s.DB.WithContext(c.Context()), req.TaskID, userID)
+	if err != nil {
+		return err
 	}
 
 	// Проверяем, существует ли группа
@@ -333,12 +738,19 @@ func (s *taskService) AddGroupToTask(c *fiber.Ctx, req *validation.AddGroupToTas
 	}
 
 	// Добавляем группу к задаче
-	if err := s.DB.Model(&task).Association("UserGroups").Append(&group); err != nil {
-
+	if err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		return addTaskGroup(tx, task, &group, userID)
+	}); err != nil {
 		s.Log.Errorf("Failed to add group to task: %+v", err)
 		return err
 	}
 
+	s.AuditService.Record(c, &model.AuditLog{
+		ActionType: model.AuditTaskGrant,
+		EntityType: "task",
+		EntityID:   &task.ID,
+		Details:    map[string]interface{}{"group_id": group.ID},
+	})
 	return nil
 }
 func (s *taskService) GetUserGroups(c *fiber.Ctx) ([]model.UserGroup, error) {
@@ -377,7 +789,14 @@ func (s *taskService) GetUserProjects(userID uuid.UUID) ([]model.Project, error)
 	return projects, nil
 }
 
-func (s *taskService) UpdateTaskTitleOrDescription(c *fiber.Ctx, taskID uuid.UUID, title, description string) error {
+func (s *taskService) UpdateTaskTitleOrDescription(
+	c *fiber.Ctx, taskID uuid.UUID, title, description string, userID uuid.UUID,
+) error {
+	// Чужую задачу не показываем подписчикам и не сохраняем
+	if _, err := findAccessibleTask(s.DB.WithContext(c.Context()), taskID, userID); err != nil {
+		return err
+	}
+
 	// Публикуем изменение в Redis
 	err := s.Redis.Publish(c.Context(), taskUpdatesChannel, map[string]interface{}{
 		"task_id":     taskID,
@@ -391,11 +810,11 @@ func (s *taskService) UpdateTaskTitleOrDescription(c *fiber.Ctx, taskID uuid.UUI
 	}
 
 	// Запланировать сохранение в Postgres с дебаунсингом
-	go s.debouncedSaveToPostgres(taskID, title, description)
+	go s.debouncedSaveToPostgres(taskID, title, description, userID)
 	return nil
 }
 
-func (s *taskService) debouncedSaveToPostgres(taskID uuid.UUID, title, description string) {
+func (s *taskService) debouncedSaveToPostgres(taskID uuid.UUID, title, description string, userID uuid.UUID) {
 	// Создаем уникальный ключ для дебаунсинга
 	key := "task_debounce:" + taskID.String()
 
@@ -411,18 +830,36 @@ func (s *taskService) debouncedSaveToPostgres(taskID uuid.UUID, title, descripti
 		return
 	}
 
-	// Сохраняем в Postgres
-	err := s.DB.Model(&model.Task{}).
-		Where("id = ?", taskID).
-		Updates(map[string]interface{}{
+	// Сохраняем в Postgres вместе с диффом в истории
+	var mentions []model.Mention
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Доступ мог пропасть, пока шёл дебаунс
+		task, err := findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID)
+		if err != nil {
+			return err
+		}
+		before := *task
+		task.Title, task.Description = title, description
+		if err := tx.Model(task).Updates(map[string]interface{}{
 			"title":       title,
 			"description": description,
 			"updated_at":  time.Now(),
-		}).Error
+		}).Error; err != nil {
+			return err
+		}
+		if err := recordHistory(tx, taskID, userID, historyUpdated, diffTasks(&before, task)); err != nil {
+			return err
+		}
+		// Уведомление получают только новые упомянутые
+		mentions, err = s.MentionService.SyncMentions(tx, model.MentionSourceTask, taskID, task, userID, description)
+		return err
+	})
 
 	if err != nil {
 		s.Log.Errorf("Failed to save task updates: %v", err)
+		return
 	}
+	s.MentionService.Notify(mentions, description)
 }
 // Общий обработчик WebSocket
 func (s *taskService) HandleUpdates(c *websocket.Conn, channels ...string) {
@@ -459,7 +896,7 @@ func (s *taskService) HandleUpdates(c *websocket.Conn, channels ...string) {
                 Timestamp: time.Now(),
                 Data:      json.RawMessage(msg.Payload),
             }); err != nil {
-                s.Log.Errorf("WebSocket write error:", err)
+                s.Log.Errorf("WebSocket write error: %v", err)
                 continue
             }
         case <-ctx.Done():
@@ -469,11 +906,16 @@ func (s *taskService) HandleUpdates(c *websocket.Conn, channels ...string) {
 }
 // Вспомогательный метод для публикации обновлений
 func (s *taskService) publishUpdate(ctx context.Context, channel string, data interface{}) error {
-    payload, err := json.Marshal(data)
-    if err != nil {
-        return err
-    }
-    return s.Redis.Publish(ctx, channel, payload).Err()
+    return publishMessage(ctx, s.Redis, channel, data)
+}
+
+// publishMessage публикует сообщение в канал Redis, который раздают WebSocket-обработчики
+func publishMessage(ctx context.Context, rdb *redis.Client, channel string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return rdb.Publish(ctx, channel, payload).Err()
 }
 
 // Обновленные обработчики для конкретных сущностей
@@ -489,65 +931,66 @@ func (s *taskService) HandleProjectUpdates(c *websocket.Conn) {
     s.HandleUpdates(c, projectUpdatesChannel)
 }
 
-func (s *taskService) CreateComment(c *fiber.Ctx, req *validation.CreateComment, userID uuid.UUID) (*model.Comment, error) {
-	if err := s.Validate.Struct(req); err != nil {
+// Реализация в taskService:
+func (s *taskService) GetTaskByID(c *fiber.Ctx, taskID, userID uuid.UUID) (*model.Task, error) {
+	db := s.DB.WithContext(c.Context())
+	task, err := findAccessibleTask(db.
+		// Ответы и правки отдаёт API комментариев, здесь остаются корневые, как раньше
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Where("reply_to_id IS NULL").Order("created_at, id")
+		}).
+		Preload("Comments.User").
+		Preload("UserGroups"), taskID, userID)
+	if err != nil {
 		return nil, err
 	}
 
-	comment := &model.Comment{
-		Body:   req.Body,
-		TaskID: req.TaskID,
-		UserID: userID,
-	}
-
-	if err := s.DB.WithContext(c.Context()).Create(comment).Error; err != nil {
-		s.Log.Errorf("Ошибка создания комментария: %+v", err)
+	blockers, err := s.TaskLinkService.OpenBlockers(db, task.ID)
+	if err != nil {
 		return nil, err
 	}
+	task.Blocked = len(blockers[task.ID]) > 0
+	return task, nil
+}
 
-	// Публикация комментария в Redis
-	go s.publishUpdate(context.Background(), commentUpdatesChannel, WSMessage{
-        Entity:    "comment",
-        Action:    "created",
-        Data:      comment,
-        Timestamp: time.Now(),
-    })
-
-	return comment, nil
+func (s *taskService) DeleteTask(taskID, userID uuid.UUID) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return s.deleteTask(tx, taskID, userID, "")
+	})
 }
 
-// Реализация в taskService:
-func (s *taskService) GetTaskByID(taskID uuid.UUID) (*model.Task, error) {
-	var task model.Task
-	if err := s.DB.
-		Preload("Comments").
-		Preload("UserGroups").
-		First(&task, "id = ?", taskID).Error; err != nil {
-		return nil, fiber.NewError(fiber.StatusNotFound, "Task not found")
+// deleteTask переносит задачу в корзину. Непустой etag сверяется с версией задачи под блокировкой строки
+func (s *taskService) deleteTask(tx *gorm.DB, taskID, userID uuid.UUID, etag string) error {
+	task, err := findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID)
+	if err != nil {
+		return err
+	}
+	if etag != "" && etag != "*" && etag != taskETag(task) {
+		return fiber.NewError(fiber.StatusPreconditionFailed, "Task was modified")
 	}
-	return &task, nil
-}
 
-func (s *taskService) DeleteTask(taskID uuid.UUID) error {
-	return s.DB.Transaction(func(tx *gorm.DB) error {
-		// Удаляем связи задачи с группами
-		if err := tx.Exec("DELETE FROM task_user_groups WHERE task_id = ?", taskID).Error; err != nil {
-			return err
-		}
+	// В истории остаются последние значения полей удалённой задачи
+	if err := recordHistory(tx, taskID, userID, historyDeleted, diffTasks(task, &model.Task{})); err != nil {
+		return err
+	}
 
-		// Удаляем саму задачу
-		if err := tx.Delete(&model.Task{}, "id = ?", taskID).Error; err != nil {
-			return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete task")
-		}
-		return nil
-	})
+	// Переносим задачу в корзину вместе с подзадачами, связи и комментарии остаются до очистки
+	if err := trashTasks(tx, "id = @root", taskID, time.Now(), userID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete task")
+	}
+
+	// Пересчитываем время у бывших родителей
+	return rollUpTimes(tx, task.ParentTaskID)
 }
 
 func (s *taskService) GetSectionsByProject(projectID uuid.UUID) ([]model.Section, error) {
 	var sections []model.Section
 	if err := s.DB.
 		Where("project_id = ?", projectID).
-		Preload("Tasks").
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order(sectionScope(uuid.Nil).order())
+		}).
+		Order(`"order", created_at`).
 		Find(&sections).Error; err != nil {
 		return nil, fiber.NewError(fiber.StatusNotFound, "Sections not found")
 	}
@@ -557,33 +1000,385 @@ func (s *taskService) GetSectionsByUser(userID uuid.UUID) ([]model.UserSection,
 	var sections []model.UserSection
 	if err := s.DB.
 		Where("user_id = ?", userID).
-		Preload("Tasks").
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order(userSectionScope(uuid.Nil).order())
+		}).
+		Order(`"order", created_at`).
 		Find(&sections).Error; err != nil {
 		return nil, fiber.NewError(fiber.StatusNotFound, "Sections not found")
 	}
 	return sections, nil
 }
 
-func (s *taskService) DeleteSection(sectionID uuid.UUID) error {
+// DeleteSection переносит секцию в корзину вместе с её задачами
+func (s *taskService) DeleteSection(sectionID, userID uuid.UUID) error {
 	return s.DB.Transaction(func(tx *gorm.DB) error {
-		// Переносим задачи в дефолтную секцию
-		var defaultSection model.Section
-		if err := tx.FirstOrCreate(&defaultSection,
-			model.Section{Title: "Backlog", ProjectID: uuid.Nil}).Error; err != nil {
+		var section model.Section
+		if err := tx.First(&section, "id = ?", sectionID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Section not found")
+		}
+		if _, err := findAccessibleProject(tx, section.ProjectID, userID); err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Section not found")
+		}
+
+		at := time.Now()
+		if err := trashTasks(tx, "section_id = @root", sectionID, at, userID); err != nil {
 			return err
 		}
+		return tx.Model(&section).
+			Updates(map[string]interface{}{"deleted_at": at, "deleted_by": userID}).Error
+	})
+}
 
-		if err := tx.Model(&model.Task{}).
-			Where("section_id = ?", sectionID).
-			Update("section_id", defaultSection.ID).Error; err != nil {
+// DeleteProject переносит проект в корзину вместе с секциями и задачами.
+// Удалить проект может только тот, кто им управляет
+func (s *taskService) DeleteProject(projectID, userID uuid.UUID) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		project, err := findManageableProject(tx, projectID, userID)
+		if err != nil {
 			return err
 		}
 
-		// Удаляем секцию
-		if err := tx.Delete(&model.Section{}, "id = ?", sectionID).Error; err != nil {
+		at := time.Now()
+		if err := trashTasks(tx, "project_id = @root", projectID, at, userID); err != nil {
 			return err
 		}
+		if err := tx.Model(&model.Section{}).Where("project_id = ?", projectID).
+			Updates(map[string]interface{}{"deleted_at": at, "deleted_by": userID}).Error; err != nil {
+			return err
+		}
+		return tx.Model(project).
+			Updates(map[string]interface{}{"deleted_at": at, "deleted_by": userID}).Error
+	})
+}
+
+// GetSubtaskTree возвращает задачу со всем деревом подзадач
+func (s *taskService) GetSubtaskTree(c *fiber.Ctx, taskID, userID uuid.UUID) (*model.Task, error) {
+	root, err := findAccessibleTask(s.DB.WithContext(c.Context()), taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Забираем всех потомков одним рекурсивным запросом
+	var descendants []model.Task
+	if err := s.DB.WithContext(c.Context()).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT * FROM tasks WHERE parent_task_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.* FROM tasks t
+			INNER JOIN subtree st ON t.parent_task_id = st.id
+			WHERE t.deleted_at IS NULL
+		)
+		SELECT * FROM subtree ORDER BY created_at
+	`, taskID).Scan(&descendants).Error; err != nil {
+		s.Log.Errorf("Failed to load subtasks: %+v", err)
+		return nil, err
+	}
+
+	children := make(map[uuid.UUID][]model.Task)
+	for _, task := range descendants {
+		children[*task.ParentTaskID] = append(children[*task.ParentTaskID], task)
+	}
+	attachSubtasks(root, children)
+
+	return root, nil
+}
+
+func attachSubtasks(task *model.Task, children map[uuid.UUID][]model.Task) {
+	task.Subtasks = children[task.ID]
+	for i := range task.Subtasks {
+		attachSubtasks(&task.Subtasks[i], children)
+	}
+}
+
+// MoveSubtask переносит задачу вместе с поддеревом под нового родителя
+func (s *taskService) MoveSubtask(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.MoveSubtask, userID uuid.UUID,
+) (*model.Task, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID); err != nil {
+			return err
+		}
+		before := *task
+
+		if req.ParentTaskID != nil {
+			if *req.ParentTaskID == task.ID {
+				return fiber.NewError(fiber.StatusBadRequest, "Task cannot be its own parent")
+			}
+			if err := s.checkParentTask(tx, *req.ParentTaskID, task.ProjectID, userID); err != nil {
+				return err
+			}
+
+			// Новый родитель не должен находиться внутри переносимого поддерева
+			var ancestorIDs []uuid.UUID
+			if err := tx.Raw(`
+				WITH RECURSIVE ancestors AS (
+					SELECT id, parent_task_id FROM tasks WHERE id = ?
+					UNION ALL
+					SELECT t.id, t.parent_task_id FROM tasks t
+					INNER JOIN ancestors a ON t.id = a.parent_task_id
+				)
+				SELECT id FROM ancestors
+			`, *req.ParentTaskID).Scan(&ancestorIDs).Error; err != nil {
+				return err
+			}
+			for _, id := range ancestorIDs {
+				if id == task.ID {
+					return fiber.NewError(fiber.StatusBadRequest, "Task cannot be moved into its own subtree")
+				}
+			}
+		}
+
+		if err := tx.Model(task).Update("parent_task_id", req.ParentTaskID).Error; err != nil {
+			return err
+		}
+		task.ParentTaskID = req.ParentTaskID
+		if err := recordHistory(tx, task.ID, userID, historyUpdated, diffTasks(&before, task)); err != nil {
+			return err
+		}
+
+		if err := rollUpTimes(tx, before.ParentTaskID); err != nil {
+			return err
+		}
+		return rollUpTimes(tx, task.ParentTaskID)
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to move subtask: %+v", err)
+		return nil, err
+	}
+
+	go s.publishUpdate(context.Background(), taskUpdatesChannel, WSMessage{
+		Entity:    "task",
+		Action:    "moved",
+		Data:      task,
+		Timestamp: time.Now(),
+	})
+	return task, nil
+}
+
+// MoveTask переносит задачу в секцию проекта или пользователя и ставит её рядом с соседом.
+// Вторым значением возвращается предупреждение о превышении WIP-лимита секции
+func (s *taskService) MoveTask(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.MoveTask, userID uuid.UUID,
+) (*model.Task, string, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, "", err
+	}
+
+	var task *model.Task
+	var warning string
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID); err != nil {
+			return err
+		}
+
+		// Блокировка секции упорядочивает параллельные перемещения внутри неё
+		var scope rankScope
+		if req.SectionID != nil {
+			var section model.Section
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&section, "id = ? AND project_id = ?", *req.SectionID, task.ProjectID).Error; err != nil {
+				return fiber.NewError(fiber.StatusNotFound, "Section not found")
+			}
+			if warning, err = s.checkWIPLimit(tx, &section, task); err != nil {
+				return err
+			}
+			if req.Swimlane != "" {
+				if err := s.moveToLane(tx, task, req, userID); err != nil {
+					return err
+				}
+			}
+			scope = sectionScope(section.ID)
+		} else {
+			var section model.UserSection
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&section, "id = ? AND user_id = ?", *req.UserSectionID, userID).Error; err != nil {
+				return fiber.NewError(fiber.StatusNotFound, "User section not found")
+			}
+			scope = userSectionScope(section.ID)
+		}
 
+		rank, err := scope.place(tx, task.ID, req.AfterTaskID, req.BeforeTaskID)
+		if err != nil {
+			return err
+		}
+		if req.SectionID != nil {
+			task.SectionID, task.Rank = scope.ID, rank
+		} else {
+			task.UserSectionID, task.UserRank = &scope.ID, rank
+		}
+		return tx.Model(task).Updates(map[string]interface{}{
+			scope.Container: scope.ID,
+			scope.Column:    rank,
+		}).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to move task: %+v", err)
+		return nil, "", err
+	}
+
+	go s.publishUpdate(context.Background(), taskUpdatesChannel, WSMessage{
+		Entity:    "task",
+		Action:    "reordered",
+		Data:      task,
+		Timestamp: time.Now(),
+	})
+	return task, warning, nil
+}
+
+// checkParentTask проверяет, что родитель доступен пользователю, в том же проекте и ещё не закрыт
+func (s *taskService) checkParentTask(tx *gorm.DB, parentID, projectID, userID uuid.UUID) error {
+	parent, err := findAccessibleTask(tx, parentID, userID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Parent task not found")
+	}
+	if parent.ProjectID != projectID {
+		return fiber.NewError(fiber.StatusBadRequest, "Parent task belongs to another project")
+	}
+	final, err := s.WorkflowService.FinalStatuses(tx, projectID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(final, parent.Status) {
+		return fiber.NewError(fiber.StatusConflict, "Parent task is already done")
+	}
+	return nil
+}
+
+// checkBlockers не даёт закрыть задачу с открытыми блокерами без force
+func (s *taskService) checkBlockers(tx *gorm.DB, task *model.Task, toStatus string, force bool) error {
+	if force {
 		return nil
+	}
+	final, err := s.WorkflowService.FinalStatuses(tx, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(final, toStatus) {
+		return nil
+	}
+
+	blockers, err := s.TaskLinkService.OpenBlockers(tx, task.ID)
+	if err != nil {
+		return err
+	}
+	if open := len(blockers[task.ID]); open > 0 {
+		return fiber.NewError(fiber.StatusConflict,
+			fmt.Sprintf("Task is blocked by %d open task(s), pass force to close it anyway", open))
+	}
+	return nil
+}
+
+// markBlocked выставляет признак Blocked задачам с открытыми блокерами
+func (s *taskService) markBlocked(db *gorm.DB, tasks []model.Task) error {
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	blockers, err := s.TaskLinkService.OpenBlockers(db, ids...)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Blocked = len(blockers[tasks[i].ID]) > 0
+	}
+	return nil
+}
+
+// UpdateTaskStatus переводит задачу в новый статус по воркфлоу проекта
+func (s *taskService) UpdateTaskStatus(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.UpdateTaskStatus, userID uuid.UUID,
+) (*model.Task, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID); err != nil {
+			return err
+		}
+		before := *task
+		if err := s.changeStatus(tx, task, req.Status, req.Force); err != nil {
+			return err
+		}
+		return recordHistory(tx, task.ID, userID, historyStatusChanged, diffTasks(&before, task))
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.publishUpdate(context.Background(), taskUpdatesChannel, WSMessage{
+		Entity:    "task",
+		Action:    "status_changed",
+		Data:      task,
+		Timestamp: time.Now(),
+	})
+	return task, nil
+}
+
+// changeStatus переводит задачу в статус по правилам воркфлоу.
+// Закрытие вхождения повторяющейся серии создаёт следующее
+func (s *taskService) changeStatus(tx *gorm.DB, task *model.Task, status string, force bool) error {
+	if task.Status == status {
+		return nil
+	}
+	if err := s.WorkflowService.CheckTransition(tx, task, status); err != nil {
+		return err
+	}
+	if err := s.checkBlockers(tx, task, status, force); err != nil {
+		return err
+	}
+	task.Status = status
+	if err := tx.Model(task).Update("status", status).Error; err != nil {
+		return err
+	}
+
+	if task.RecurrenceID == nil {
+		return nil
+	}
+	final, err := s.WorkflowService.FinalStatuses(tx, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(final, status) {
+		return nil
+	}
+	return s.RecurrenceService.AdvanceSeries(tx, *task.RecurrenceID, task.ID)
+}
+
+// rollUpTimes пересчитывает EstimatedTime/SpentTime задачи и вверх по цепочке родителей.
+// SpentTime складывается из завершённых записей времени задачи и SpentTime подзадач
+func rollUpTimes(tx *gorm.DB, taskID *uuid.UUID) error {
+	for taskID != nil {
+		if err := tx.Exec(`
+			UPDATE tasks SET
+				estimated_time = CASE WHEN EXISTS (SELECT 1 FROM tasks WHERE parent_task_id = @id AND deleted_at IS NULL)
+					THEN (SELECT COALESCE(SUM(estimated_time), 0) FROM tasks
+						WHERE parent_task_id = @id AND deleted_at IS NULL)
+					ELSE estimated_time END,
+				spent_time = (SELECT COALESCE(SUM(duration), 0) FROM time_entries
+					WHERE task_id = @id AND ended_at IS NOT NULL)
+					+ (SELECT COALESCE(SUM(spent_time), 0) FROM tasks
+						WHERE parent_task_id = @id AND deleted_at IS NULL)
+			WHERE id = @id
+		`, sql.Named("id", *taskID)).Error; err != nil {
+			return err
+		}
+
+		var task model.Task
+		if err := tx.Select("id", "parent_task_id").First(&task, "id = ?", *taskID).Error; err != nil {
+			return err
+		}
+		taskID = task.ParentTaskID
+	}
+	return nil
 }
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurrenceService interface {
+	GetRecurrence(c *fiber.Ctx, taskID, userID uuid.UUID) (*response.Recurrence, error)
+	SetRecurrence(c *fiber.Ctx, taskID uuid.UUID, req *validation.SetRecurrence, userID uuid.UUID) (*response.Recurrence, error)
+	StopRecurrence(c *fiber.Ctx, taskID, userID uuid.UUID) error
+	AddException(c *fiber.Ctx, taskID uuid.UUID, req *validation.AddRecurrenceException, userID uuid.UUID) (*response.Recurrence, error)
+	AdvanceSeries(tx *gorm.DB, recurrenceID, fromTaskID uuid.UUID) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+type recurrenceService struct {
+	Log             *logrus.Logger
+	DB              *gorm.DB
+	Validate        *validator.Validate
+	WorkflowService WorkflowService
+}
+
+func NewRecurrenceService(db *gorm.DB, validate *validator.Validate, workflowService WorkflowService) RecurrenceService {
+	return &recurrenceService{
+		Log:             utils.Log,
+		DB:              db,
+		Validate:        validate,
+		WorkflowService: workflowService,
+	}
+}
+
+// Сколько ближайших вхождений показывать в ответе
+const upcomingOccurrences = 5
+
+func (s *recurrenceService) withUpcoming(series *model.TaskRecurrence) (*response.Recurrence, error) {
+	result := &response.Recurrence{TaskRecurrence: *series, Upcoming: []time.Time{}}
+	if series.EndedAt != nil {
+		return result, nil
+	}
+
+	upcoming, err := utils.Occurrences(series.RRule, series.DTStart, series.ExDates,
+		series.LastOccurrence, upcomingOccurrences)
+	if err != nil {
+		return nil, err
+	}
+	result.Upcoming = upcoming
+	return result, nil
+}
+
+// taskSeries возвращает доступную пользователю задачу и её серию
+func (s *recurrenceService) taskSeries(
+	db *gorm.DB, taskID, userID uuid.UUID,
+) (*model.Task, *model.TaskRecurrence, error) {
+	task, err := findAccessibleTask(db, taskID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if task.RecurrenceID == nil {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Task is not recurring")
+	}
+
+	var series model.TaskRecurrence
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&series, "id = ?", *task.RecurrenceID).Error; err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Recurrence not found")
+	}
+	return task, &series, nil
+}
+
+func (s *recurrenceService) GetRecurrence(c *fiber.Ctx, taskID, userID uuid.UUID) (*response.Recurrence, error) {
+	_, series, err := s.taskSeries(s.DB.WithContext(c.Context()), taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withUpcoming(series)
+}
+
+// SetRecurrence делает задачу повторяющейся или меняет правило для этой и следующих задач серии
+func (s *recurrenceService) SetRecurrence(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.SetRecurrence, userID uuid.UUID,
+) (*response.Recurrence, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var series *model.TaskRecurrence
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		task, err := findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID)
+		if err != nil {
+			return err
+		}
+
+		if task.RecurrenceID == nil {
+			series, err = s.startSeries(tx, task, req)
+		} else {
+			series, err = s.editFollowing(tx, task, req)
+		}
+		return err
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to set recurrence: %+v", err)
+		return nil, err
+	}
+
+	return s.withUpcoming(series)
+}
+
+func (s *recurrenceService) startSeries(
+	tx *gorm.DB, task *model.Task, req *validation.SetRecurrence,
+) (*model.TaskRecurrence, error) {
+	dtstart := req.DTStart
+	if dtstart == nil {
+		dtstart = task.DueDate
+	}
+	if dtstart == nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Recurring task needs a due date or dtstart")
+	}
+
+	series := &model.TaskRecurrence{
+		ProjectID:      task.ProjectID,
+		SectionID:      task.SectionID,
+		UserSectionID:  task.UserSectionID,
+		RRule:          req.RRule,
+		DTStart:        *dtstart,
+		LastTaskID:     task.ID,
+		LastOccurrence: *dtstart,
+		Title:          task.Title,
+		Description:    task.Description,
+		Priority:       task.Priority,
+		AssignedTo:     task.AssignedTo,
+		EstimatedTime:  task.EstimatedTime,
+	}
+	applyRecurrenceTemplate(series, req)
+	if err := tx.Create(series).Error; err != nil {
+		return nil, err
+	}
+
+	// Сама задача становится первым вхождением серии
+	updates := recurrenceTemplateUpdates(req)
+	updates["recurrence_id"] = series.ID
+	updates["due_date"] = *dtstart
+	if err := tx.Model(task).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// editFollowing применяет изменения к этой и следующим задачам серии.
+// Если задача не первая в серии, серия разделяется на две
+func (s *recurrenceService) editFollowing(
+	tx *gorm.DB, task *model.Task, req *validation.SetRecurrence,
+) (*model.TaskRecurrence, error) {
+	var series model.TaskRecurrence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&series, "id = ?", *task.RecurrenceID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Recurrence not found")
+	}
+
+	occurrence := series.DTStart
+	if task.DueDate != nil {
+		occurrence = *task.DueDate
+	}
+
+	if occurrence.After(series.DTStart) {
+		now := time.Now()
+		if err := tx.Model(&series).Update("ended_at", now).Error; err != nil {
+			return nil, err
+		}
+
+		var exdates []time.Time
+		for _, exdate := range series.ExDates {
+			if !exdate.Before(occurrence) {
+				exdates = append(exdates, exdate)
+			}
+		}
+		series = model.TaskRecurrence{
+			ProjectID:      series.ProjectID,
+			SectionID:      series.SectionID,
+			UserSectionID:  series.UserSectionID,
+			RRule:          series.RRule,
+			DTStart:        occurrence,
+			ExDates:        exdates,
+			LastTaskID:     series.LastTaskID,
+			LastOccurrence: series.LastOccurrence,
+			Title:          series.Title,
+			Description:    series.Description,
+			Priority:       series.Priority,
+			AssignedTo:     series.AssignedTo,
+			EstimatedTime:  series.EstimatedTime,
+		}
+		if series.LastOccurrence.Before(occurrence) {
+			series.LastTaskID, series.LastOccurrence = task.ID, occurrence
+		}
+	}
+
+	series.RRule = req.RRule
+	if req.DTStart != nil {
+		series.DTStart = *req.DTStart
+	}
+	applyRecurrenceTemplate(&series, req)
+	if err := tx.Save(&series).Error; err != nil {
+		return nil, err
+	}
+
+	// Переносим эту и следующие задачи в (новую) серию
+	updates := recurrenceTemplateUpdates(req)
+	updates["recurrence_id"] = series.ID
+	if err := tx.Model(&model.Task{}).
+		Where("recurrence_id = ? AND (id = ? OR due_date >= ?)", *task.RecurrenceID, task.ID, occurrence).
+		Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func applyRecurrenceTemplate(series *model.TaskRecurrence, req *validation.SetRecurrence) {
+	if req.Title != nil {
+		series.Title = *req.Title
+	}
+	if req.Description != nil {
+		series.Description = *req.Description
+	}
+	if req.Priority != nil {
+		series.Priority = *req.Priority
+	}
+	if req.EstimatedTime != nil {
+		series.EstimatedTime = *req.EstimatedTime
+	}
+}
+
+func recurrenceTemplateUpdates(req *validation.SetRecurrence) map[string]interface{} {
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+	if req.EstimatedTime != nil {
+		updates["estimated_time"] = *req.EstimatedTime
+	}
+	return updates
+}
+
+func (s *recurrenceService) StopRecurrence(c *fiber.Ctx, taskID, userID uuid.UUID) error {
+	return s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		_, series, err := s.taskSeries(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if series.EndedAt != nil {
+			return nil
+		}
+		return tx.Model(series).Update("ended_at", time.Now()).Error
+	})
+}
+
+// AddException исключает вхождение серии в указанный день
+func (s *recurrenceService) AddException(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.AddRecurrenceException, userID uuid.UUID,
+) (*response.Recurrence, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	day, _ := time.Parse(time.DateOnly, req.Date)
+
+	var series *model.TaskRecurrence
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if _, series, err = s.taskSeries(tx, taskID, userID); err != nil {
+			return err
+		}
+
+		occurrence, found, err := utils.OccurrenceOn(series.RRule, series.DTStart, series.ExDates, day)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fiber.NewError(fiber.StatusBadRequest, "Series has no occurrence on this date")
+		}
+
+		series.ExDates = append(series.ExDates, occurrence)
+		return tx.Model(series).Update("ex_dates", series.ExDates).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to add recurrence exception: %+v", err)
+		return nil, err
+	}
+
+	return s.withUpcoming(series)
+}
+
+// AdvanceSeries создаёт следующее вхождение, если fromTaskID всё ещё последнее в серии
+func (s *recurrenceService) AdvanceSeries(tx *gorm.DB, recurrenceID, fromTaskID uuid.UUID) error {
+	var series model.TaskRecurrence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&series, "id = ?", recurrenceID).Error; err != nil {
+		return err
+	}
+	if series.EndedAt != nil || series.LastTaskID != fromTaskID {
+		return nil
+	}
+
+	// Пропущенные из-за простоя вхождения не создаём
+	after := series.LastOccurrence
+	if now := time.Now(); now.After(after) {
+		after = now
+	}
+	next, err := utils.NextOccurrence(series.RRule, series.DTStart, series.ExDates, after)
+	if err != nil {
+		return err
+	}
+	if next.IsZero() {
+		return tx.Model(&series).Update("ended_at", time.Now()).Error
+	}
+
+	status, err := s.WorkflowService.InitialStatus(tx, series.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	task := &model.Task{
+		ProjectID:     series.ProjectID,
+		SectionID:     series.SectionID,
+		UserSectionID: series.UserSectionID,
+		Title:         series.Title,
+		Description:   series.Description,
+		Priority:      series.Priority,
+		AssignedTo:    series.AssignedTo,
+		EstimatedTime: series.EstimatedTime,
+		Status:        status,
+		DueDate:       &next,
+		RecurrenceID:  &series.ID,
+	}
+	if task.Rank, err = sectionScope(task.SectionID).last(tx); err != nil {
+		return err
+	}
+	if task.UserSectionID != nil {
+		if task.UserRank, err = userSectionScope(*task.UserSectionID).last(tx); err != nil {
+			return err
+		}
+	}
+	if err := tx.Create(task).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&series).Updates(map[string]interface{}{
+		"last_task_id":    task.ID,
+		"last_occurrence": next,
+	}).Error
+}
+
+// RunScheduler периодически создаёт вхождения для серий, у которых прошёл срок последней задачи
+func (s *recurrenceService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.advanceDueSeries(ctx)
+		}
+	}
+}
+
+// advanceDueSeries продолжает серии, у последней задачи которых прошёл срок.
+// Пока последняя задача, секция или проект серии в корзине, серия стоит на паузе
+// и продолжается после восстановления. При окончательном удалении серию завершает очистка корзины
+func (s *recurrenceService) advanceDueSeries(ctx context.Context) {
+	var due []model.TaskRecurrence
+	if err := s.DB.WithContext(ctx).
+		Select("task_recurrences.id", "task_recurrences.last_task_id").
+		Joins("LEFT JOIN tasks ON tasks.id = task_recurrences.last_task_id").
+		Where("task_recurrences.ended_at IS NULL AND tasks.deleted_at IS NULL").
+		Where(`NOT EXISTS (SELECT 1 FROM sections
+			WHERE sections.id = task_recurrences.section_id AND sections.deleted_at IS NOT NULL)`).
+		Where(`NOT EXISTS (SELECT 1 FROM projects
+			WHERE projects.id = task_recurrences.project_id AND projects.deleted_at IS NOT NULL)`).
+		Where("tasks.id IS NULL OR tasks.due_date IS NULL OR tasks.due_date <= ?", time.Now()).
+		Find(&due).Error; err != nil {
+		s.Log.Errorf("Failed to find due recurrences: %+v", err)
+		return
+	}
+
+	for _, series := range due {
+		err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return s.AdvanceSeries(tx, series.ID, series.LastTaskID)
+		})
+		if err != nil {
+			s.Log.Errorf("Failed to create next occurrence of %s: %+v", series.ID, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"database/sql"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type SearchService interface {
+	Search(c *fiber.Ctx, userID uuid.UUID, params *validation.QuerySearch) ([]response.SearchResult, int64, error)
+}
+
+type searchService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewSearchService(db *gorm.DB, validate *validator.Validate) SearchService {
+	return &searchService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+	}
+}
+
+// ts_headline выделяет совпадения управляющими символами, а не тегами: текст задачи
+// экранируется уже после выделения, и только потом символы заменяются на <mark>
+const (
+	headlineStart   = "\x02"
+	headlineStop    = "\x03"
+	headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop +
+		", MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+var headlineMarks = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// Подзапросы по типам сущностей. Все возвращают одинаковый набор колонок
+var searchQueries = map[string]string{
+	"task": `
+		SELECT 'task' AS entity, tasks.id, tasks.project_id, NULL::uuid AS task_id, tasks.title,
+			ts_headline(@config::regconfig, coalesce(tasks.title, '') || ' ' || coalesce(tasks.description, ''),
+				q.query, @options) AS snippet,
+			ts_rank(tasks.search_vector, q.query) AS rank
+		FROM tasks, q
+		WHERE tasks.search_vector @@ q.query AND tasks.deleted_at IS NULL AND ` + taskAccessCondition,
+	"comment": `
+		SELECT 'comment' AS entity, comments.id, tasks.project_id, comments.task_id, tasks.title,
+			ts_headline(@config::regconfig, comments.body, q.query, @options) AS snippet,
+			ts_rank(comments.search_vector, q.query) AS rank
+		FROM comments
+		INNER JOIN tasks ON tasks.id = comments.task_id, q
+		WHERE comments.search_vector @@ q.query AND comments.deleted_at IS NULL AND tasks.deleted_at IS NULL
+			AND ` + taskAccessCondition,
+	"project": `
+		SELECT 'project' AS entity, projects.id, projects.id AS project_id, NULL::uuid AS task_id, projects.title,
+			ts_headline(@config::regconfig, projects.title, q.query, @options) AS snippet,
+			ts_rank(projects.search_vector, q.query) AS rank
+		FROM projects, q
+		WHERE projects.search_vector @@ q.query AND projects.deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM project_users pu WHERE pu.project_id = projects.id AND pu.user_id = @user)`,
+}
+
+var searchTypes = []string{"task", "comment", "project"}
+
+func (s *searchService) Search(
+	c *fiber.Ctx, userID uuid.UUID, params *validation.QuerySearch,
+) ([]response.SearchResult, int64, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, 0, err
+	}
+
+	types := searchTypes
+	if params.Types != "" {
+		types = strings.Split(params.Types, ",")
+	}
+
+	parts := make([]string, 0, len(types))
+	for i, entity := range types {
+		query, ok := searchQueries[entity]
+		if !ok {
+			return nil, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown search type %q", entity))
+		}
+		// Повтор типа в списке дублировал бы его результаты
+		if slices.Contains(types[:i], entity) {
+			continue
+		}
+		parts = append(parts, query)
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	var results []response.SearchResult
+	err := s.DB.WithContext(c.Context()).Raw(`
+		WITH q AS (SELECT websearch_to_tsquery(@config::regconfig, @query) AS query)
+		SELECT results.*, COUNT(*) OVER () AS total
+		FROM (`+strings.Join(parts, " UNION ALL ")+`) results
+		ORDER BY rank DESC, id
+		LIMIT @limit OFFSET @offset`,
+		sql.Named("config", config.SearchConfig),
+		sql.Named("query", params.Query),
+		sql.Named("options", headlineOptions),
+		sql.Named("user", userID),
+		sql.Named("limit", params.Limit),
+		sql.Named("offset", offset),
+	).Scan(&results).Error
+	if err != nil {
+		s.Log.Errorf("Failed to search: %+v", err)
+		return nil, 0, err
+	}
+
+	var total int64
+	if len(results) > 0 {
+		total = results[0].Total
+	}
+	for i := range results {
+		results[i].Snippet = headlineMarks.Replace(html.EscapeString(results[i].Snippet))
+	}
+	return results, total, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// openTasks отбирает незавершённые задачи - только они занимают место в WIP-лимите
+func openTasks(final []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(final) == 0 {
+			return db
+		}
+		return db.Where("status NOT IN ?", final)
+	}
+}
+
+// checkWIPLimit проверяет, поместится ли задача в секцию. Строгий лимит запрещает перенос,
+// обычный только возвращает предупреждение. Секция должна быть заблокирована вызывающим
+func (s *taskService) checkWIPLimit(tx *gorm.DB, section *model.Section, task *model.Task) (string, error) {
+	if section.WIPLimit == 0 || task.SectionID == section.ID {
+		return "", nil
+	}
+	final, err := s.WorkflowService.FinalStatuses(tx, section.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(final, task.Status) {
+		return "", nil
+	}
+
+	var count int64
+	if err := tx.Model(&model.Task{}).
+		Where("section_id = ?", section.ID).
+		Scopes(openTasks(final)).
+		Count(&count).Error; err != nil {
+		return "", err
+	}
+	if int(count) < section.WIPLimit {
+		return "", nil
+	}
+	if section.WIPStrict {
+		return "", fiber.NewError(fiber.StatusConflict,
+			fmt.Sprintf("Section %q has reached its WIP limit of %d", section.Title, section.WIPLimit))
+	}
+	// Без названия секции: предупреждение уходит и в заголовок ответа
+	return fmt.Sprintf("Section WIP limit exceeded: %d of %d", count+1, section.WIPLimit), nil
+}
+
+// GetBoard собирает доску проекта: секции по порядку, в каждой задачи по рангу,
+// счётчики и исполнители, а при группировке - дорожки. Всё загружается фиксированным числом запросов
+func (s *taskService) GetBoard(
+	c *fiber.Ctx, projectID uuid.UUID, params *validation.QueryBoard, userID uuid.UUID,
+) (*response.Board, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleProject(db, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	final, err := s.WorkflowService.FinalStatuses(db, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []model.Section
+	if err := db.Where("project_id = ?", projectID).Order(`"order", created_at`).Find(&sections).Error; err != nil {
+		s.Log.Errorf("Failed to get board sections: %+v", err)
+		return nil, err
+	}
+	sectionIDs := make([]uuid.UUID, len(sections))
+	for i := range sections {
+		sectionIDs[i] = sections[i].ID
+	}
+
+	var tasks []model.Task
+	if len(sectionIDs) > 0 {
+		if err := db.Where("section_id IN ?", sectionIDs).
+			Preload("Labels").
+			Order(sectionScope(uuid.Nil).order()).
+			Find(&tasks).Error; err != nil {
+			s.Log.Errorf("Failed to get board tasks: %+v", err)
+			return nil, err
+		}
+	}
+
+	var assigneeIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for i := range tasks {
+		if id := tasks[i].AssignedTo; id != nil && !seen[*id] {
+			seen[*id] = true
+			assigneeIDs = append(assigneeIDs, *id)
+		}
+	}
+	names := make(map[uuid.UUID]string, len(assigneeIDs))
+	if len(assigneeIDs) > 0 {
+		var users []model.User
+		if err := db.Select("id", "name").Where("id IN ?", assigneeIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			names[user.ID] = user.Name
+		}
+	}
+
+	columns := make([]response.BoardColumn, len(sections))
+	index := make(map[uuid.UUID]int, len(sections))
+	for i, section := range sections {
+		index[section.ID] = i
+		columns[i] = response.BoardColumn{
+			ID:        section.ID,
+			Title:     section.Title,
+			Order:     section.Order,
+			WIPLimit:  section.WIPLimit,
+			WIPStrict: section.WIPStrict,
+			Assignees: []response.BoardAssignee{},
+			Tasks:     []model.Task{},
+		}
+	}
+	// Задачи уже отсортированы по рангу, порядок внутри колонки сохраняется
+	for _, task := range tasks {
+		column := &columns[index[task.SectionID]]
+		column.Tasks = append(column.Tasks, task)
+		column.TotalTasks++
+		if !slices.Contains(final, task.Status) {
+			column.OpenTasks++
+		}
+		if task.AssignedTo == nil {
+			continue
+		}
+		i := slices.IndexFunc(column.Assignees, func(a response.BoardAssignee) bool { return a.UserID == *task.AssignedTo })
+		if i < 0 {
+			column.Assignees = append(column.Assignees, response.BoardAssignee{UserID: *task.AssignedTo, Name: names[*task.AssignedTo]})
+			i = len(column.Assignees) - 1
+		}
+		column.Assignees[i].Tasks++
+	}
+	for i := range columns {
+		columns[i].OverLimit = columns[i].WIPLimit > 0 && columns[i].OpenTasks > columns[i].WIPLimit
+	}
+
+	if final == nil {
+		final = []string{}
+	}
+	board := &response.Board{ProjectID: projectID, FinalStatuses: final, Columns: columns}
+	if params.Swimlane == "" {
+		return board, nil
+	}
+
+	lane, err := loadSwimlane(db, projectID, params)
+	if err != nil {
+		return nil, err
+	}
+	board.Swimlane = params.Swimlane
+	board.Lanes = buildLanes(lane, sections, tasks, final)
+	// Задачи отдаются в ячейках дорожек, в колонках остаются итоги
+	for i := range board.Columns {
+		board.Columns[i].Tasks = []model.Task{}
+	}
+	return board, nil
+}
+
+// SetSectionWIPLimit задаёт или снимает WIP-лимит секции. Уже превышенный лимит задачи не выталкивает
+func (s *taskService) SetSectionWIPLimit(
+	c *fiber.Ctx, sectionID uuid.UUID, req *validation.SectionWIPLimit, userID uuid.UUID,
+) (*model.Section, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	var section model.Section
+	if err := db.First(&section, "id = ?", sectionID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Section not found")
+	}
+	if _, err := findAccessibleProject(db, section.ProjectID, userID); err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Section not found")
+	}
+
+	if err := db.Model(&section).Updates(map[string]interface{}{
+		"wip_limit":  req.WIPLimit,
+		"wip_strict": req.Strict,
+	}).Error; err != nil {
+		s.Log.Errorf("Failed to update WIP limit: %+v", err)
+		return nil, err
+	}
+
+	go s.publishUpdate(context.Background(), projectUpdatesChannel, WSMessage{
+		Entity:    "section",
+		Action:    "wip_limit_updated",
+		Data:      section,
+		Timestamp: time.Now(),
+	})
+	return &section, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bulkTarget - данные операции, общие для всех задач пакета
+type bulkTarget struct {
+	userSection *model.UserSection
+	section     *model.Section
+	group       *model.UserGroup
+}
+
+// BulkUpdateTasks применяет одну операцию ко всем задачам в одной транзакции.
+// Ошибка по задаче откатывает только её изменения (savepoint), остальные сохраняются
+func (s *taskService) BulkUpdateTasks(
+	c *fiber.Ctx, req *validation.BulkTask, userID uuid.UUID,
+) (*response.BulkTasks, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	result := &response.BulkTasks{
+		Operation: req.Operation,
+		Results:   make([]response.BulkTaskResult, 0, len(req.TaskIDs)),
+		Tasks:     []model.Task{},
+	}
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		target, err := s.bulkTarget(tx, req)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[uuid.UUID]bool, len(req.TaskIDs))
+		for _, taskID := range req.TaskIDs {
+			if seen[taskID] {
+				continue
+			}
+			seen[taskID] = true
+
+			var task *model.Task
+			var warning string
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				var err error
+				if task, err = findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID); err != nil {
+					return err
+				}
+				if req.Operation == "move_section" {
+					if warning, err = s.checkWIPLimit(tx, target.section, task); err != nil {
+						return err
+					}
+				}
+				before := *task
+				if err := s.applyBulkOperation(tx, task, req, target, userID); err != nil {
+					return err
+				}
+				// Удаление и добавление группы пишут историю сами
+				if req.Operation == "delete" || req.Operation == "add_group" {
+					return nil
+				}
+				return recordHistory(tx, task.ID, userID, "bulk_"+req.Operation, diffTasks(&before, task))
+			})
+
+			item := response.BulkTaskResult{TaskID: taskID, Success: err == nil, Warning: warning}
+			if err != nil {
+				// Внутренние ошибки не отдаём клиенту
+				var fiberErr *fiber.Error
+				if errors.As(err, &fiberErr) {
+					item.Error = fiberErr.Message
+				} else {
+					s.Log.Errorf("Bulk %s failed for task %s: %+v", req.Operation, taskID, err)
+					item.Error = fiber.ErrInternalServerError.Message
+				}
+				result.Failed++
+			} else {
+				result.Succeeded++
+				if req.Operation != "delete" {
+					result.Tasks = append(result.Tasks, *task)
+				}
+			}
+			result.Results = append(result.Results, item)
+		}
+		return nil
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to apply bulk operation: %+v", err)
+		return nil, err
+	}
+
+	if req.Operation == "add_group" && result.Succeeded > 0 {
+		taskIDs := make([]uuid.UUID, 0, result.Succeeded)
+		for _, task := range result.Tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditTaskGrant,
+			EntityType: "user_group",
+			EntityID:   req.GroupID,
+			Details:    map[string]interface{}{"task_ids": taskIDs},
+		})
+	}
+
+	// Одно сообщение на весь пакет вместо сообщения на каждую задачу
+	if result.Succeeded > 0 {
+		go s.publishUpdate(context.Background(), taskUpdatesChannel, WSMessage{
+			Entity:    "task",
+			Action:    "bulk_" + req.Operation,
+			Data:      result,
+			Timestamp: time.Now(),
+		})
+	}
+	return result, nil
+}
+
+// bulkTarget загружает секцию, группу или исполнителя операции один раз на пакет
+func (s *taskService) bulkTarget(tx *gorm.DB, req *validation.BulkTask) (*bulkTarget, error) {
+	target := new(bulkTarget)
+	switch req.Operation {
+	case "reassign":
+		target.userSection = new(model.UserSection)
+		if err := tx.Where("user_id = ? AND title = ?", *req.AssignedTo, "Recently Assigned").
+			First(target.userSection).Error; err != nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "User section not found")
+		}
+	case "move_section":
+		// Блокировка секции нужна для подсчёта WIP-лимита
+		target.section = new(model.Section)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(target.section, "id = ?", *req.SectionID).Error; err != nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Section not found")
+		}
+	case "add_group":
+		target.group = new(model.UserGroup)
+		if err := tx.First(target.group, "id = ?", *req.GroupID).Error; err != nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Group not found")
+		}
+	}
+	return target, nil
+}
+
+func (s *taskService) applyBulkOperation(
+	tx *gorm.DB, task *model.Task, req *validation.BulkTask, target *bulkTarget, userID uuid.UUID,
+) error {
+	switch req.Operation {
+	case "set_status":
+		return s.changeStatus(tx, task, req.Status, req.Force)
+	case "set_priority":
+		task.Priority = req.Priority
+		return tx.Model(task).Update("priority", req.Priority).Error
+	case "reassign":
+		// Задача попадает в конец секции "Recently Assigned" нового исполнителя
+		rank, err := userSectionScope(target.userSection.ID).last(tx)
+		if err != nil {
+			return err
+		}
+		task.AssignedTo, task.UserSectionID, task.UserRank = req.AssignedTo, &target.userSection.ID, rank
+		return tx.Model(task).Updates(map[string]interface{}{
+			"assigned_to":     *req.AssignedTo,
+			"user_section_id": target.userSection.ID,
+			"user_rank":       rank,
+		}).Error
+	case "move_section":
+		if target.section.ProjectID != task.ProjectID {
+			return fiber.NewError(fiber.StatusBadRequest, "Section belongs to another project")
+		}
+		rank, err := sectionScope(target.section.ID).last(tx)
+		if err != nil {
+			return err
+		}
+		task.SectionID, task.Rank = target.section.ID, rank
+		return tx.Model(task).Updates(map[string]interface{}{
+			"section_id": target.section.ID,
+			"rank":       rank,
+		}).Error
+	case "add_group":
+		return addTaskGroup(tx, task, target.group, userID)
+	case "set_due_date":
+		task.DueDate = req.DueDate
+		return tx.Model(task).Update("due_date", req.DueDate).Error
+	case "delete":
+		return s.deleteTask(tx, task.ID, userID, "")
+	}
+	return fiber.NewError(fiber.StatusBadRequest, "Unknown operation")
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Колонки выгрузки, в том же виде их по умолчанию ждёт импорт
+var taskCSVColumns = []string{"title", "description", "status", "priority", "due_date", "assignee_email", "section"}
+
+const (
+	taskImportMaxRows = 5000
+	// Excel без BOM открывает UTF-8 как однобайтовую кодировку
+	csvBOM = "\ufeff"
+	// Символы, с которых таблицы начинают формулу
+	csvFormulaChars = "=+-@\t\r"
+)
+
+// Форматы срока, которые понимает импорт: выгрузка и то, что обычно сохраняют таблицы
+var taskCSVDateLayouts = []string{time.RFC3339, time.DateOnly, "2006-01-02 15:04", "02.01.2006"}
+
+type taskCSVRow struct {
+	Title         string
+	Description   string
+	Status        string
+	Priority      string
+	DueDate       *time.Time
+	AssigneeEmail string
+	Section       string
+}
+
+// formatCSVDate пишет срок в полночь UTC как дату без времени, как и календарь
+func formatCSVDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	due := t.UTC()
+	if due.Equal(due.Truncate(24 * time.Hour)) {
+		return due.Format(time.DateOnly)
+	}
+	return due.Format(time.RFC3339)
+}
+
+// ExportTasksCSV возвращает функцию, которая пишет задачи проекта в CSV
+// в порядке секций и задач в них. Строки читаются курсором, выгрузка не копится в памяти
+func (s *taskService) ExportTasksCSV(c *fiber.Ctx, projectID, userID uuid.UUID) (func(w *bufio.Writer), error) {
+	if _, err := findAccessibleProject(s.DB.WithContext(c.Context()), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	// Поток пишется после выхода из обработчика, контекст запроса к этому моменту уже недоступен.
+	// Запрос выполняется сразу, чтобы его ошибка вернулась клиенту статусом, а не пустым файлом
+	db := s.DB.WithContext(context.Background())
+	rows, err := db.Table("tasks").
+		Select(`tasks.title, tasks.description, tasks.status, tasks.priority, tasks.due_date,
+			COALESCE(users.email, '') AS assignee_email, COALESCE(sections.title, '') AS section`).
+		Joins("LEFT JOIN users ON users.id = tasks.assigned_to").
+		Joins("LEFT JOIN sections ON sections.id = tasks.section_id").
+		Where("tasks.project_id = ? AND tasks.deleted_at IS NULL", projectID).
+		Order(`sections."order", sections.created_at, tasks.rank COLLATE "C", tasks.created_at, tasks.id`).
+		Rows()
+	if err != nil {
+		s.Log.Errorf("Failed to export tasks: %+v", err)
+		return nil, err
+	}
+
+	return func(w *bufio.Writer) {
+		defer rows.Close()
+
+		w.WriteString(csvBOM)
+		writer := csv.NewWriter(w)
+		_ = writer.Write(taskCSVColumns)
+		for rows.Next() {
+			var row taskCSVRow
+			if err := db.ScanRows(rows, &row); err != nil {
+				s.Log.Errorf("Failed to export tasks: %+v", err)
+				return
+			}
+			_ = writer.Write([]string{
+				escapeCSVCell(row.Title), escapeCSVCell(row.Description), escapeCSVCell(row.Status),
+				escapeCSVCell(row.Priority), formatCSVDate(row.DueDate), escapeCSVCell(row.AssigneeEmail),
+				escapeCSVCell(row.Section),
+			})
+		}
+		if err := rows.Err(); err != nil {
+			s.Log.Errorf("Failed to export tasks: %+v", err)
+		}
+		writer.Flush()
+	}, nil
+}
+
+// escapeCSVCell не даёт таблице выполнить текст задачи как формулу: ячейка с управляющим
+// символом в начале получает апостроф, который таблицы не показывают. Импорт его снимает
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaChars, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaChars, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// taskImportContext - справочники проекта, по которым проверяются строки импорта
+type taskImportContext struct {
+	statuses     map[string]string
+	initial      string
+	sections     map[string]uuid.UUID
+	firstSection *uuid.UUID
+	members      map[string]uuid.UUID
+}
+
+func (s *taskService) loadImportContext(
+	c *fiber.Ctx, db *gorm.DB, projectID, userID uuid.UUID,
+) (*taskImportContext, error) {
+	ic := &taskImportContext{
+		statuses: map[string]string{},
+		sections: map[string]uuid.UUID{},
+		members:  map[string]uuid.UUID{},
+	}
+
+	workflow, err := s.WorkflowService.GetWorkflow(c, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range workflow.Statuses {
+		ic.statuses[strings.ToLower(status.Name)] = status.Name
+	}
+	if ic.initial, err = s.WorkflowService.InitialStatus(db, projectID); err != nil {
+		return nil, err
+	}
+
+	var sections []model.Section
+	if err := db.Where("project_id = ?", projectID).Order(`"order", created_at`).Find(&sections).Error; err != nil {
+		return nil, err
+	}
+	for i := range sections {
+		key := strings.ToLower(strings.TrimSpace(sections[i].Title))
+		if _, ok := ic.sections[key]; !ok {
+			ic.sections[key] = sections[i].ID
+		}
+	}
+	if len(sections) > 0 {
+		ic.firstSection = &sections[0].ID
+	}
+
+	var members []model.User
+	if err := db.Select("users.id", "users.email").
+		Joins("JOIN project_users pu ON pu.user_id = users.id").
+		Where("pu.project_id = ?", projectID).
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		ic.members[strings.ToLower(member.Email)] = member.ID
+	}
+	return ic, nil
+}
+
+// importColumns сопоставляет поля задачи с номерами колонок по заголовку
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := positions[key]; !ok {
+			positions[key] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	for _, field := range taskCSVColumns {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		i, ok := positions[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			if mapped {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Column %q not found", name))
+			}
+			continue
+		}
+		columns[field] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Title column not found")
+	}
+	return columns, nil
+}
+
+// parseImportRow проверяет строку и собирает из неё задачу. Ошибки копятся,
+// чтобы пользователь увидел все проблемы строки сразу
+func (ic *taskImportContext) parseImportRow(record []string, columns map[string]int) (*model.Task, []string) {
+	value := func(field string) string {
+		if i, ok := columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(unescapeCSVCell(strings.TrimSpace(record[i])))
+		}
+		return ""
+	}
+
+	var errs []string
+	task := &model.Task{
+		Title:       value("title"),
+		Description: value("description"),
+		Priority:    value("priority"),
+		Status:      ic.initial,
+	}
+	switch length := len([]rune(task.Title)); {
+	case length == 0:
+		errs = append(errs, "title is required")
+	case length > 50:
+		errs = append(errs, "title must be at most 50 characters")
+	}
+	if len([]rune(task.Priority)) > 50 {
+		errs = append(errs, "priority must be at most 50 characters")
+	}
+
+	if status := value("status"); status != "" {
+		name, ok := ic.statuses[strings.ToLower(status)]
+		if ok {
+			task.Status = name
+		} else {
+			errs = append(errs, fmt.Sprintf("unknown status %q", status))
+		}
+	}
+
+	if due := value("due_date"); due != "" {
+		parsed := false
+		for _, layout := range taskCSVDateLayouts {
+			if t, err := time.Parse(layout, due); err == nil {
+				t = t.UTC()
+				task.DueDate, parsed = &t, true
+				break
+			}
+		}
+		if !parsed {
+			errs = append(errs, fmt.Sprintf("invalid due date %q", due))
+		}
+	}
+
+	if email := value("assignee_email"); email != "" {
+		id, ok := ic.members[strings.ToLower(email)]
+		if ok {
+			task.AssignedTo = &id
+		} else {
+			errs = append(errs, fmt.Sprintf("assignee %q is not a project member", email))
+		}
+	}
+
+	if section := value("section"); section != "" {
+		id, ok := ic.sections[strings.ToLower(section)]
+		if ok {
+			task.SectionID = id
+		} else {
+			errs = append(errs, fmt.Sprintf("unknown section %q", section))
+		}
+	} else if ic.firstSection != nil {
+		task.SectionID = *ic.firstSection
+	} else {
+		errs = append(errs, "project has no sections")
+	}
+	return task, errs
+}
+
+// csvImportError превращает ошибку разбора в 400 с номером строки файла
+func csvImportError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("Invalid CSV on line %d: %v", parseErr.StartLine, parseErr.Err))
+	}
+	return fiber.NewError(fiber.StatusBadRequest, "Invalid CSV: "+err.Error())
+}
+
+// ImportTasksCSV проверяет строки CSV и, если это не пробный запуск и ошибок нет,
+// создаёт все задачи одной транзакцией. Строка с ошибкой отменяет весь импорт
+func (s *taskService) ImportTasksCSV(
+	c *fiber.Ctx, projectID uuid.UUID, file io.Reader, req *validation.ImportTasks, userID uuid.UUID,
+) (*response.TaskImport, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleProject(db, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "CSV file is empty")
+	}
+	if err != nil {
+		return nil, csvImportError(err)
+	}
+	header[0] = strings.TrimPrefix(header[0], csvBOM)
+	columns, err := importColumns(header, req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	ic, err := s.loadImportContext(c, db, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.TaskImport{DryRun: req.DryRun, Rows: []response.TaskImportRow{}}
+	var tasks []*model.Task
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, csvImportError(err)
+		}
+		line, _ := reader.FieldPos(0)
+		// Пустые строки в конце таблицы не считаются
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if result.Total++; result.Total > taskImportMaxRows {
+			return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge,
+				fmt.Sprintf("CSV file has more than %d rows", taskImportMaxRows))
+		}
+
+		task, errs := ic.parseImportRow(record, columns)
+		task.ProjectID = projectID
+		result.Rows = append(result.Rows, response.TaskImportRow{Row: line, Title: task.Title, Errors: errs})
+		if len(errs) > 0 {
+			result.Failed++
+			continue
+		}
+		result.Valid++
+		tasks = append(tasks, task)
+	}
+	if req.DryRun || result.Failed > 0 || len(tasks) == 0 {
+		return result, nil
+	}
+
+	// Упоминания в описаниях уведомляются после коммита, как при создании задачи
+	mentions := make([][]model.Mention, len(tasks))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// У исполнителя задача попадает в "Recently Assigned", как при создании
+		userSections := make(map[uuid.UUID]uuid.UUID)
+		// Ошибочных строк нет, поэтому задачи идут в том же порядке, что и строки отчёта
+		for i, task := range tasks {
+			if task.AssignedTo != nil {
+				sectionID, ok := userSections[*task.AssignedTo]
+				if !ok {
+					var userSection model.UserSection
+					if err := tx.Where("user_id = ? AND title = ?", *task.AssignedTo, "Recently Assigned").
+						First(&userSection).Error; err != nil {
+						return fiber.NewError(fiber.StatusNotFound, "User section not found")
+					}
+					sectionID = userSection.ID
+					userSections[*task.AssignedTo] = sectionID
+				}
+				rank, err := userSectionScope(sectionID).last(tx)
+				if err != nil {
+					return err
+				}
+				task.UserSectionID, task.UserRank = &sectionID, rank
+			}
+
+			rank, err := sectionScope(task.SectionID).last(tx)
+			if err != nil {
+				return err
+			}
+			task.Rank = rank
+			if err := tx.Create(task).Error; err != nil {
+				return err
+			}
+			if err := recordHistory(tx, task.ID, userID, historyCreated, diffTasks(&model.Task{}, task)); err != nil {
+				return err
+			}
+			if mentions[i], err = s.MentionService.SyncMentions(
+				tx, model.MentionSourceTask, task.ID, task, userID, task.Description,
+			); err != nil {
+				return err
+			}
+			result.Rows[i].TaskID = &task.ID
+		}
+		return nil
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to import tasks: %+v", err)
+		return nil, err
+	}
+	result.Created = len(tasks)
+
+	go func() {
+		for i, task := range tasks {
+			s.MentionService.Notify(mentions[i], task.Description)
+		}
+	}()
+	go s.publishUpdate(context.Background(), projectUpdatesChannel, WSMessage{
+		Entity:    "project",
+		Action:    "tasks_imported",
+		Data:      fiber.Map{"project_id": projectID, "created": result.Created},
+		Timestamp: time.Now(),
+	})
+	return result, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"database/sql"
+	"reflect"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const defaultHistoryLimit = 50
+
+// Действия в истории задачи
+const (
+	historyCreated       = "created"
+	historyUpdated       = "updated"
+	historyReassigned    = "reassigned"
+	historyStatusChanged = "status_changed"
+	historyGroupAdded    = "group_added"
+	historyCustomFields  = "custom_fields_updated"
+	historyDeleted       = "deleted"
+	historyRestored      = "restored"
+	historyLaneChanged   = "lane_changed"
+	historyLabelAdded    = "label_added"
+	historyLabelRemoved  = "label_removed"
+)
+
+type historyField struct {
+	name  string
+	value interface{}
+}
+
+// historyFields возвращает отслеживаемые поля задачи в постоянном порядке.
+// Указатели разыменовываются, время приводится к UTC, чтобы сравнение не зависело от источника значения
+func historyFields(task *model.Task) []historyField {
+	var dueDate interface{}
+	if task.DueDate != nil {
+		dueDate = task.DueDate.UTC()
+	}
+	var customFields interface{}
+	if len(task.CustomFields) > 0 {
+		customFields = map[string]interface{}(task.CustomFields)
+	}
+	return []historyField{
+		{"title", task.Title},
+		{"description", task.Description},
+		{"status", task.Status},
+		{"priority", task.Priority},
+		{"due_date", dueDate},
+		{"section_id", uuidValue(&task.SectionID)},
+		{"user_section_id", uuidValue(task.UserSectionID)},
+		{"assigned_to", uuidValue(task.AssignedTo)},
+		{"user_group", uuidValue(task.UserGroup)},
+		{"parent_task_id", uuidValue(task.ParentTaskID)},
+		{"estimated_time", task.EstimatedTime},
+		{"custom_fields", customFields},
+	}
+}
+
+func uuidValue(id *uuid.UUID) interface{} {
+	if id == nil || *id == uuid.Nil {
+		return nil
+	}
+	return *id
+}
+
+// diffTasks сравнивает отслеживаемые поля задачи до и после изменения
+func diffTasks(before, after *model.Task) []model.FieldChange {
+	old, cur := historyFields(before), historyFields(after)
+	changes := make([]model.FieldChange, 0)
+	for i := range old {
+		if !reflect.DeepEqual(old[i].value, cur[i].value) {
+			changes = append(changes, model.FieldChange{Field: old[i].name, Old: old[i].value, New: cur[i].value})
+		}
+	}
+	return changes
+}
+
+// recordHistory сохраняет изменение задачи, пустой дифф не записывается
+func recordHistory(tx *gorm.DB, taskID, userID uuid.UUID, action string, changes []model.FieldChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return tx.Create(&model.TaskHistory{
+		TaskID:  taskID,
+		UserID:  userID,
+		Action:  action,
+		Changes: changes,
+	}).Error
+}
+
+// addTaskGroup добавляет группу к задаче и записывает новый список групп в историю
+func addTaskGroup(tx *gorm.DB, task *model.Task, group *model.UserGroup, userID uuid.UUID) error {
+	var before []uuid.UUID
+	if err := tx.Table("task_user_groups").Where("task_id = ?", task.ID).
+		Pluck("user_group_id", &before).Error; err != nil {
+		return err
+	}
+	if slices.Contains(before, group.ID) {
+		return nil
+	}
+	if err := tx.Model(task).Association("UserGroups").Append(group); err != nil {
+		return err
+	}
+	return recordHistory(tx, task.ID, userID, historyGroupAdded, []model.FieldChange{{
+		Field: "user_groups",
+		Old:   before,
+		New:   append(slices.Clone(before), group.ID),
+	}})
+}
+
+// GetTaskHistory возвращает историю задачи от новых записей к старым с курсорной пагинацией
+func (s *taskService) GetTaskHistory(
+	c *fiber.Ctx, taskID uuid.UUID, params *validation.QueryTaskHistory, userID uuid.UUID,
+) ([]model.TaskHistory, string, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, "", err
+	}
+	if _, err := findAccessibleTask(s.DB.WithContext(c.Context()), taskID, userID); err != nil {
+		return nil, "", err
+	}
+
+	query := s.DB.WithContext(c.Context()).Where("task_id = ?", taskID)
+	if params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		value, err := cursorValue("time", cursor.Value)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		query = query.Where(`("timestamp" < @value OR ("timestamp" = @value AND id < @id))`,
+			sql.Named("value", value), sql.Named("id", cursor.ID))
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+
+	var history []model.TaskHistory
+	if err := query.Order(`"timestamp" DESC, id DESC`).Limit(limit + 1).Find(&history).Error; err != nil {
+		s.Log.Errorf("Failed to get task history: %+v", err)
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(history) > limit {
+		history = history[:limit]
+		last := history[limit-1]
+		encoded, err := utils.EncodeCursor(last.Timestamp, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		nextCursor = encoded
+	}
+	return history, nextCursor, nil
+}
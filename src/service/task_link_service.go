@@ -0,0 +1,203 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"database/sql"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TaskLinkService interface {
+	CreateLink(c *fiber.Ctx, taskID uuid.UUID, req *validation.CreateTaskLink, userID uuid.UUID) (*model.TaskLink, error)
+	GetLinks(c *fiber.Ctx, taskID, userID uuid.UUID) (*response.TaskLinks, error)
+	DeleteLink(c *fiber.Ctx, taskID, linkID, userID uuid.UUID) error
+	OpenBlockers(db *gorm.DB, taskIDs ...uuid.UUID) (map[uuid.UUID][]model.Task, error)
+}
+
+type taskLinkService struct {
+	Log             *logrus.Logger
+	DB              *gorm.DB
+	Validate        *validator.Validate
+	WorkflowService WorkflowService
+}
+
+func NewTaskLinkService(db *gorm.DB, validate *validator.Validate, workflowService WorkflowService) TaskLinkService {
+	return &taskLinkService{
+		Log:             utils.Log,
+		DB:              db,
+		Validate:        validate,
+		WorkflowService: workflowService,
+	}
+}
+
+func (s *taskLinkService) CreateLink(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.CreateTaskLink, userID uuid.UUID,
+) (*model.TaskLink, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.TargetTaskID == taskID {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Task cannot be linked to itself")
+	}
+
+	// blocked_by хранится как обратная связь blocks
+	link := &model.TaskLink{
+		SourceTaskID: taskID,
+		TargetTaskID: req.TargetTaskID,
+		Type:         req.Type,
+		CreatedBy:    userID,
+	}
+	if req.Type == "blocked_by" {
+		link.SourceTaskID, link.TargetTaskID = req.TargetTaskID, taskID
+		link.Type = model.TaskLinkBlocks
+	}
+
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		// Связывать можно только задачи, доступные пользователю с обеих сторон
+		for _, id := range []uuid.UUID{link.SourceTaskID, link.TargetTaskID} {
+			if _, err := findAccessibleTask(tx, id, userID); err != nil {
+				return err
+			}
+		}
+
+		// Связь уже есть в любом направлении
+		var exists int64
+		if err := tx.Model(&model.TaskLink{}).
+			Where("type = ? AND ((source_task_id = ? AND target_task_id = ?) OR (source_task_id = ? AND target_task_id = ?))",
+				link.Type, link.SourceTaskID, link.TargetTaskID, link.TargetTaskID, link.SourceTaskID).
+			Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return fiber.NewError(fiber.StatusConflict, "Tasks are already linked")
+		}
+
+		if link.Type == model.TaskLinkBlocks {
+			// Цикл появится, если цель уже (транзитивно) блокирует источник
+			var cycle int64
+			if err := tx.Raw(`
+				WITH RECURSIVE chain AS (
+					SELECT target_task_id AS id FROM task_links WHERE source_task_id = ? AND type = ?
+					UNION
+					SELECT l.target_task_id FROM task_links l
+					INNER JOIN chain ch ON l.source_task_id = ch.id
+					WHERE l.type = ?
+				)
+				SELECT COUNT(*) FROM chain WHERE id = ?
+			`, link.TargetTaskID, model.TaskLinkBlocks, model.TaskLinkBlocks, link.SourceTaskID).
+				Scan(&cycle).Error; err != nil {
+				return err
+			}
+			if cycle > 0 {
+				return fiber.NewError(fiber.StatusConflict, "Link would create a dependency cycle")
+			}
+		}
+
+		return tx.Create(link).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to create task link: %+v", err)
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s *taskLinkService) GetLinks(c *fiber.Ctx, taskID, userID uuid.UUID) (*response.TaskLinks, error) {
+	if _, err := findAccessibleTask(s.DB.WithContext(c.Context()), taskID, userID); err != nil {
+		return nil, err
+	}
+
+	var links []model.TaskLink
+	if err := s.DB.WithContext(c.Context()).
+		Preload("SourceTask").
+		Preload("TargetTask").
+		Where("source_task_id = ? OR target_task_id = ?", taskID, taskID).
+		// Связи с задачами из корзины не показываем
+		Where(`NOT EXISTS (SELECT 1 FROM tasks t
+			WHERE t.id IN (task_links.source_task_id, task_links.target_task_id) AND t.deleted_at IS NOT NULL)`).
+		// и с задачами, которых пользователь не видит
+		Where(`EXISTS (SELECT 1 FROM tasks WHERE tasks.id = CASE WHEN task_links.source_task_id = @task
+			THEN task_links.target_task_id ELSE task_links.source_task_id END AND `+taskAccessCondition+`)`,
+			sql.Named("task", taskID), sql.Named("user", userID)).
+		Order("created_at").
+		Find(&links).Error; err != nil {
+		s.Log.Errorf("Failed to get task links: %+v", err)
+		return nil, err
+	}
+
+	blockers, err := s.OpenBlockers(s.DB.WithContext(c.Context()), taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.TaskLinks{
+		Blocked: len(blockers[taskID]) > 0,
+		Links:   links,
+	}, nil
+}
+
+func (s *taskLinkService) DeleteLink(c *fiber.Ctx, taskID, linkID, userID uuid.UUID) error {
+	db := s.DB.WithContext(c.Context())
+	var link model.TaskLink
+	if err := db.Where("id = ? AND (source_task_id = ? OR target_task_id = ?)", linkID, taskID, taskID).
+		First(&link).Error; err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Link not found")
+	}
+	// Удалить связь может тот, кому доступны обе задачи
+	for _, id := range []uuid.UUID{link.SourceTaskID, link.TargetTaskID} {
+		if _, err := findAccessibleTask(db, id, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := db.Delete(&link).Error; err != nil {
+		s.Log.Errorf("Failed to delete task link: %+v", err)
+		return err
+	}
+	return nil
+}
+
+// OpenBlockers возвращает незакрытые задачи, блокирующие каждую из taskIDs
+func (s *taskLinkService) OpenBlockers(db *gorm.DB, taskIDs ...uuid.UUID) (map[uuid.UUID][]model.Task, error) {
+	blockers := make(map[uuid.UUID][]model.Task)
+	if len(taskIDs) == 0 {
+		return blockers, nil
+	}
+
+	var links []model.TaskLink
+	if err := db.Preload("SourceTask").
+		Where("target_task_id IN ? AND type = ?", taskIDs, model.TaskLinkBlocks).
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	// Финальные статусы зависят от проекта блокирующей задачи
+	finalByProject := make(map[uuid.UUID][]string)
+	for _, link := range links {
+		// Блокирующая задача в корзине больше не блокирует
+		if link.SourceTask == nil {
+			continue
+		}
+		final, ok := finalByProject[link.SourceTask.ProjectID]
+		if !ok {
+			var err error
+			if final, err = s.WorkflowService.FinalStatuses(db, link.SourceTask.ProjectID); err != nil {
+				return nil, err
+			}
+			finalByProject[link.SourceTask.ProjectID] = final
+		}
+		if !slices.Contains(final, link.SourceTask.Status) {
+			blockers[link.TargetTaskID] = append(blockers[link.TargetTaskID], *link.SourceTask)
+		}
+	}
+	return blockers, nil
+}
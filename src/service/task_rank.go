@@ -0,0 +1,128 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// rankScope - контейнер (секция проекта или секция пользователя), внутри которого упорядочены задачи
+type rankScope struct {
+	Column    string // rank или user_rank
+	Container string // section_id или user_section_id
+	ID        uuid.UUID
+}
+
+func sectionScope(sectionID uuid.UUID) rankScope {
+	return rankScope{Column: "rank", Container: "section_id", ID: sectionID}
+}
+
+func userSectionScope(userSectionID uuid.UUID) rankScope {
+	return rankScope{Column: "user_rank", Container: "user_section_id", ID: userSectionID}
+}
+
+// Ключи сравниваются побайтово, независимо от локали базы
+func (r rankScope) order() string {
+	return r.Column + ` COLLATE "C", created_at, id`
+}
+
+type rankedTask struct {
+	ID   uuid.UUID
+	Rank string
+}
+
+func (r rankScope) tasks(tx *gorm.DB, excludeID uuid.UUID) ([]rankedTask, error) {
+	var tasks []rankedTask
+	err := tx.Model(&model.Task{}).
+		Select("id", r.Column+" AS rank").
+		Where(r.Container+" = ? AND id <> ?", r.ID, excludeID).
+		Order(r.order()).
+		Scan(&tasks).Error
+	return tasks, err
+}
+
+// normalize раздаёт задачам новые ключи, если среди них есть пустые или повторяющиеся
+func (r rankScope) normalize(tx *gorm.DB, tasks []rankedTask) error {
+	valid := true
+	for i, task := range tasks {
+		if task.Rank == "" || (i > 0 && task.Rank == tasks[i-1].Rank) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return nil
+	}
+
+	for i, key := range utils.RankSequence(len(tasks)) {
+		if err := tx.Model(&model.Task{}).Where("id = ?", tasks[i].ID).
+			UpdateColumn(r.Column, key).Error; err != nil {
+			return err
+		}
+		tasks[i].Rank = key
+	}
+	return nil
+}
+
+// place возвращает ключ для задачи taskID сразу после afterID или перед beforeID.
+// Без соседей задача попадает в конец контейнера
+func (r rankScope) place(tx *gorm.DB, taskID uuid.UUID, afterID, beforeID *uuid.UUID) (string, error) {
+	tasks, err := r.tasks(tx, taskID)
+	if err != nil {
+		return "", err
+	}
+	if err := r.normalize(tx, tasks); err != nil {
+		return "", err
+	}
+
+	indexOf := func(id uuid.UUID) int {
+		for i, task := range tasks {
+			if task.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var prev, next string
+	switch {
+	case afterID != nil:
+		i := indexOf(*afterID)
+		if i < 0 {
+			return "", fiber.NewError(fiber.StatusBadRequest, "Neighbour task is not in the target section")
+		}
+		prev = tasks[i].Rank
+		if i+1 < len(tasks) {
+			next = tasks[i+1].Rank
+		}
+	case beforeID != nil:
+		i := indexOf(*beforeID)
+		if i < 0 {
+			return "", fiber.NewError(fiber.StatusBadRequest, "Neighbour task is not in the target section")
+		}
+		next = tasks[i].Rank
+		if i > 0 {
+			prev = tasks[i-1].Rank
+		}
+	case len(tasks) > 0:
+		prev = tasks[len(tasks)-1].Rank
+	}
+	return utils.RankBetween(prev, next)
+}
+
+// last возвращает ключ для новой задачи в конце контейнера
+func (r rankScope) last(tx *gorm.DB) (string, error) {
+	var key string
+	if err := tx.Model(&model.Task{}).
+		Select(r.Column).
+		Where(r.Container+" = ?", r.ID).
+		Order(r.Column + ` COLLATE "C" DESC`).
+		Limit(1).
+		Scan(&key).Error; err != nil {
+		return "", err
+	}
+	return utils.RankBetween(key, "")
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Поля, по которым доска делится на дорожки
+const (
+	swimlaneAssignee    = "assignee"
+	swimlanePriority    = "priority"
+	swimlaneUserGroup   = "user_group"
+	swimlaneLabel       = "label"
+	swimlaneCustomField = "custom_field"
+)
+
+// Известные приоритеты в порядке дорожек, остальные значения идут после них
+var priorityLanes = []string{"urgent", "high", "medium", "low"}
+
+// swimlane описывает группировку: дорожки по порядку и ключи задачи.
+// С метками задача может попасть в несколько дорожек
+type swimlane struct {
+	lanes []response.BoardLane
+	keys  func(task *model.Task) []string
+}
+
+func projectMembers(db *gorm.DB, projectID uuid.UUID) ([]model.User, error) {
+	var members []model.User
+	err := db.Select("users.id", "users.name").
+		Joins("JOIN project_users pu ON pu.user_id = users.id").
+		Where("pu.project_id = ?", projectID).
+		Order("users.name, users.id").
+		Find(&members).Error
+	return members, err
+}
+
+// swimlaneField загружает пользовательское поле, подходящее для дорожек - с одним значением из конечного набора
+func swimlaneField(db *gorm.DB, projectID, fieldID uuid.UUID) (*model.CustomField, error) {
+	var field model.CustomField
+	if err := db.First(&field, "id = ? AND project_id = ?", fieldID, projectID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Custom field not found")
+	}
+	switch field.Type {
+	case model.CustomFieldSelect, model.CustomFieldUser, model.CustomFieldCheckbox:
+		return &field, nil
+	}
+	return nil, fiber.NewError(fiber.StatusBadRequest,
+		fmt.Sprintf("Custom field of type %s can't be used for swimlanes", field.Type))
+}
+
+func uuidKey(id *uuid.UUID) []string {
+	if id == nil {
+		return []string{""}
+	}
+	return []string{id.String()}
+}
+
+// loadSwimlane собирает дорожки группировки. Дорожка без значения всегда последняя
+func loadSwimlane(db *gorm.DB, projectID uuid.UUID, params *validation.QueryBoard) (*swimlane, error) {
+	lane := &swimlane{}
+	var empty string
+	switch params.Swimlane {
+	case swimlaneAssignee:
+		members, err := projectMembers(db, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			lane.lanes = append(lane.lanes, response.BoardLane{Key: member.ID.String(), Title: member.Name})
+		}
+		lane.keys = func(task *model.Task) []string { return uuidKey(task.AssignedTo) }
+		empty = "Unassigned"
+	case swimlanePriority:
+		for _, priority := range priorityLanes {
+			lane.lanes = append(lane.lanes, response.BoardLane{Key: priority, Title: priority})
+		}
+		lane.keys = func(task *model.Task) []string { return []string{task.Priority} }
+		empty = "No priority"
+	case swimlaneUserGroup:
+		var groups []model.UserGroup
+		if err := db.Select("user_groups.id", "user_groups.team_title").
+			Joins("JOIN project_user_groups pug ON pug.user_group_id = user_groups.id").
+			Where("pug.project_id = ?", projectID).
+			Order("user_groups.team_title, user_groups.id").
+			Find(&groups).Error; err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			lane.lanes = append(lane.lanes, response.BoardLane{Key: group.ID.String(), Title: group.TeamTitle})
+		}
+		lane.keys = func(task *model.Task) []string { return uuidKey(task.UserGroup) }
+		empty = "No group"
+	case swimlaneLabel:
+		var labels []model.Label
+		if err := db.Where("project_id = ?", projectID).Order("name").Find(&labels).Error; err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			lane.lanes = append(lane.lanes, response.BoardLane{Key: label.ID.String(), Title: label.Name})
+		}
+		lane.keys = func(task *model.Task) []string {
+			if len(task.Labels) == 0 {
+				return []string{""}
+			}
+			keys := make([]string, len(task.Labels))
+			for i, label := range task.Labels {
+				keys[i] = label.ID.String()
+			}
+			return keys
+		}
+		empty = "No label"
+	case swimlaneCustomField:
+		field, err := swimlaneField(db, projectID, uuid.MustParse(params.FieldID))
+		if err != nil {
+			return nil, err
+		}
+		switch field.Type {
+		case model.CustomFieldSelect:
+			for _, option := range field.Options {
+				lane.lanes = append(lane.lanes, response.BoardLane{Key: option, Title: option})
+			}
+		case model.CustomFieldCheckbox:
+			lane.lanes = append(lane.lanes,
+				response.BoardLane{Key: "true", Title: "Yes"}, response.BoardLane{Key: "false", Title: "No"})
+		case model.CustomFieldUser:
+			members, err := projectMembers(db, projectID)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				lane.lanes = append(lane.lanes, response.BoardLane{Key: member.ID.String(), Title: member.Name})
+			}
+		}
+		key := field.ID.String()
+		lane.keys = func(task *model.Task) []string {
+			switch value := task.CustomFields[key].(type) {
+			case string:
+				return []string{value}
+			case bool:
+				return []string{strconv.FormatBool(value)}
+			}
+			return []string{""}
+		}
+		empty = "No " + field.Name
+	}
+	lane.lanes = append(lane.lanes, response.BoardLane{Key: "", Title: empty})
+	return lane, nil
+}
+
+// buildLanes раскладывает задачи по ячейкам дорожек. Значения, которых нет среди
+// известных дорожек (например, удалённый вариант select), получают свою дорожку перед пустой
+func buildLanes(lane *swimlane, sections []model.Section, tasks []model.Task, final []string) []response.BoardLane {
+	lanes := lane.lanes
+	index := make(map[string]int, len(lanes))
+	for i := range lanes {
+		index[lanes[i].Key] = i
+	}
+	sectionIndex := make(map[uuid.UUID]int, len(sections))
+	for i := range sections {
+		sectionIndex[sections[i].ID] = i
+	}
+	newCells := func() []response.BoardCell {
+		cells := make([]response.BoardCell, len(sections))
+		for i := range sections {
+			cells[i] = response.BoardCell{SectionID: sections[i].ID, Tasks: []model.Task{}}
+		}
+		return cells
+	}
+	for i := range lanes {
+		lanes[i].Cells = newCells()
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		open := !slices.Contains(final, task.Status)
+		for _, key := range lane.keys(task) {
+			li, ok := index[key]
+			if !ok {
+				// Пустая дорожка остаётся последней
+				li = len(lanes) - 1
+				lanes = slices.Insert(lanes, li, response.BoardLane{Key: key, Title: key, Cells: newCells()})
+				for k := range index {
+					if index[k] >= li {
+						index[k]++
+					}
+				}
+				index[key] = li
+			}
+			cell := &lanes[li].Cells[sectionIndex[task.SectionID]]
+			cell.Tasks = append(cell.Tasks, *task)
+			cell.TotalTasks++
+			lanes[li].TotalTasks++
+			if open {
+				cell.OpenTasks++
+				lanes[li].OpenTasks++
+			}
+		}
+	}
+	return lanes
+}
+
+// moveToLane меняет поле группировки при переносе задачи в другую дорожку
+func (s *taskService) moveToLane(tx *gorm.DB, task *model.Task, req *validation.MoveTask, userID uuid.UUID) error {
+	key := *req.LaneKey
+	before := *task
+	var id *uuid.UUID
+	if key != "" && req.Swimlane != swimlanePriority && req.Swimlane != swimlaneCustomField {
+		parsed, err := uuid.Parse(key)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid lane key")
+		}
+		id = &parsed
+	}
+
+	switch req.Swimlane {
+	case swimlaneAssignee:
+		if id != nil {
+			var count int64
+			if err := tx.Table("project_users").
+				Where("project_id = ? AND user_id = ?", task.ProjectID, *id).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "Assignee is not a project member")
+			}
+		}
+		updates := map[string]interface{}{"assigned_to": id}
+		// Как при переназначении, задача попадает в "Recently Assigned" нового исполнителя
+		if id != nil && (task.AssignedTo == nil || *task.AssignedTo != *id) {
+			var userSection model.UserSection
+			if err := tx.Where("user_id = ? AND title = ?", *id, "Recently Assigned").First(&userSection).Error; err != nil {
+				return fiber.NewError(fiber.StatusNotFound, "User section not found")
+			}
+			rank, err := userSectionScope(userSection.ID).last(tx)
+			if err != nil {
+				return err
+			}
+			task.UserSectionID, task.UserRank = &userSection.ID, rank
+			updates["user_section_id"], updates["user_rank"] = userSection.ID, rank
+		}
+		task.AssignedTo = id
+		return s.saveLane(tx, task, &before, userID, updates)
+	case swimlanePriority:
+		// Дорожки приоритетов - известные значения и те, что уже стоят у задач проекта
+		if key != "" && !slices.Contains(priorityLanes, key) {
+			var count int64
+			if err := tx.Model(&model.Task{}).
+				Where("project_id = ? AND priority = ?", task.ProjectID, key).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "Unknown priority lane")
+			}
+		}
+		task.Priority = key
+		return s.saveLane(tx, task, &before, userID, map[string]interface{}{"priority": key})
+	case swimlaneUserGroup:
+		if id != nil {
+			var count int64
+			if err := tx.Table("project_user_groups").
+				Where("project_id = ? AND user_group_id = ?", task.ProjectID, *id).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "Group is not attached to the project")
+			}
+		}
+		task.UserGroup = id
+		return s.saveLane(tx, task, &before, userID, map[string]interface{}{"user_group": id})
+	case swimlaneLabel:
+		return s.moveLabelLane(tx, task, req.FromLaneKey, id, userID)
+	case swimlaneCustomField:
+		field, err := swimlaneField(tx, task.ProjectID, *req.FieldID)
+		if err != nil {
+			return err
+		}
+		var value interface{} = key
+		if key == "" {
+			value = nil
+		} else if field.Type == model.CustomFieldCheckbox {
+			if value, err = strconv.ParseBool(key); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid lane key")
+			}
+		}
+		values, err := s.CustomFieldService.ValidateValues(tx, task.ProjectID, task.CustomFields,
+			map[string]interface{}{field.ID.String(): value})
+		if err != nil {
+			return err
+		}
+		task.CustomFields = values
+		return s.saveLane(tx, task, &before, userID, map[string]interface{}{"custom_fields": values})
+	}
+	return fiber.NewError(fiber.StatusBadRequest, "Unknown swimlane")
+}
+
+func (s *taskService) saveLane(
+	tx *gorm.DB, task, before *model.Task, userID uuid.UUID, updates map[string]interface{},
+) error {
+	if err := tx.Model(task).Updates(updates).Error; err != nil {
+		return err
+	}
+	return recordHistory(tx, task.ID, userID, historyLaneChanged, diffTasks(before, task))
+}
+
+// moveLabelLane заменяет метку дорожки, из которой задачу перенесли, на метку целевой дорожки
+func (s *taskService) moveLabelLane(tx *gorm.DB, task *model.Task, fromKey string, to *uuid.UUID, userID uuid.UUID) error {
+	var from *uuid.UUID
+	if fromKey != "" {
+		parsed, err := uuid.Parse(fromKey)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid lane key")
+		}
+		from = &parsed
+	}
+	if uuidValue(from) == uuidValue(to) {
+		return nil
+	}
+	if to != nil {
+		if err := tx.Select("id").First(&model.Label{}, "id = ? AND project_id = ?", *to, task.ProjectID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Label not found")
+		}
+		if err := tx.Exec(
+			"INSERT INTO task_labels (task_id, label_id) VALUES (?, ?) ON CONFLICT DO NOTHING", task.ID, *to,
+		).Error; err != nil {
+			return err
+		}
+	}
+	if from != nil {
+		if err := tx.Exec("DELETE FROM task_labels WHERE task_id = ? AND label_id = ?", task.ID, *from).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Model(task).Association("Labels").Find(&task.Labels); err != nil {
+		return err
+	}
+	return recordHistory(tx, task.ID, userID, historyLaneChanged, []model.FieldChange{{
+		Field: "label",
+		Old:   uuidValue(from),
+		New:   uuidValue(to),
+	}})
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/validation"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// taskETag - версия задачи для CalDAV. Любое изменение полей задачи сдвигает updated_at
+func taskETag(task *model.Task) string {
+	return fmt.Sprintf(`"%x"`, task.UpdatedAt.UnixMicro())
+}
+
+// UpdateTaskFromTodo применяет к задаче состояние из VTODO. Если версия из If-Match устарела,
+// возвращается 412 - клиент должен перечитать ресурс. Отметка о выполнении переводит задачу
+// в первый финальный статус воркфлоу, снятие отметки - в начальный
+func (s *taskService) UpdateTaskFromTodo(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.UpdateTaskTodo, userID uuid.UUID,
+) (*model.Task, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var task model.Task
+	var mentions []model.Mention
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		found, err := findAccessibleTask(tx.Clauses(clause.Locking{Strength: "UPDATE"}), taskID, userID)
+		if err != nil {
+			return err
+		}
+		task = *found
+		if req.ETag != "" && req.ETag != "*" && req.ETag != taskETag(&task) {
+			return fiber.NewError(fiber.StatusPreconditionFailed, "Task was modified")
+		}
+		before := task
+
+		updates := map[string]interface{}{}
+		if req.Title != task.Title {
+			updates["title"] = req.Title
+		}
+		if req.Description != task.Description {
+			updates["description"] = req.Description
+		}
+		if !sameTime(req.DueDate, task.DueDate) {
+			updates["due_date"] = req.DueDate
+		}
+		if len(updates) > 0 {
+			updates["updated_at"] = time.Now()
+			if err := tx.Model(&task).Updates(updates).Error; err != nil {
+				return err
+			}
+			task.Title, task.Description, task.DueDate = req.Title, req.Description, req.DueDate
+		}
+
+		final, err := s.WorkflowService.FinalStatuses(tx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		switch done := slices.Contains(final, task.Status); {
+		case req.Completed && !done:
+			if len(final) == 0 {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "Project workflow has no final status")
+			}
+			err = s.changeStatus(tx, &task, final[0], false)
+		case !req.Completed && done:
+			var initial string
+			if initial, err = s.WorkflowService.InitialStatus(tx, task.ProjectID); err == nil {
+				err = s.changeStatus(tx, &task, initial, false)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := recordHistory(tx, taskID, userID, historyUpdated, diffTasks(&before, &task)); err != nil {
+			return err
+		}
+		if _, ok := updates["description"]; ok {
+			if mentions, err = s.MentionService.SyncMentions(
+				tx, model.MentionSourceTask, taskID, &task, userID, task.Description,
+			); err != nil {
+				return err
+			}
+		}
+		// Перечитываем задачу, чтобы версия совпадала с сохранённым updated_at
+		return tx.First(&task, "id = ?", taskID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.MentionService.Notify(mentions, task.Description)
+	go s.publishUpdate(context.Background(), taskUpdatesChannel, WSMessage{
+		Entity:    "task",
+		Action:    "updated",
+		Data:      task,
+		Timestamp: time.Now(),
+	})
+	return &task, nil
+}
+
+// DeleteTaskFromTodo переносит задачу в корзину, если версия из If-Match не устарела
+func (s *taskService) DeleteTaskFromTodo(c *fiber.Ctx, taskID uuid.UUID, etag string, userID uuid.UUID) error {
+	return s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		return s.deleteTask(tx, taskID, userID, etag)
+	})
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"math"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TemplateService interface {
+	CreateProjectTemplate(
+		c *fiber.Ctx, projectID uuid.UUID, req *validation.CreateProjectTemplate, userID uuid.UUID,
+	) (*model.ProjectTemplate, error)
+	GetProjectTemplates(c *fiber.Ctx, userID uuid.UUID) ([]model.ProjectTemplate, error)
+	GetProjectTemplate(c *fiber.Ctx, templateID, userID uuid.UUID) (*model.ProjectTemplate, error)
+	DeleteProjectTemplate(c *fiber.Ctx, templateID, userID uuid.UUID) error
+	ApplyProjectTemplate(tx *gorm.DB, project *model.Project, templateID uuid.UUID, start time.Time, userID uuid.UUID) error
+	CreateTaskTemplate(
+		c *fiber.Ctx, taskID uuid.UUID, req *validation.CreateTaskTemplate, userID uuid.UUID,
+	) (*model.TaskTemplate, error)
+	GetTaskTemplates(c *fiber.Ctx, userID uuid.UUID) ([]model.TaskTemplate, error)
+	DeleteTaskTemplate(c *fiber.Ctx, templateID, userID uuid.UUID) error
+	InstantiateTaskTemplate(
+		c *fiber.Ctx, templateID uuid.UUID, req *validation.InstantiateTaskTemplate, userID uuid.UUID,
+	) (*model.Task, error)
+}
+
+type templateService struct {
+	Log             *logrus.Logger
+	DB              *gorm.DB
+	Validate        *validator.Validate
+	WorkflowService WorkflowService
+}
+
+func NewTemplateService(db *gorm.DB, validate *validator.Validate, workflowService WorkflowService) TemplateService {
+	return &templateService{
+		Log:             utils.Log,
+		DB:              db,
+		Validate:        validate,
+		WorkflowService: workflowService,
+	}
+}
+
+// visibleTemplates оставляет шаблоны, которые пользователь создал сам или которые сохранены
+// из проекта, где он состоит
+func visibleTemplates(userID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`(created_by = ? OR EXISTS (
+			SELECT 1 FROM project_users pu WHERE pu.project_id = source_project_id AND pu.user_id = ?
+		))`, userID, userID)
+	}
+}
+
+// CreateProjectTemplate сохраняет секции, задачи с подзадачами и группы проекта как шаблон.
+// Сроки задач сохраняются в днях от даты создания проекта. Задачи вне секций проекта
+// (созданные через POST /tasks лежат в секции пользователя) попадают в первую секцию
+func (s *templateService) CreateProjectTemplate(
+	c *fiber.Ctx, projectID uuid.UUID, req *validation.CreateProjectTemplate, userID uuid.UUID,
+) (*model.ProjectTemplate, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	project, err := findAccessibleProject(db, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []model.Section
+	if err := db.Where("project_id = ?", projectID).Order(`"order", created_at`).Find(&sections).Error; err != nil {
+		return nil, err
+	}
+	var tasks []model.Task
+	if err := db.Where("project_id = ?", projectID).Order(sectionScope(uuid.Nil).order()).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	groups, err := s.taskGroups(db, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &model.ProjectTemplate{
+		Title:           req.Title,
+		Description:     req.Description,
+		CreatedBy:       userID,
+		SourceProjectID: &project.ID,
+		GroupIDs:        []uuid.UUID{},
+		Sections:        make([]model.TemplateSection, 0, len(sections)),
+	}
+	if err := db.Table("project_user_groups").
+		Where("project_id = ?", projectID).
+		Pluck("user_group_id", &template.GroupIDs).Error; err != nil {
+		return nil, err
+	}
+
+	// Корневые задачи раскладываем по секциям, подзадачи - по родителям
+	projectSections := make(map[uuid.UUID]bool, len(sections))
+	for _, section := range sections {
+		projectSections[section.ID] = true
+	}
+	roots := make(map[uuid.UUID][]model.Task)
+	children := make(map[uuid.UUID][]model.Task)
+	for _, task := range tasks {
+		switch {
+		case task.ParentTaskID != nil:
+			children[*task.ParentTaskID] = append(children[*task.ParentTaskID], task)
+		case projectSections[task.SectionID]:
+			roots[task.SectionID] = append(roots[task.SectionID], task)
+		case len(sections) > 0:
+			roots[sections[0].ID] = append(roots[sections[0].ID], task)
+		default:
+			return nil, fiber.NewError(fiber.StatusUnprocessableEntity, "Project has tasks but no sections to put them in")
+		}
+	}
+	for _, section := range sections {
+		template.Sections = append(template.Sections, model.TemplateSection{
+			Title: section.Title,
+			Order: section.Order,
+			Tasks: snapshotTasks(roots[section.ID], children, groups, project.CreatedAt),
+		})
+	}
+
+	if err := db.Create(template).Error; err != nil {
+		s.Log.Errorf("Failed to create project template: %+v", err)
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *templateService) GetProjectTemplates(c *fiber.Ctx, userID uuid.UUID) ([]model.ProjectTemplate, error) {
+	var templates []model.ProjectTemplate
+	if err := s.DB.WithContext(c.Context()).
+		Scopes(visibleTemplates(userID)).
+		Order("title").
+		Find(&templates).Error; err != nil {
+		s.Log.Errorf("Failed to get project templates: %+v", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *templateService) GetProjectTemplate(c *fiber.Ctx, templateID, userID uuid.UUID) (*model.ProjectTemplate, error) {
+	var template model.ProjectTemplate
+	if err := s.DB.WithContext(c.Context()).Scopes(visibleTemplates(userID)).
+		First(&template, "id = ?", templateID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Template not found")
+	}
+	return &template, nil
+}
+
+func (s *templateService) DeleteProjectTemplate(c *fiber.Ctx, templateID, userID uuid.UUID) error {
+	result := s.DB.WithContext(c.Context()).
+		Where("id = ? AND created_by = ?", templateID, userID).
+		Delete(&model.ProjectTemplate{})
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete project template: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Template not found")
+	}
+	return nil
+}
+
+// ApplyProjectTemplate наполняет только что созданный проект по шаблону
+func (s *templateService) ApplyProjectTemplate(
+	tx *gorm.DB, project *model.Project, templateID uuid.UUID, start time.Time, userID uuid.UUID,
+) error {
+	var template model.ProjectTemplate
+	if err := tx.Scopes(visibleTemplates(userID)).First(&template, "id = ?", templateID).Error; err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Template not found")
+	}
+
+	if err := appendGroups(tx, "project_user_groups", "project_id", project.ID, template.GroupIDs, userID); err != nil {
+		return err
+	}
+
+	status, err := s.WorkflowService.InitialStatus(tx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range template.Sections {
+		section := &model.Section{
+			Title:     item.Title,
+			ProjectID: project.ID,
+			Order:     item.Order,
+		}
+		if err := tx.Create(section).Error; err != nil {
+			return err
+		}
+
+		base := model.Task{ProjectID: project.ID, SectionID: section.ID, Status: status}
+		for _, task := range item.Tasks {
+			if _, err := s.createTask(tx, task, base, nil, start, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CreateTaskTemplate сохраняет задачу вместе с поддеревом подзадач как шаблон
+func (s *templateService) CreateTaskTemplate(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.CreateTaskTemplate, userID uuid.UUID,
+) (*model.TaskTemplate, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.Context())
+	root, err := findAccessibleTask(db, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var descendants []model.Task
+	if err := db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT * FROM tasks WHERE parent_task_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.* FROM tasks t
+			INNER JOIN subtree st ON t.parent_task_id = st.id
+			WHERE t.deleted_at IS NULL
+		)
+		SELECT * FROM subtree ORDER BY `+sectionScope(uuid.Nil).order(), taskID).
+		Scan(&descendants).Error; err != nil {
+		return nil, err
+	}
+
+	taskIDs := []uuid.UUID{root.ID}
+	children := make(map[uuid.UUID][]model.Task)
+	for _, task := range descendants {
+		taskIDs = append(taskIDs, task.ID)
+		children[*task.ParentTaskID] = append(children[*task.ParentTaskID], task)
+	}
+	groups, err := s.taskGroups(db, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &model.TaskTemplate{
+		Title:           req.Title,
+		CreatedBy:       userID,
+		SourceProjectID: &root.ProjectID,
+		Task:            snapshotTasks([]model.Task{*root}, children, groups, root.CreatedAt)[0],
+	}
+	if err := db.Create(template).Error; err != nil {
+		s.Log.Errorf("Failed to create task template: %+v", err)
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *templateService) GetTaskTemplates(c *fiber.Ctx, userID uuid.UUID) ([]model.TaskTemplate, error) {
+	var templates []model.TaskTemplate
+	if err := s.DB.WithContext(c.Context()).
+		Scopes(visibleTemplates(userID)).
+		Order("title").
+		Find(&templates).Error; err != nil {
+		s.Log.Errorf("Failed to get task templates: %+v", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *templateService) DeleteTaskTemplate(c *fiber.Ctx, templateID, userID uuid.UUID) error {
+	result := s.DB.WithContext(c.Context()).
+		Where("id = ? AND created_by = ?", templateID, userID).
+		Delete(&model.TaskTemplate{})
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete task template: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Template not found")
+	}
+	return nil
+}
+
+// InstantiateTaskTemplate создаёт задачу с подзадачами из шаблона в конце секции проекта
+func (s *templateService) InstantiateTaskTemplate(
+	c *fiber.Ctx, templateID uuid.UUID, req *validation.InstantiateTaskTemplate, userID uuid.UUID,
+) (*model.Task, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if req.StartDate != nil {
+		start = *req.StartDate
+	}
+
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var template model.TaskTemplate
+		if err := tx.Scopes(visibleTemplates(userID)).First(&template, "id = ?", templateID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Template not found")
+		}
+		if _, err := findAccessibleProject(tx, req.ProjectID, userID); err != nil {
+			return err
+		}
+		var section model.Section
+		if err := tx.First(&section, "id = ? AND project_id = ?", req.SectionID, req.ProjectID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Section not found")
+		}
+
+		status, err := s.WorkflowService.InitialStatus(tx, req.ProjectID)
+		if err != nil {
+			return err
+		}
+		base := model.Task{ProjectID: req.ProjectID, SectionID: section.ID, Status: status}
+		task, err = s.createTask(tx, template.Task, base, nil, start, userID)
+		return err
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to instantiate task template: %+v", err)
+		return nil, err
+	}
+	return task, nil
+}
+
+// createTask создаёт задачу шаблона и рекурсивно её подзадачи в секции base
+func (s *templateService) createTask(
+	tx *gorm.DB, item model.TemplateTask, base model.Task, parentID *uuid.UUID, start time.Time, userID uuid.UUID,
+) (*model.Task, error) {
+	rank, err := sectionScope(base.SectionID).last(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &model.Task{
+		ProjectID:     base.ProjectID,
+		SectionID:     base.SectionID,
+		Status:        base.Status,
+		Title:         item.Title,
+		Description:   item.Description,
+		Priority:      item.Priority,
+		EstimatedTime: item.EstimatedTime,
+		ParentTaskID:  parentID,
+		Rank:          rank,
+	}
+	if item.DueOffsetDays != nil {
+		due := start.AddDate(0, 0, *item.DueOffsetDays)
+		task.DueDate = &due
+	}
+	if err := tx.Create(task).Error; err != nil {
+		return nil, err
+	}
+	if err := appendGroups(tx, "task_user_groups", "task_id", task.ID, item.GroupIDs, userID); err != nil {
+		return nil, err
+	}
+
+	for _, subtask := range item.Subtasks {
+		if _, err := s.createTask(tx, subtask, base, &task.ID, start, userID); err != nil {
+			return nil, err
+		}
+	}
+	if len(item.Subtasks) > 0 {
+		if err := rollUpTimes(tx, &task.ID); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// taskGroups возвращает группы каждой из задач
+func (s *templateService) taskGroups(db *gorm.DB, taskIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	groups := make(map[uuid.UUID][]uuid.UUID)
+	if len(taskIDs) == 0 {
+		return groups, nil
+	}
+
+	var rows []struct {
+		TaskID      uuid.UUID
+		UserGroupID uuid.UUID
+	}
+	if err := db.Table("task_user_groups").
+		Select("task_id", "user_group_id").
+		Where("task_id IN ?", taskIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		groups[row.TaskID] = append(groups[row.TaskID], row.UserGroupID)
+	}
+	return groups, nil
+}
+
+// appendGroups привязывает группы к проекту или задаче. Привязываются только группы, которыми
+// пользователь владеет или в которых состоит: удалённые с момента сохранения шаблона и чужие пропускаются
+func appendGroups(
+	tx *gorm.DB, joinTable, ownerColumn string, ownerID uuid.UUID, groupIDs []uuid.UUID, userID uuid.UUID,
+) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+	return tx.Exec(`INSERT INTO `+joinTable+` (`+ownerColumn+`, user_group_id)
+		SELECT ?, g.id FROM user_groups g
+		WHERE g.id IN ? AND (g.owner_id = ? OR EXISTS (
+			SELECT 1 FROM user_group_users ugu WHERE ugu.user_group_id = g.id AND ugu.user_id = ?
+		))
+		ON CONFLICT DO NOTHING`, ownerID, groupIDs, userID, userID).Error
+}
+
+func snapshotTasks(
+	tasks []model.Task, children map[uuid.UUID][]model.Task, groups map[uuid.UUID][]uuid.UUID, anchor time.Time,
+) []model.TemplateTask {
+	result := make([]model.TemplateTask, 0, len(tasks))
+	for _, task := range tasks {
+		item := model.TemplateTask{
+			Title:         task.Title,
+			Description:   task.Description,
+			Priority:      task.Priority,
+			EstimatedTime: task.EstimatedTime,
+			GroupIDs:      groups[task.ID],
+			Subtasks:      snapshotTasks(children[task.ID], children, groups, anchor),
+		}
+		if item.GroupIDs == nil {
+			item.GroupIDs = []uuid.UUID{}
+		}
+		if task.DueDate != nil {
+			offset := int(math.Round(task.DueDate.Sub(anchor).Hours() / 24))
+			item.DueOffsetDays = &offset
+		}
+		result = append(result, item)
+	}
+	return result
+}
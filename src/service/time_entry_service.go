@@ -0,0 +1,334 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TimeEntryService interface {
+	StartTimer(c *fiber.Ctx, taskID uuid.UUID, req *validation.StartTimer, userID uuid.UUID) (*model.TimeEntry, error)
+	StopTimer(c *fiber.Ctx, userID uuid.UUID) (*model.TimeEntry, error)
+	GetRunningTimer(c *fiber.Ctx, userID uuid.UUID) (*model.TimeEntry, error)
+	GetTaskEntries(c *fiber.Ctx, taskID, userID uuid.UUID) ([]model.TimeEntry, error)
+	CreateEntry(c *fiber.Ctx, taskID uuid.UUID, req *validation.CreateTimeEntry, userID uuid.UUID) (*model.TimeEntry, error)
+	UpdateEntry(c *fiber.Ctx, entryID uuid.UUID, req *validation.UpdateTimeEntry, userID uuid.UUID) (*model.TimeEntry, error)
+	DeleteEntry(c *fiber.Ctx, entryID, userID uuid.UUID) error
+	GetTimesheet(c *fiber.Ctx, query *validation.QueryTimesheet, user *model.User) (*response.Timesheet, error)
+}
+
+type timeEntryService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewTimeEntryService(db *gorm.DB, validate *validator.Validate) TimeEntryService {
+	return &timeEntryService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+	}
+}
+
+// Максимальный период табеля в днях
+const maxTimesheetDays = 366
+
+func (s *timeEntryService) StartTimer(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.StartTimer, userID uuid.UUID,
+) (*model.TimeEntry, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if _, err := findAccessibleTask(s.DB.WithContext(c.Context()), taskID, userID); err != nil {
+		return nil, err
+	}
+
+	entry := &model.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      req.Note,
+	}
+	// Уникальный индекс допускает только один запущенный таймер на пользователя
+	result := s.DB.WithContext(c.Context()).Create(entry)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Another timer is already running")
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to start timer: %+v", result.Error)
+		return nil, result.Error
+	}
+	return entry, nil
+}
+
+func (s *timeEntryService) StopTimer(c *fiber.Ctx, userID uuid.UUID) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&entry, "user_id = ? AND ended_at IS NULL", userID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "No running timer")
+		}
+
+		now := time.Now()
+		entry.EndedAt = &now
+		entry.Duration = int(math.Round(now.Sub(entry.StartedAt).Minutes()))
+		if err := tx.Model(&entry).Updates(map[string]interface{}{
+			"ended_at": now,
+			"duration": entry.Duration,
+		}).Error; err != nil {
+			return err
+		}
+		return rollUpTimes(tx, &entry.TaskID)
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to stop timer: %+v", err)
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *timeEntryService) GetRunningTimer(c *fiber.Ctx, userID uuid.UUID) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	if err := s.DB.WithContext(c.Context()).
+		Preload("Task").
+		First(&entry, "user_id = ? AND ended_at IS NULL", userID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "No running timer")
+	}
+	return &entry, nil
+}
+
+func (s *timeEntryService) GetTaskEntries(c *fiber.Ctx, taskID, userID uuid.UUID) ([]model.TimeEntry, error) {
+	if _, err := findAccessibleTask(s.DB.WithContext(c.Context()), taskID, userID); err != nil {
+		return nil, err
+	}
+
+	var entries []model.TimeEntry
+	if err := s.DB.WithContext(c.Context()).
+		Where("task_id = ?", taskID).
+		Order("started_at DESC").
+		Find(&entries).Error; err != nil {
+		s.Log.Errorf("Failed to get time entries: %+v", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *timeEntryService) CreateEntry(
+	c *fiber.Ctx, taskID uuid.UUID, req *validation.CreateTimeEntry, userID uuid.UUID,
+) (*model.TimeEntry, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	endedAt := req.StartedAt.Add(time.Duration(req.Duration) * time.Minute)
+	if endedAt.After(time.Now()) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Time entry cannot end in the future")
+	}
+
+	entry := &model.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: req.StartedAt,
+		EndedAt:   &endedAt,
+		Duration:  req.Duration,
+		Note:      req.Note,
+		Manual:    true,
+	}
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		if _, err := findAccessibleTask(tx, taskID, userID); err != nil {
+			return err
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return rollUpTimes(tx, &taskID)
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to create time entry: %+v", err)
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ownEntry блокирует запись времени пользователя для изменения
+func (s *timeEntryService) ownEntry(tx *gorm.DB, entryID, userID uuid.UUID) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&entry, "id = ? AND user_id = ?", entryID, userID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Time entry not found")
+	}
+	return &entry, nil
+}
+
+func (s *timeEntryService) UpdateEntry(
+	c *fiber.Ctx, entryID uuid.UUID, req *validation.UpdateTimeEntry, userID uuid.UUID,
+) (*model.TimeEntry, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var entry *model.TimeEntry
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if entry, err = s.ownEntry(tx, entryID, userID); err != nil {
+			return err
+		}
+		if entry.EndedAt == nil {
+			return fiber.NewError(fiber.StatusConflict, "Stop the timer before editing the entry")
+		}
+
+		if req.StartedAt != nil {
+			entry.StartedAt = *req.StartedAt
+		}
+		if req.Duration != nil {
+			entry.Duration = *req.Duration
+		}
+		endedAt := entry.StartedAt.Add(time.Duration(entry.Duration) * time.Minute)
+		if endedAt.After(time.Now()) {
+			return fiber.NewError(fiber.StatusBadRequest, "Time entry cannot end in the future")
+		}
+		now := time.Now()
+		entry.EndedAt = &endedAt
+		entry.EditNote = req.EditNote
+		entry.EditedAt = &now
+
+		if err := tx.Model(entry).Updates(map[string]interface{}{
+			"started_at": entry.StartedAt,
+			"ended_at":   endedAt,
+			"duration":   entry.Duration,
+			"edit_note":  entry.EditNote,
+			"edited_at":  now,
+		}).Error; err != nil {
+			return err
+		}
+		return rollUpTimes(tx, &entry.TaskID)
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to update time entry: %+v", err)
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeEntryService) DeleteEntry(c *fiber.Ctx, entryID, userID uuid.UUID) error {
+	return s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		entry, err := s.ownEntry(tx, entryID, userID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(entry).Error; err != nil {
+			return err
+		}
+		return rollUpTimes(tx, &entry.TaskID)
+	})
+}
+
+// GetTimesheet сравнивает залогированное время с нормой WorkTime за каждый рабочий день периода
+func (s *timeEntryService) GetTimesheet(
+	c *fiber.Ctx, query *validation.QueryTimesheet, user *model.User,
+) (*response.Timesheet, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	from, _ := time.Parse(time.DateOnly, query.From)
+	to, _ := time.Parse(time.DateOnly, query.To)
+	if to.Before(from) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Parameter to must not be before from")
+	}
+	if to.Sub(from) >= maxTimesheetDays*24*time.Hour {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Timesheet period is too long")
+	}
+
+	target := user
+	if query.User != "" && query.User != user.ID.String() {
+		if !slices.Contains(config.RoleRights[user.Role], "getUsers") {
+			return nil, fiber.NewError(fiber.StatusForbidden, "You don't have permission to access this resource")
+		}
+		target = new(model.User)
+		if err := s.DB.WithContext(c.Context()).First(target, "id = ?", query.User).Error; err != nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+		}
+	}
+
+	var entries []model.TimeEntry
+	if err := s.DB.WithContext(c.Context()).
+		Preload("Task", func(db *gorm.DB) *gorm.DB {
+			// Время по задачам из корзины остаётся в табеле
+			return db.Unscoped().Select("id", "title", "project_id")
+		}).
+		Where("user_id = ? AND ended_at IS NOT NULL", target.ID).
+		Where("started_at >= ? AND started_at < ?", from, to.AddDate(0, 0, 1)).
+		Order("started_at").
+		Find(&entries).Error; err != nil {
+		s.Log.Errorf("Failed to get timesheet entries: %+v", err)
+		return nil, err
+	}
+
+	loggedByDay := make(map[string]int)
+	loggedByTask := make(map[uuid.UUID]*response.TimesheetTask)
+	var tasks []*response.TimesheetTask
+	for _, entry := range entries {
+		loggedByDay[entry.StartedAt.UTC().Format(time.DateOnly)] += entry.Duration
+
+		task, ok := loggedByTask[entry.TaskID]
+		if !ok {
+			task = &response.TimesheetTask{TaskID: entry.TaskID}
+			if entry.Task != nil {
+				task.Title = entry.Task.Title
+			}
+			loggedByTask[entry.TaskID] = task
+			tasks = append(tasks, task)
+		}
+		task.Logged += entry.Duration
+	}
+
+	timesheet := &response.Timesheet{
+		UserID:   target.ID,
+		From:     query.From,
+		To:       query.To,
+		WorkTime: target.WorkTime,
+		Days:     []response.TimesheetDay{},
+		Tasks:    make([]response.TimesheetTask, 0, len(tasks)),
+		Entries:  entries,
+	}
+	var logged, expected int
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		row := response.TimesheetDay{
+			Date:   day.Format(time.DateOnly),
+			Logged: loggedByDay[day.Format(time.DateOnly)],
+		}
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			row.Expected = target.WorkTime * 60
+		}
+		logged += row.Logged
+		expected += row.Expected
+		timesheet.Days = append(timesheet.Days, row)
+	}
+	for _, task := range tasks {
+		timesheet.Tasks = append(timesheet.Tasks, *task)
+	}
+
+	timesheet.LoggedHours = minutesToHours(logged)
+	timesheet.ExpectedHours = minutesToHours(expected)
+	timesheet.DifferenceHours = minutesToHours(logged - expected)
+	return timesheet, nil
+}
+
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
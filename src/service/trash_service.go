@@ -0,0 +1,382 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/storage"
+	"app/src/utils"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TrashService interface {
+	GetProjectTrash(c *fiber.Ctx, projectID, userID uuid.UUID) ([]response.TrashItem, error)
+	GetDeletedProjects(c *fiber.Ctx, userID uuid.UUID) ([]response.TrashItem, error)
+	RestoreTask(c *fiber.Ctx, taskID, userID uuid.UUID) (*model.Task, error)
+	RestoreSection(c *fiber.Ctx, sectionID, userID uuid.UUID) (*model.Section, error)
+	RestoreProject(c *fiber.Ctx, projectID, userID uuid.UUID) (*model.Project, error)
+	Purge(ctx context.Context) error
+	RunPurge(ctx context.Context, interval time.Duration)
+}
+
+type trashService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+	Redis    *redis.Client
+	Storage  storage.Storage
+}
+
+func NewTrashService(
+	db *gorm.DB, validate *validator.Validate, redisClient *redis.Client, fileStorage storage.Storage,
+) TrashService {
+	return &trashService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+		Redis:    redisClient,
+		Storage:  fileStorage,
+	}
+}
+
+func (s *trashService) publish(channel, entity, action string, data interface{}) {
+	err := publishMessage(context.Background(), s.Redis, channel, WSMessage{
+		Entity:    entity,
+		Action:    action,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to publish %s %s: %v", entity, action, err)
+	}
+}
+
+// retention - срок хранения удалённых сущностей до окончательного удаления
+func retention() time.Duration {
+	return time.Duration(config.TrashRetentionDays) * 24 * time.Hour
+}
+
+// taskTree выбирает задачи по условию where вместе с поддеревьями подзадач.
+// Рекурсия идёт только по задачам в состоянии state, поэтому удалённые раньше ветки не затрагиваются
+func taskTree(where, state string) string {
+	return `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE ` + where + ` AND ` + state + `
+			UNION
+			SELECT t.id FROM tasks t
+			INNER JOIN subtree st ON t.parent_task_id = st.id
+			WHERE t.` + state + `
+		)`
+}
+
+// trashTasks переносит в корзину задачи по условию where (параметр @root) вместе с подзадачами.
+// Все задачи одной операции получают одинаковый deleted_at - по нему они восстанавливаются вместе
+func trashTasks(tx *gorm.DB, where string, root interface{}, at time.Time, userID uuid.UUID) error {
+	return tx.Exec(taskTree(where, "deleted_at IS NULL")+`
+		UPDATE tasks SET deleted_at = @at, deleted_by = @user WHERE id IN (SELECT id FROM subtree)
+	`, sql.Named("root", root), sql.Named("at", at), sql.Named("user", userID)).Error
+}
+
+// restoreTasks возвращает из корзины задачи, удалённые одной операцией в момент at
+func restoreTasks(tx *gorm.DB, where string, root interface{}, at time.Time) error {
+	return tx.Exec(taskTree(where, "deleted_at = @at")+`
+		UPDATE tasks SET deleted_at = NULL, deleted_by = NULL WHERE id IN (SELECT id FROM subtree)
+	`, sql.Named("root", root), sql.Named("at", at)).Error
+}
+
+// Корнями корзины считаются сущности, удалённые сами, а не вместе с родителем:
+// у родителя, секции или проекта другой deleted_at
+const trashItemsQuery = `
+	SELECT 'section' AS type, s.id, s.title, s.deleted_at, s.deleted_by
+	FROM sections s
+	WHERE s.project_id = @project AND s.deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM projects p WHERE p.id = s.project_id AND p.deleted_at = s.deleted_at)
+	UNION ALL
+	SELECT 'task' AS type, t.id, t.title, t.deleted_at, t.deleted_by
+	FROM tasks t
+	WHERE t.project_id = @project AND t.deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = t.parent_task_id AND p.deleted_at = t.deleted_at)
+		AND NOT EXISTS (SELECT 1 FROM sections s WHERE s.id = t.section_id AND s.deleted_at = t.deleted_at)
+		AND NOT EXISTS (SELECT 1 FROM projects p WHERE p.id = t.project_id AND p.deleted_at = t.deleted_at)
+	ORDER BY deleted_at DESC, id`
+
+// withPurgeAt проставляет дату окончательного удаления
+func withPurgeAt(items []response.TrashItem) []response.TrashItem {
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(retention())
+	}
+	return items
+}
+
+// GetProjectTrash возвращает удалённые задачи и секции проекта. Корзина доступна и у удалённого проекта
+func (s *trashService) GetProjectTrash(c *fiber.Ctx, projectID, userID uuid.UUID) ([]response.TrashItem, error) {
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleProject(db.Unscoped(), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	items := make([]response.TrashItem, 0)
+	if err := db.Raw(trashItemsQuery, sql.Named("project", projectID)).Scan(&items).Error; err != nil {
+		s.Log.Errorf("Failed to get project trash: %+v", err)
+		return nil, err
+	}
+	return withPurgeAt(items), nil
+}
+
+// GetDeletedProjects возвращает удалённые проекты, в которых участвует пользователь
+func (s *trashService) GetDeletedProjects(c *fiber.Ctx, userID uuid.UUID) ([]response.TrashItem, error) {
+	items := make([]response.TrashItem, 0)
+	if err := s.DB.WithContext(c.Context()).Raw(`
+		SELECT 'project' AS type, projects.id, projects.title, projects.deleted_at, projects.deleted_by
+		FROM projects
+		WHERE projects.deleted_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM project_users pu WHERE pu.project_id = projects.id AND pu.user_id = ?)
+		ORDER BY projects.deleted_at DESC, projects.id
+	`, userID).Scan(&items).Error; err != nil {
+		s.Log.Errorf("Failed to get deleted projects: %+v", err)
+		return nil, err
+	}
+	return withPurgeAt(items), nil
+}
+
+// RestoreTask возвращает задачу из корзины вместе с подзадачами, удалёнными вместе с ней
+func (s *trashService) RestoreTask(c *fiber.Ctx, taskID, userID uuid.UUID) (*model.Task, error) {
+	var task *model.Task
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if task, err = findAccessibleTask(tx.Unscoped(), taskID, userID); err != nil {
+			return err
+		}
+		if !task.DeletedAt.Valid {
+			return fiber.NewError(fiber.StatusConflict, "Task is not deleted")
+		}
+
+		// Восстановить задачу можно только в живые проект, секцию и родителя
+		if err := s.checkProjectRestored(tx, task.ProjectID); err != nil {
+			return err
+		}
+		var section model.Section
+		if err := tx.Unscoped().Select("id", "deleted_at").First(&section, "id = ?", task.SectionID).Error; err != nil {
+			return err
+		}
+		if section.DeletedAt.Valid {
+			return fiber.NewError(fiber.StatusConflict, "Restore the section first")
+		}
+		if task.ParentTaskID != nil {
+			var parent model.Task
+			if err := tx.Unscoped().Select("id", "deleted_at").First(&parent, "id = ?", *task.ParentTaskID).Error; err != nil {
+				return err
+			}
+			if parent.DeletedAt.Valid {
+				return fiber.NewError(fiber.StatusConflict, "Restore the parent task first")
+			}
+		}
+
+		if err := restoreTasks(tx, "id = @root", task.ID, task.DeletedAt.Time); err != nil {
+			return err
+		}
+		if err := recordHistory(tx, task.ID, userID, historyRestored, diffTasks(&model.Task{}, task)); err != nil {
+			return err
+		}
+		if err := rollUpTimes(tx, task.ParentTaskID); err != nil {
+			return err
+		}
+		return tx.First(task, "id = ?", task.ID).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to restore task: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(taskUpdatesChannel, "task", "restored", task)
+	return task, nil
+}
+
+// RestoreSection возвращает из корзины секцию вместе с задачами, удалёнными вместе с ней
+func (s *trashService) RestoreSection(c *fiber.Ctx, sectionID, userID uuid.UUID) (*model.Section, error) {
+	var section model.Section
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().First(&section, "id = ?", sectionID).Error; err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Section not found")
+		}
+		if _, err := findAccessibleProject(tx.Unscoped(), section.ProjectID, userID); err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "Section not found")
+		}
+		if !section.DeletedAt.Valid {
+			return fiber.NewError(fiber.StatusConflict, "Section is not deleted")
+		}
+		if err := s.checkProjectRestored(tx, section.ProjectID); err != nil {
+			return err
+		}
+
+		at := section.DeletedAt.Time
+		if err := restoreTasks(tx, "section_id = @root", section.ID, at); err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&section).
+			Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to restore section: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(projectUpdatesChannel, "section", "restored", section)
+	return &section, nil
+}
+
+// RestoreProject возвращает из корзины проект вместе с секциями и задачами, удалёнными вместе с ним.
+// Как и удалить проект, восстановить его могут только те, кто им управляет
+func (s *trashService) RestoreProject(c *fiber.Ctx, projectID, userID uuid.UUID) (*model.Project, error) {
+	var project *model.Project
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if project, err = findManageableProject(tx.Unscoped(), projectID, userID); err != nil {
+			return err
+		}
+		if !project.DeletedAt.Valid {
+			return fiber.NewError(fiber.StatusConflict, "Project is not deleted")
+		}
+
+		at := project.DeletedAt.Time
+		if err := restoreTasks(tx, "project_id = @root", project.ID, at); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&model.Section{}).
+			Where("project_id = ? AND deleted_at = ?", project.ID, at).
+			Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(project).
+			Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to restore project: %+v", err)
+		return nil, err
+	}
+
+	go s.publish(projectUpdatesChannel, "project", "restored", project)
+	return project, nil
+}
+
+func (s *trashService) checkProjectRestored(tx *gorm.DB, projectID uuid.UUID) error {
+	var project model.Project
+	if err := tx.Unscoped().Select("id", "deleted_at").First(&project, "id = ?", projectID).Error; err != nil {
+		return err
+	}
+	if project.DeletedAt.Valid {
+		return fiber.NewError(fiber.StatusConflict, "Restore the project first")
+	}
+	return nil
+}
+
+// Purge окончательно удаляет сущности, пролежавшие в корзине дольше срока хранения.
+// Связанные записи без каскада в базе удаляются явно, файлы вложений - после коммита
+func (s *trashService) Purge(ctx context.Context) error {
+	cutoff := time.Now().Add(-retention())
+	var storageKeys []string
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taskIDs []uuid.UUID
+		if err := tx.Unscoped().Model(&model.Task{}).
+			Where("deleted_at < ?", cutoff).
+			Pluck("id", &taskIDs).Error; err != nil {
+			return err
+		}
+		if len(taskIDs) > 0 {
+			if err := tx.Model(&model.Attachment{}).
+				Where("task_id IN ?", taskIDs).
+				Pluck("storage_key", &storageKeys).Error; err != nil {
+				return err
+			}
+			var thumbnailKeys []string
+			if err := tx.Model(&model.AttachmentThumbnail{}).
+				Where("attachment_id IN (SELECT id FROM attachments WHERE task_id IN ?)", taskIDs).
+				Pluck("storage_key", &thumbnailKeys).Error; err != nil {
+				return err
+			}
+			storageKeys = append(storageKeys, thumbnailKeys...)
+			// История задачи остаётся и после окончательного удаления
+			for _, table := range []string{"task_users", "task_user_groups", "comments", "attachments"} {
+				if err := tx.Exec("DELETE FROM "+table+" WHERE task_id IN ?", taskIDs).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Unscoped().Delete(&model.Task{}, "id IN ?", taskIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		var sectionIDs []uuid.UUID
+		if err := tx.Unscoped().Model(&model.Section{}).
+			Where("deleted_at < ?", cutoff).
+			Pluck("id", &sectionIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&model.Section{}, "deleted_at < ?", cutoff).Error; err != nil {
+			return err
+		}
+
+		var projectIDs []uuid.UUID
+		if err := tx.Unscoped().Model(&model.Project{}).
+			Where("deleted_at < ?", cutoff).
+			Pluck("id", &projectIDs).Error; err != nil {
+			return err
+		}
+
+		// Серии, которым больше некуда добавлять вхождения, завершаются
+		if len(taskIDs)+len(sectionIDs)+len(projectIDs) > 0 {
+			if err := tx.Model(&model.TaskRecurrence{}).
+				Where("ended_at IS NULL").
+				Where("last_task_id IN ? OR section_id IN ? OR project_id IN ?", taskIDs, sectionIDs, projectIDs).
+				Update("ended_at", time.Now()).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(projectIDs) == 0 {
+			return nil
+		}
+		for _, table := range []string{"project_users", "project_user_groups", "project_permissions", "user_project_roles"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE project_id IN ?", projectIDs).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&model.Project{}, "id IN ?", projectIDs).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range storageKeys {
+		if err := s.Storage.Delete(ctx, key); err != nil {
+			s.Log.Errorf("Failed to remove attachment file %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// RunPurge периодически очищает корзину, пока не отменён ctx
+func (s *trashService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Purge(ctx); err != nil {
+				s.Log.Errorf("Failed to purge trash: %+v", err)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -23,16 +24,18 @@ type UserService interface {
 }
 
 type userService struct {
-	Log      *logrus.Logger
-	DB       *gorm.DB
-	Validate *validator.Validate
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
 }
 
-func NewUserService(db *gorm.DB, validate *validator.Validate) UserService {
+func NewUserService(db *gorm.DB, validate *validator.Validate, auditService AuditService) UserService {
 	return &userService{
-		Log:      utils.Log,
-		DB:       db,
-		Validate: validate,
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
 	}
 }
 
@@ -125,9 +128,16 @@ func (s *userService) CreateUser(c *fiber.Ctx, req *validation.CreateUser) (*mod
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed to create user: %+v", result.Error)
+		return user, result.Error
 	}
 
-	return user, result.Error
+	s.AuditService.Record(c, &model.AuditLog{
+		ActionType: model.AuditUserCreated,
+		EntityType: "user",
+		EntityID:   &user.ID,
+		Details:    map[string]interface{}{"email": user.Email, "role": user.Role},
+	})
+	return user, nil
 }
 
 func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id string) (*model.User, error) {
@@ -135,10 +145,15 @@ func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id st
 		return nil, err
 	}
 
-	if req.Email == "" && req.Name == "" && req.Password == "" {
+	if req.Email == "" && req.Name == "" && req.Password == "" && req.Role == "" {
 		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid Request")
 	}
 
+	before, err := s.GetUserByID(c, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.Password != "" {
 		hashedPassword, err := utils.HashPassword(req.Password)
 		if err != nil {
@@ -151,6 +166,7 @@ func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id st
 		Name:     req.Name,
 		Password: req.Password,
 		Email:    req.Email,
+		Role:     req.Role,
 	}
 
 	result := s.DB.WithContext(c.Context()).Where("id = ?", id).Updates(updateBody)
@@ -165,6 +181,7 @@ func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id st
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed to update user: %+v", result.Error)
+		return nil, result.Error
 	}
 
 	user, err := s.GetUserByID(c, id)
@@ -172,7 +189,35 @@ func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id st
 		return nil, err
 	}
 
-	return user, result.Error
+	// Смена роли пишется отдельной записью, остальные поля - без значений
+	if user.Role != before.Role {
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditRoleChanged,
+			EntityType: "user",
+			EntityID:   &user.ID,
+			Details:    map[string]interface{}{"old": before.Role, "new": user.Role},
+		})
+	}
+	changed := make([]string, 0, 3)
+	if user.Name != before.Name {
+		changed = append(changed, "name")
+	}
+	if user.Email != before.Email {
+		changed = append(changed, "email")
+	}
+	if req.Password != "" {
+		changed = append(changed, "password")
+	}
+	if len(changed) > 0 {
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditUserUpdated,
+			EntityType: "user",
+			EntityID:   &user.ID,
+			Details:    map[string]interface{}{"fields": changed},
+		})
+	}
+
+	return user, nil
 }
 
 func (s *userService) UpdatePassOrVerify(c *fiber.Ctx, req *validation.UpdatePassOrVerify, id string) error {
@@ -220,9 +265,17 @@ func (s *userService) DeleteUser(c *fiber.Ctx, id string) error {
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed to delete user: %+v", result.Error)
+		return result.Error
 	}
 
-	return result.Error
+	if userID, err := uuid.Parse(id); err == nil {
+		s.AuditService.Record(c, &model.AuditLog{
+			ActionType: model.AuditUserDeleted,
+			EntityType: "user",
+			EntityID:   &userID,
+		})
+	}
+	return nil
 }
 
 
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"fmt"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type WorkflowService interface {
+	GetWorkflow(c *fiber.Ctx, projectID, userID uuid.UUID) (*response.Workflow, error)
+	UpdateWorkflow(c *fiber.Ctx, projectID uuid.UUID, req *validation.UpdateWorkflow, userID uuid.UUID) (*response.Workflow, error)
+	InitialStatus(db *gorm.DB, projectID uuid.UUID) (string, error)
+	FinalStatuses(db *gorm.DB, projectID uuid.UUID) ([]string, error)
+	CheckTransition(db *gorm.DB, task *model.Task, toStatus string) error
+}
+
+type workflowService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewWorkflowService(db *gorm.DB, validate *validator.Validate) WorkflowService {
+	return &workflowService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+	}
+}
+
+// Воркфлоу, который используется, пока проект не настроил свой
+func defaultWorkflow(projectID uuid.UUID) *response.Workflow {
+	statuses := []model.WorkflowStatus{
+		{ProjectID: projectID, Name: model.TaskStatusTodo, Order: 1, IsInitial: true},
+		{ProjectID: projectID, Name: model.TaskStatusInProgress, Order: 2},
+		{ProjectID: projectID, Name: model.TaskStatusReview, Order: 3},
+		{ProjectID: projectID, Name: model.TaskStatusDone, Order: 4, IsFinal: true},
+	}
+	transitions := []model.WorkflowTransition{
+		{ProjectID: projectID, FromStatus: model.TaskStatusTodo, ToStatus: model.TaskStatusInProgress,
+			Guards: []string{model.GuardAssigneeRequired}},
+		{ProjectID: projectID, FromStatus: model.TaskStatusInProgress, ToStatus: model.TaskStatusTodo},
+		{ProjectID: projectID, FromStatus: model.TaskStatusInProgress, ToStatus: model.TaskStatusReview},
+		{ProjectID: projectID, FromStatus: model.TaskStatusReview, ToStatus: model.TaskStatusInProgress},
+		{ProjectID: projectID, FromStatus: model.TaskStatusReview, ToStatus: model.TaskStatusDone,
+			Guards: []string{model.GuardSubtasksDone}},
+		{ProjectID: projectID, FromStatus: model.TaskStatusDone, ToStatus: model.TaskStatusInProgress},
+	}
+	return &response.Workflow{Statuses: statuses, Transitions: transitions}
+}
+
+func (s *workflowService) loadWorkflow(db *gorm.DB, projectID uuid.UUID) (*response.Workflow, error) {
+	var statuses []model.WorkflowStatus
+	if err := db.Where("project_id = ?", projectID).Order(`"order" asc`).Find(&statuses).Error; err != nil {
+		s.Log.Errorf("Failed to load workflow statuses: %+v", err)
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return defaultWorkflow(projectID), nil
+	}
+
+	var transitions []model.WorkflowTransition
+	if err := db.Where("project_id = ?", projectID).Find(&transitions).Error; err != nil {
+		s.Log.Errorf("Failed to load workflow transitions: %+v", err)
+		return nil, err
+	}
+
+	return &response.Workflow{Statuses: statuses, Transitions: transitions}, nil
+}
+
+func (s *workflowService) GetWorkflow(c *fiber.Ctx, projectID, userID uuid.UUID) (*response.Workflow, error) {
+	db := s.DB.WithContext(c.Context())
+	if _, err := findAccessibleProject(db, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.loadWorkflow(db, projectID)
+}
+
+// UpdateWorkflow заменяет воркфлоу проекта. Менять его могут только те, кто управляет проектом
+func (s *workflowService) UpdateWorkflow(
+	c *fiber.Ctx, projectID uuid.UUID, req *validation.UpdateWorkflow, userID uuid.UUID,
+) (*response.Workflow, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := findManageableProject(s.DB.WithContext(c.Context()), projectID, userID); err != nil {
+		return nil, err
+	}
+
+	// Проверяем целостность воркфлоу
+	names := make([]string, 0, len(req.Statuses))
+	initial, final := 0, 0
+	for _, status := range req.Statuses {
+		if slices.Contains(names, status.Name) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Duplicate status %q", status.Name))
+		}
+		names = append(names, status.Name)
+		if status.IsInitial {
+			initial++
+		}
+		if status.IsFinal {
+			final++
+		}
+	}
+	if initial != 1 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Workflow must have exactly one initial status")
+	}
+	if final == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Workflow must have at least one final status")
+	}
+	for _, transition := range req.Transitions {
+		if !slices.Contains(names, transition.From) || !slices.Contains(names, transition.To) {
+			return nil, fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("Transition %q -> %q references an unknown status", transition.From, transition.To))
+		}
+		if transition.From == transition.To {
+			return nil, fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("Transition %q -> %q must change the status", transition.From, transition.To))
+		}
+	}
+
+	workflow := &response.Workflow{}
+	err := s.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		// Воркфлоу заменяется целиком
+		if err := tx.Where("project_id = ?", projectID).Delete(&model.WorkflowTransition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", projectID).Delete(&model.WorkflowStatus{}).Error; err != nil {
+			return err
+		}
+
+		for i, status := range req.Statuses {
+			workflow.Statuses = append(workflow.Statuses, model.WorkflowStatus{
+				ProjectID: projectID,
+				Name:      status.Name,
+				Order:     i + 1,
+				IsInitial: status.IsInitial,
+				IsFinal:   status.IsFinal,
+			})
+		}
+		if err := tx.Create(&workflow.Statuses).Error; err != nil {
+			return err
+		}
+
+		for _, transition := range req.Transitions {
+			workflow.Transitions = append(workflow.Transitions, model.WorkflowTransition{
+				ProjectID:  projectID,
+				FromStatus: transition.From,
+				ToStatus:   transition.To,
+				Guards:     transition.Guards,
+			})
+		}
+		if len(workflow.Transitions) > 0 {
+			if err := tx.Create(&workflow.Transitions).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to update workflow: %+v", err)
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+func (s *workflowService) InitialStatus(db *gorm.DB, projectID uuid.UUID) (string, error) {
+	workflow, err := s.loadWorkflow(db, projectID)
+	if err != nil {
+		return "", err
+	}
+	for _, status := range workflow.Statuses {
+		if status.IsInitial {
+			return status.Name, nil
+		}
+	}
+	return workflow.Statuses[0].Name, nil
+}
+
+func (s *workflowService) FinalStatuses(db *gorm.DB, projectID uuid.UUID) ([]string, error) {
+	workflow, err := s.loadWorkflow(db, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var final []string
+	for _, status := range workflow.Statuses {
+		if status.IsFinal {
+			final = append(final, status.Name)
+		}
+	}
+	return final, nil
+}
+
+// CheckTransition проверяет, что задачу можно перевести в статус toStatus
+func (s *workflowService) CheckTransition(db *gorm.DB, task *model.Task, toStatus string) error {
+	workflow, err := s.loadWorkflow(db, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	var target *model.WorkflowStatus
+	known := false
+	for i := range workflow.Statuses {
+		if workflow.Statuses[i].Name == toStatus {
+			target = &workflow.Statuses[i]
+		}
+		if workflow.Statuses[i].Name == task.Status {
+			known = true
+		}
+	}
+	if target == nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown status %q", toStatus))
+	}
+
+	// Задачи со статусом вне воркфлоу можно перевести в любой статус
+	var guards []string
+	if known {
+		allowed := false
+		for _, transition := range workflow.Transitions {
+			if transition.FromStatus == task.Status && transition.ToStatus == toStatus {
+				allowed = true
+				guards = transition.Guards
+				break
+			}
+		}
+		if !allowed {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Transition from %q to %q is not allowed", task.Status, toStatus))
+		}
+	}
+
+	// Родитель закрывается только после всех подзадач
+	if target.IsFinal && !slices.Contains(guards, model.GuardSubtasksDone) {
+		guards = append(guards, model.GuardSubtasksDone)
+	}
+
+	for _, guard := range guards {
+		if err := s.checkGuard(db, task, guard, toStatus); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *workflowService) checkGuard(db *gorm.DB, task *model.Task, guard, toStatus string) error {
+	switch guard {
+	case model.GuardAssigneeRequired:
+		if task.AssignedTo == nil {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Transition to %q requires an assignee", toStatus))
+		}
+	case model.GuardEstimateRequired:
+		if task.EstimatedTime <= 0 {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Transition to %q requires an estimate", toStatus))
+		}
+	case model.GuardDueDateRequired:
+		if task.DueDate == nil {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Transition to %q requires a due date", toStatus))
+		}
+	case model.GuardSubtasksDone:
+		final, err := s.FinalStatuses(db, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		var open int64
+		if err := db.Model(&model.Task{}).
+			Where("parent_task_id = ? AND (status IS NULL OR status NOT IN ?)", task.ID, final).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Transition to %q requires all subtasks to be done", toStatus))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"app/src/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound возвращается, когда объекта с таким ключом нет в хранилище
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage - хранилище файлов вложений. Ключ - путь объекта вида "a/b/c"
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New создаёт хранилище по драйверу из конфигурации
+func New(ctx context.Context) (Storage, error) {
+	switch config.StorageDriver {
+	case "local":
+		return NewLocalStorage(config.StorageLocalPath)
+	case "s3":
+		return NewS3Storage(ctx, S3Options{
+			Endpoint:  config.S3Endpoint,
+			Region:    config.S3Region,
+			Bucket:    config.S3Bucket,
+			AccessKey: config.S3AccessKey,
+			SecretKey: config.S3SecretKey,
+			UseSSL:    config.S3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", config.StorageDriver)
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Cursor - позиция в выборке для keyset-пагинации: значение колонки сортировки и ID записи
+type Cursor struct {
+	Value interface{} `json:"v"`
+	ID    uuid.UUID   `json:"id"`
+}
+
+func EncodeCursor(value interface{}, id uuid.UUID) (string, error) {
+	payload, err := json.Marshal(Cursor{Value: value, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(payload), nil
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	payload, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor encoding")
+	}
+
+	result := new(Cursor)
+	if err := json.Unmarshal(payload, result); err != nil {
+		return nil, errors.New("invalid cursor payload")
+	}
+	if result.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor id")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"time"
+	// База часовых поясов для TZID, в образе контейнера её может не быть
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+// Максимальная длина строки iCalendar в октетах без CRLF
+const icalLineLimit = 75
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// ICalWriter собирает документ iCalendar (RFC 5545): строки через CRLF,
+// длинные строки переносятся, не разрывая символы UTF-8
+type ICalWriter struct {
+	buf bytes.Buffer
+}
+
+// Line пишет свойство со значением как есть
+func (w *ICalWriter) Line(name, value string) {
+	line := name + ":" + value
+	for len(line) > icalLineLimit {
+		cut := icalLineLimit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n")
+		// Пробел в начале строки продолжения входит в лимит
+		line = " " + line[cut:]
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+// Text пишет текстовое свойство с экранированием спецсимволов
+func (w *ICalWriter) Text(name, value string) {
+	w.Line(name, ICalEscape(value))
+}
+
+func (w *ICalWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func ICalEscape(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+// ICalTime форматирует момент времени в UTC
+func ICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func ICalDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func ICalUnescape(text string) string {
+	return icalUnescaper.Replace(text)
+}
+
+// ICalProperty - свойство компонента, имена свойства и параметров в верхнем регистре
+type ICalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// ICalComponent - компонент вроде VCALENDAR или VTODO с вложенными компонентами
+type ICalComponent struct {
+	Name       string
+	Properties []ICalProperty
+	Components []*ICalComponent
+}
+
+// Property возвращает первое свойство с таким именем
+func (c *ICalComponent) Property(name string) *ICalProperty {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Component ищет вложенный компонент по имени на любой глубине
+func (c *ICalComponent) Component(name string) *ICalComponent {
+	for _, child := range c.Components {
+		if child.Name == name {
+			return child
+		}
+		if found := child.Component(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+var ErrInvalidICal = errors.New("invalid iCalendar data")
+
+// ParseICal разбирает документ iCalendar в дерево компонентов.
+// Значения свойств не раскодируются, текст нужно пропустить через ICalUnescape
+func ParseICal(data []byte) (*ICalComponent, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	// Строки продолжения начинаются с пробела или табуляции
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+
+	var root *ICalComponent
+	var stack []*ICalComponent
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			component := &ICalComponent{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			} else if root != nil {
+				return nil, ErrInvalidICal
+			} else {
+				root = component
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, ErrInvalidICal
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, ErrInvalidICal
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, prop)
+		}
+	}
+	if root == nil || len(stack) > 0 {
+		return nil, ErrInvalidICal
+	}
+	return root, nil
+}
+
+// parseICalLine делит строку на имя, параметры и значение. Двоеточия и точки с запятой
+// внутри кавычек в параметрах разделителями не считаются
+func parseICalLine(line string) (ICalProperty, error) {
+	prop := ICalProperty{Params: map[string]string{}}
+	quoted := false
+	start, paramName := 0, ""
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+		case ch == '=' && paramName == "" && prop.Name != "":
+			paramName = strings.ToUpper(line[start:i])
+			start = i + 1
+		case ch == ';' || ch == ':':
+			part := line[start:i]
+			if prop.Name == "" {
+				prop.Name = strings.ToUpper(part)
+			} else if paramName != "" {
+				prop.Params[paramName] = strings.Trim(part, `"`)
+				paramName = ""
+			}
+			start = i + 1
+			if ch == ':' {
+				if prop.Name == "" {
+					return prop, ErrInvalidICal
+				}
+				prop.Value = line[i+1:]
+				return prop, nil
+			}
+		}
+	}
+	return prop, ErrInvalidICal
+}
+
+// ParseICalTime читает значение даты или даты со временем. Второе значение - дата без времени,
+// она возвращается как полночь UTC. Время без зоны и без TZID считается UTC
+func ParseICalTime(prop *ICalProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+	if prop.Params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		// Неизвестная зона (например, из VTIMEZONE клиента) считается UTC
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t.UTC(), false, err
+}
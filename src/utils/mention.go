@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Упоминание - @ в начале слова и дальше имя, локальная часть email или email целиком:
+// @alice, @alice.smith, @alice@example.com. Email внутри текста (bob@example.com) упоминанием не считается
+var mentionPattern = regexp.MustCompile(
+	`(?:^|[^\p{L}\p{N}_.@])@([\p{L}\p{N}_][\p{L}\p{N}_.+-]*(?:@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+)?)`,
+)
+
+// ParseMentions возвращает уникальные упоминания из текста в нижнем регистре в порядке появления
+func ParseMentions(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// Точка или дефис в конце - это пунктуация, а не часть имени
+		handle := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "strings"
+
+// MatchMIME проверяет MIME-тип по списку разрешённых. Параметры вроде charset игнорируются,
+// запись "image/*" разрешает всю группу, "*/*" - любой тип
+func MatchMIME(mimeType string, allowed []string) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	group, subtype, ok := strings.Cut(mimeType, "/")
+	if !ok || group == "" || subtype == "" {
+		return false
+	}
+
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "*/*" || pattern == mimeType:
+			return true
+		case strings.HasSuffix(pattern, "/*") && strings.TrimSuffix(pattern, "/*") == group:
+			return true
+		}
+	}
+	return false
+}
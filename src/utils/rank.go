@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// Алфавит ключей ранга. Порядок символов совпадает с байтовым,
+// поэтому ключи сравниваются как обычные строки (COLLATE "C")
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween возвращает ключ строго между prev и next.
+// Пустой prev - начало списка, пустой next - конец
+func RankBetween(prev, next string) (string, error) {
+	if next != "" && prev >= next {
+		return "", errors.New("rank keys are out of order")
+	}
+	if strings.Trim(prev+next, rankDigits) != "" {
+		return "", errors.New("rank key contains invalid characters")
+	}
+	return rankMidpoint(strings.TrimRight(prev, "0"), next, next == ""), nil
+}
+
+// rankMidpoint работает с ключами как с дробями 0.prev и 0.next
+func rankMidpoint(prev, next string, open bool) string {
+	if !open {
+		// Общий префикс переносим в результат как есть
+		n := 0
+		for n < len(next) && rankDigitAt(prev, n) == next[n] {
+			n++
+		}
+		if n > 0 {
+			return next[:n] + rankMidpoint(rankTail(prev, n), next[n:], false)
+		}
+	}
+
+	low := 0
+	if prev != "" {
+		low = strings.IndexByte(rankDigits, prev[0])
+	}
+	high := len(rankDigits)
+	if !open {
+		high = strings.IndexByte(rankDigits, next[0])
+	}
+
+	if high-low > 1 {
+		return string(rankDigits[(low+high+1)/2])
+	}
+	if !open && len(next) > 1 {
+		return next[:1]
+	}
+	return string(rankDigits[low]) + rankMidpoint(rankTail(prev, 1), "", true)
+}
+
+func rankDigitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return rankDigits[0]
+}
+
+func rankTail(key string, n int) string {
+	if n >= len(key) {
+		return ""
+	}
+	return key[n:]
+}
+
+// RankSequence возвращает count равномерно распределённых возрастающих ключей
+func RankSequence(count int) []string {
+	base := len(rankDigits)
+	width, capacity := 1, base
+	for capacity <= count {
+		width++
+		capacity *= base
+	}
+	step := capacity / (count + 1)
+
+	keys := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		value := i * step
+		key := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			key[j] = rankDigits[value%base]
+			value /= base
+		}
+		keys = append(keys, strings.TrimRight(string(key), "0"))
+	}
+	return keys
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+func recurrenceSet(rule string, dtstart time.Time, exdates []time.Time) (*rrule.Set, error) {
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, err
+	}
+	option.Dtstart = dtstart
+
+	recurrence, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &rrule.Set{}
+	set.RRule(recurrence)
+	for _, exdate := range exdates {
+		set.ExDate(exdate)
+	}
+	return set, nil
+}
+
+// NextOccurrence возвращает первое вхождение строго после after.
+// Нулевое время означает, что серия закончилась
+func NextOccurrence(rule string, dtstart time.Time, exdates []time.Time, after time.Time) (time.Time, error) {
+	set, err := recurrenceSet(rule, dtstart, exdates)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return set.After(after, false), nil
+}
+
+// Occurrences возвращает до limit ближайших вхождений после after
+func Occurrences(rule string, dtstart time.Time, exdates []time.Time, after time.Time, limit int) ([]time.Time, error) {
+	set, err := recurrenceSet(rule, dtstart, exdates)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]time.Time, 0, limit)
+	next := set.After(after, false)
+	for !next.IsZero() && len(occurrences) < limit {
+		occurrences = append(occurrences, next)
+		next = set.After(next, false)
+	}
+	return occurrences, nil
+}
+
+// OccurrenceOn возвращает вхождение серии в указанный день, если оно есть
+func OccurrenceOn(rule string, dtstart time.Time, exdates []time.Time, day time.Time) (time.Time, bool, error) {
+	set, err := recurrenceSet(rule, dtstart, exdates)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, dtstart.Location())
+	occurrence := set.After(start, true)
+	if occurrence.IsZero() || !occurrence.Before(start.AddDate(0, 0, 1)) {
+		return time.Time{}, false, nil
+	}
+	return occurrence, true, nil
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/image/draw"
+
+	// Дополнительные форматы для image.Decode
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// Ограничение на размер исходника, чтобы маленький файл не развернулся в гигабайты памяти
+const maxImagePixels = 50_000_000
+
+var (
+	ErrImageTooLarge  = errors.New("image dimensions are too large")
+	ErrPDFUnsupported = errors.New("pdftoppm is not installed")
+)
+
+// DecodeImage декодирует изображение, заранее проверив его размеры по заголовку
+func DecodeImage(data []byte) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, "", ErrImageTooLarge
+	}
+	// Для GIF берётся только первый кадр
+	if format == "gif" {
+		img, err := gif.Decode(bytes.NewReader(data))
+		return img, format, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, format, err
+}
+
+// ResizeToFit уменьшает изображение так, чтобы большая сторона не превышала maxSide.
+// Изображения меньше этого размера не увеличиваются
+func ResizeToFit(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return src
+	}
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
+
+// EncodeThumbnail кодирует миниатюру и возвращает её MIME-тип.
+// Форматы с прозрачностью сохраняются в PNG, остальные - в JPEG
+func EncodeThumbnail(w io.Writer, img image.Image, format string) (string, error) {
+	switch format {
+	case "png", "gif", "webp":
+		return "image/png", png.Encode(w, img)
+	default:
+		return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	}
+}
+
+// RenderPDFPage рендерит первую страницу PDF через pdftoppm из poppler-utils,
+// большая сторона результата равна maxSide
+func RenderPDFPage(ctx context.Context, data []byte, maxSide int) (image.Image, error) {
+	binary, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		return nil, ErrPDFUnsupported
+	}
+
+	dir, err := os.MkdirTemp("", "pdf-preview-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.pdf")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, err
+	}
+	output := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, binary,
+		"-png", "-f", "1", "-l", "1", "-singlefile", "-scale-to", strconv.Itoa(maxSide), input, output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm: %w: %s", err, bytes.TrimSpace(out))
+	}
+
+	page, err := os.ReadFile(output + ".png")
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := DecodeImage(page)
+	return img, err
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DAVNamespace            = "DAV:"
+	CalDAVNamespace         = "urn:ietf:params:xml:ns:caldav"
+	CalendarServerNamespace = "http://calendarserver.org/ns/"
+)
+
+// Префиксы пространств имён, объявленные в корне ответа
+var davPrefixes = map[string]string{
+	DAVNamespace:            "D",
+	CalDAVNamespace:         "C",
+	CalendarServerNamespace: "CS",
+}
+
+// DAVProp - свойство ресурса, Value - готовое XML-содержимое элемента
+type DAVProp struct {
+	Name  xml.Name
+	Value string
+}
+
+// DAVText экранирует текст для содержимого свойства
+func DAVText(text string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// DAVElement собирает пустой элемент или элемент с готовым содержимым
+func DAVElement(namespace, local, value string) string {
+	var buf bytes.Buffer
+	writeDAVElement(&buf, xml.Name{Space: namespace, Local: local}, value)
+	return buf.String()
+}
+
+func writeDAVElement(buf *bytes.Buffer, name xml.Name, value string) {
+	tag := name.Local
+	attr := ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		// Чужое пространство имён объявляется прямо на элементе
+		attr = ` xmlns="` + DAVText(name.Space) + `"`
+	}
+	buf.WriteString("<" + tag + attr)
+	if value == "" {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">" + value + "</" + tag + ">")
+}
+
+// DAVMultistatus собирает ответ 207 Multi-Status (RFC 4918)
+type DAVMultistatus struct {
+	buf bytes.Buffer
+}
+
+func NewDAVMultistatus() *DAVMultistatus {
+	m := &DAVMultistatus{}
+	m.buf.WriteString(xml.Header)
+	m.buf.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="` + CalDAVNamespace + `" xmlns:CS="` + CalendarServerNamespace + `">`)
+	return m
+}
+
+// Response добавляет ресурс: найденные свойства со статусом 200, отсутствующие - с 404
+func (m *DAVMultistatus) Response(href string, found []DAVProp, missing []xml.Name) {
+	m.buf.WriteString("<D:response><D:href>" + DAVText(href) + "</D:href>")
+	if len(found) > 0 {
+		m.buf.WriteString("<D:propstat><D:prop>")
+		for _, prop := range found {
+			writeDAVElement(&m.buf, prop.Name, prop.Value)
+		}
+		m.buf.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>")
+	}
+	if len(missing) > 0 {
+		m.buf.WriteString("<D:propstat><D:prop>")
+		for _, name := range missing {
+			writeDAVElement(&m.buf, name, "")
+		}
+		m.buf.WriteString("</D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat>")
+	}
+	m.buf.WriteString("</D:response>")
+}
+
+// Status добавляет ресурс без свойств, например отсутствующий href из calendar-multiget
+func (m *DAVMultistatus) Status(href string, code int) {
+	m.buf.WriteString("<D:response><D:href>" + DAVText(href) + "</D:href><D:status>HTTP/1.1 " +
+		strconv.Itoa(code) + " " + http.StatusText(code) + "</D:status></D:response>")
+}
+
+func (m *DAVMultistatus) Bytes() []byte {
+	return append(m.buf.Bytes(), "</D:multistatus>"...)
+}
+
+// DAVRequest - тело PROPFIND или REPORT. Kind - имя корневого элемента
+// (propfind, calendar-query, calendar-multiget), Props - запрошенные свойства
+type DAVRequest struct {
+	Kind    string
+	AllProp bool
+	Props   []xml.Name
+	Hrefs   []string
+}
+
+var ErrInvalidDAVRequest = errors.New("invalid WebDAV request body")
+
+// ParseDAVRequest разбирает тело запроса. Пустое тело PROPFIND означает allprop.
+// Фильтры calendar-query не разбираются - в коллекциях есть только VTODO
+func ParseDAVRequest(body []byte) (*DAVRequest, error) {
+	req := &DAVRequest{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		req.Kind, req.AllProp = "propfind", true
+		return req, nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var path []xml.Name
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidDAVRequest
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name)
+			switch {
+			case len(path) == 1:
+				req.Kind = t.Name.Local
+			case len(path) == 2 && (t.Name.Local == "allprop" || t.Name.Local == "propname"):
+				req.AllProp = true
+			case len(path) == 3 && path[1].Local == "prop":
+				req.Props = append(req.Props, t.Name)
+			}
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		case xml.CharData:
+			if len(path) == 2 && path[1].Local == "href" {
+				req.Hrefs = append(req.Hrefs, string(bytes.TrimSpace(t)))
+			}
+		}
+	}
+	if req.Kind == "" {
+		return nil, ErrInvalidDAVRequest
+	}
+	if len(req.Props) == 0 {
+		req.AllProp = true
+	}
+	return req, nil
+}
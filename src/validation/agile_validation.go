@@ -1,9 +1,15 @@
 package validation
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type CreateProject struct {
-	Title string `json:"title" validate:"required,max=50" example:"fake name"`
+	Title      string     `json:"title" validate:"required,max=50" example:"fake name"`
+	TemplateID *uuid.UUID `json:"template_id"`                               // Создать проект по шаблону
+	StartDate  *time.Time `json:"start_date" example:"2024-10-07T09:00:00Z"` // Отсчёт сроков шаблона, по умолчанию - сейчас
 }
 
 type CreateGroup struct {
@@ -16,15 +22,70 @@ type GetProjectSection struct {
 }
 
 type CreateTask struct {
-	Title        string     `json:"title" validate:"required,max=50" example:"fake task"`
-	Description  string     `json:"description"`
-	AssignedTo   *uuid.UUID `json:"assigned_to"`
-	ProjectID    uuid.UUID  `json:"project_id" validate:"required,uuid"` // Добавил теги
-	ParentTaskID *uuid.UUID `json:"parent_task_id,omitempty"`            // Если есть parent task
+	Title         string                 `json:"title" validate:"required,max=50" example:"fake task"`
+	Description   string                 `json:"description"`
+	AssignedTo    *uuid.UUID             `json:"assigned_to"`
+	ProjectID     uuid.UUID              `json:"project_id" validate:"required,uuid"` // Добавил теги
+	ParentTaskID  *uuid.UUID             `json:"parent_task_id,omitempty"`            // Если есть parent task
+	EstimatedTime int                    `json:"estimated_time" validate:"omitempty,min=0" example:"60"`
+	CustomFields  map[string]interface{} `json:"custom_fields"` // Значения пользовательских полей по ID поля
+}
+type MoveTask struct {
+	SectionID     *uuid.UUID `json:"section_id" validate:"required_without=UserSectionID,excluded_with=UserSectionID"`
+	UserSectionID *uuid.UUID `json:"user_section_id" validate:"required_without=SectionID"`
+	AfterTaskID   *uuid.UUID `json:"after_task_id" validate:"excluded_with=BeforeTaskID"` // Поставить сразу после этой задачи
+	BeforeTaskID  *uuid.UUID `json:"before_task_id"`                                      // Поставить сразу перед этой задачей
+	// Перенос между дорожками доски меняет поле группировки в той же транзакции
+	Swimlane    string     `json:"swimlane" validate:"omitempty,oneof=assignee priority user_group label custom_field,excluded_with=UserSectionID" example:"assignee"`
+	FieldID     *uuid.UUID `json:"field_id" validate:"required_if=Swimlane custom_field"`       // Поле для custom_field
+	LaneKey     *string    `json:"lane_key" validate:"required_with=Swimlane,omitempty,max=50"` // Ключ целевой дорожки, "" - дорожка без значения
+	FromLaneKey string     `json:"from_lane_key" validate:"max=50"`                             // label: метка дорожки, из которой перенесли задачу
+}
+type CreateCalendarFeed struct {
+	ProjectID *uuid.UUID `json:"project_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Без проекта - лента назначенных задач
+}
+type QueryBoard struct {
+	Swimlane string `validate:"omitempty,oneof=assignee priority user_group label custom_field"`
+	FieldID  string `validate:"required_if=Swimlane custom_field,omitempty,uuid"`
+}
+type SectionWIPLimit struct {
+	WIPLimit int  `json:"wip_limit" validate:"min=0,max=1000" example:"5"` // 0 снимает лимит
+	Strict   bool `json:"strict"`                                          // Запрещать перенос сверх лимита
+}
+type BulkTask struct {
+	TaskIDs    []uuid.UUID `json:"task_ids" validate:"required,min=1,max=500,dive,required"`
+	Operation  string      `json:"operation" validate:"required,oneof=set_status set_priority reassign move_section add_group set_due_date delete" example:"set_status"`
+	Status     string      `json:"status" validate:"required_if=Operation set_status,max=50" example:"done"`
+	Force      bool        `json:"force"` // set_status: закрыть задачи, несмотря на блокеры
+	Priority   string      `json:"priority" validate:"required_if=Operation set_priority,max=50" example:"high"`
+	AssignedTo *uuid.UUID  `json:"assigned_to" validate:"required_if=Operation reassign"`
+	SectionID  *uuid.UUID  `json:"section_id" validate:"required_if=Operation move_section"`
+	GroupID    *uuid.UUID  `json:"group_id" validate:"required_if=Operation add_group"`
+	DueDate    *time.Time  `json:"due_date" example:"2024-10-07T09:00:00Z"` // set_due_date: null снимает срок
+}
+type MoveSubtask struct {
+	ParentTaskID *uuid.UUID `json:"parent_task_id" example:"550e8400-e29b-41d4-a716-446655440000"` // nil - сделать задачу корневой
 }
 type CreateComment struct {
-	Body   string    `json:"body" validate:"required" example:"fake comment"`
-	TaskID uuid.UUID `json:"task_id" validate:"required,uuid"` // Добавил теги
+	Body      string     `json:"body" validate:"required,max=10000" example:"fake comment"`
+	TaskID    uuid.UUID  `json:"task_id" validate:"required,uuid"` // Добавил теги
+	ReplyToID *uuid.UUID `json:"reply_to_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CitateID  *uuid.UUID `json:"citate_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Цитируемый комментарий
+}
+type UpdateComment struct {
+	Body string `json:"body" validate:"required,max=10000" example:"edited comment"`
+}
+type CommentReaction struct {
+	Emoji string `json:"emoji" validate:"required,max=32,emoji" example:"👍"`
+}
+type QueryComments struct {
+	Limit  int    `validate:"omitempty,min=1,max=100"` // Количество веток верхнего уровня
+	Cursor string `validate:"omitempty,base64url,max=512"`
+}
+type QueryMentions struct {
+	Unread bool
+	Limit  int    `validate:"omitempty,min=1,max=100"`
+	Cursor string `validate:"omitempty,base64url,max=512"`
 }
 
 type CreateUserGroup struct {
@@ -56,3 +117,145 @@ type UpdateTaskTitleOrDescription struct {
 	Title       string    `json:"title" validate:"required" example:"Title task"`
 	Description string    `json:"description" validate:"required" example:"Lorem ipsum"`
 }
+
+type WorkflowStatus struct {
+	Name      string `json:"name" validate:"required,max=50" example:"in_progress"`
+	IsInitial bool   `json:"is_initial" example:"false"`
+	IsFinal   bool   `json:"is_final" example:"false"`
+}
+type WorkflowTransition struct {
+	From   string   `json:"from" validate:"required,max=50" example:"todo"`
+	To     string   `json:"to" validate:"required,max=50" example:"in_progress"`
+	Guards []string `json:"guards" validate:"omitempty,dive,oneof=assignee_required subtasks_done estimate_required due_date_required"`
+}
+type UpdateWorkflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses" validate:"required,min=1,dive"`
+	Transitions []WorkflowTransition `json:"transitions" validate:"dive"`
+}
+type UpdateTaskStatus struct {
+	Status string `json:"status" validate:"required,max=50" example:"in_progress"`
+	Force  bool   `json:"force" example:"false"` // Закрыть задачу, даже если есть открытые блокеры
+}
+
+// ImportTasks - параметры импорта задач из CSV. Mapping: поле задачи -> заголовок колонки,
+// без сопоставления колонка называется как поле
+type ImportTasks struct {
+	DryRun  bool              `json:"dry_run" example:"true"` // Только проверить строки, ничего не сохраняя
+	Mapping map[string]string `json:"mapping" validate:"dive,keys,oneof=title description status priority due_date assignee_email section,endkeys,max=100"`
+}
+
+// UpdateTaskTodo - состояние задачи из VTODO CalDAV-клиента, ресурс заменяется целиком
+type UpdateTaskTodo struct {
+	Title       string     `validate:"required,max=50"`
+	Description string
+	DueDate     *time.Time // nil снимает срок
+	Completed   bool
+	ETag        string     // Из If-Match, пустой - без проверки версии
+}
+
+type QueryTask struct {
+	Status       string            `validate:"omitempty,max=255"` // Список через запятую
+	Priority     string            `validate:"omitempty,max=255"` // Список через запятую
+	ProjectID    string            `validate:"omitempty,uuid"`
+	SectionID    string            `validate:"omitempty,uuid"`
+	AssignedTo   string            `validate:"omitempty,uuid"`
+	ParentTaskID string            `validate:"omitempty,uuid|eq=null"` // null - только корневые задачи
+	DueFrom      string            `validate:"omitempty,datetime=2006-01-02"`
+	DueTo        string            `validate:"omitempty,datetime=2006-01-02"`
+	Sort         string            `validate:"omitempty,max=50"` // Колонка, "-" в начале - по убыванию
+	Limit        int               `validate:"omitempty,min=1,max=100"`
+	Cursor       string            `validate:"omitempty,base64url,max=512"`
+	Labels       string            `validate:"omitempty,max=1000"`                              // ID меток через запятую
+	LabelMatch   string            `validate:"omitempty,oneof=any all"`                         // any - хотя бы одна, all - все
+	CustomFields map[string]string `validate:"omitempty,max=20,dive,keys,uuid,endkeys,max=255"` // ID поля -> значение, "1..5" - диапазон
+}
+
+type QueryTaskHistory struct {
+	Limit  int    `validate:"omitempty,min=1,max=100"`
+	Cursor string `validate:"omitempty,base64url,max=512"`
+}
+
+type CreateTaskLink struct {
+	TargetTaskID uuid.UUID `json:"target_task_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type         string    `json:"type" validate:"required,oneof=blocks blocked_by relates_to duplicates" example:"blocks"`
+}
+
+type SetRecurrence struct {
+	RRule         string     `json:"rrule" validate:"required,max=255,rrule" example:"FREQ=WEEKLY;BYDAY=MO"`
+	DTStart       *time.Time `json:"dtstart" example:"2024-10-07T09:00:00Z"` // По умолчанию - срок задачи
+	Title         *string    `json:"title" validate:"omitempty,max=50" example:"Weekly report"`
+	Description   *string    `json:"description" example:"Lorem ipsum"`
+	Priority      *string    `json:"priority" validate:"omitempty,max=50" example:"high"`
+	EstimatedTime *int       `json:"estimated_time" validate:"omitempty,min=0" example:"60"`
+}
+type AddRecurrenceException struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02" example:"2024-10-14"`
+}
+
+type StartTimer struct {
+	Note string `json:"note" validate:"max=500" example:"Code review"`
+}
+
+type CreateTimeEntry struct {
+	StartedAt time.Time `json:"started_at" validate:"required" example:"2024-10-07T09:00:00Z"`
+	Duration  int       `json:"duration" validate:"required,min=1,max=1440" example:"90"` // Минуты
+	Note      string    `json:"note" validate:"max=500" example:"Code review"`
+}
+
+type UpdateTimeEntry struct {
+	StartedAt *time.Time `json:"started_at" example:"2024-10-07T09:00:00Z"`
+	Duration  *int       `json:"duration" validate:"omitempty,min=1,max=1440" example:"60"`
+	EditNote  string     `json:"edit_note" validate:"required,max=500" example:"Forgot to stop the timer"`
+}
+
+type QueryTimesheet struct {
+	User string `validate:"omitempty,uuid"` // По умолчанию - текущий пользователь
+	From string `validate:"required,datetime=2006-01-02"`
+	To   string `validate:"required,datetime=2006-01-02"`
+}
+
+type CreateProjectTemplate struct {
+	Title       string `json:"title" validate:"required,max=50" example:"Client onboarding"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type CreateTaskTemplate struct {
+	Title string `json:"title" validate:"required,max=50" example:"Release checklist"`
+}
+
+type InstantiateTaskTemplate struct {
+	ProjectID uuid.UUID  `json:"project_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SectionID uuid.UUID  `json:"section_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StartDate *time.Time `json:"start_date" example:"2024-10-07T09:00:00Z"` // По умолчанию - сейчас
+}
+
+type CreateLabel struct {
+	Name  string `json:"name" validate:"required,max=50" example:"bug"`
+	Color string `json:"color" validate:"omitempty,hexcolor" example:"#e53935"`
+}
+
+type UpdateLabel struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=50" example:"defect"`
+	Color *string `json:"color" validate:"omitempty,hexcolor" example:"#e53935"`
+}
+
+type MergeLabel struct {
+	TargetLabelID uuid.UUID `json:"target_label_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+type CreateCustomField struct {
+	Name    string   `json:"name" validate:"required,max=50" example:"Severity"`
+	Type    string   `json:"type" validate:"required,oneof=text number date select multi_select user checkbox" example:"select"`
+	Options []string `json:"options" validate:"required_if=Type select,required_if=Type multi_select,omitempty,max=100,unique,dive,required,max=50,excludesall=0x2C0x7C'"` // Варианты для select и multi_select
+	Order   int      `json:"order" validate:"min=0" example:"0"`
+}
+
+type UpdateCustomField struct {
+	Name    *string  `json:"name" validate:"omitempty,min=1,max=50" example:"Impact"`
+	Options []string `json:"options" validate:"omitempty,max=100,unique,dive,required,max=50,excludesall=0x2C0x7C'"` // Полный список вариантов
+	Order   *int     `json:"order" validate:"omitempty,min=0" example:"1"`
+}
+
+type SetCustomFieldValues struct {
+	Values map[string]interface{} `json:"values" validate:"required"` // ID поля -> значение, null очищает поле
+}
@@ -0,0 +1,14 @@
+package validation
+
+type QueryAuditLog struct {
+	ActorID    string `validate:"omitempty,uuid"`
+	Action     string `validate:"omitempty,max=255"` // Список через запятую
+	EntityType string `validate:"omitempty,max=50"`
+	EntityID   string `validate:"omitempty,uuid"`
+	IP         string `validate:"omitempty,ip"`
+	From       string `validate:"omitempty,datetime=2006-01-02"`
+	To         string `validate:"omitempty,datetime=2006-01-02"`
+	Limit      int    `validate:"omitempty,min=1,max=100"`
+	Cursor     string `validate:"omitempty,base64url,max=512"`
+	Format     string `validate:"omitempty,oneof=json ndjson"` // ndjson - выгрузка всех записей без пагинации
+}
@@ -2,8 +2,10 @@ package validation
 
 import (
 	"regexp"
+	"unicode"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/teambition/rrule-go"
 )
 
 func Password(field validator.FieldLevel) bool {
@@ -19,3 +21,39 @@ func Password(field validator.FieldLevel) bool {
 
 	return true
 }
+
+func RRule(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	if ok && value != "" {
+		option, err := rrule.StrToROption(value)
+		if err != nil {
+			return false
+		}
+		_, err = rrule.NewRRule(*option)
+		return err == nil
+	}
+
+	return true
+}
+
+// Emoji пропускает одно эмодзи, в том числе составное: с модификатором тона,
+// вариационным селектором, через ZWJ, флаги и keycap
+func Emoji(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	if !ok || value == "" {
+		return true
+	}
+
+	hasSymbol := false
+	for _, r := range value {
+		switch {
+		case unicode.Is(unicode.So, r), r == 0x20E3:
+			hasSymbol = true
+		case unicode.Is(unicode.Sk, r), r == 0x200D, r == 0xFE0F, r == 0xFE0E,
+			r >= 0xE0020 && r <= 0xE007F, r >= '0' && r <= '9', r == '#', r == '*':
+		default:
+			return false
+		}
+	}
+	return hasSymbol
+}
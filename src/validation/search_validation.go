@@ -0,0 +1,8 @@
+package validation
+
+type QuerySearch struct {
+	Query string `validate:"required,max=255"`
+	Types string `validate:"omitempty,max=50"`    // task,comment,project через запятую
+	Page  int    `validate:"number,min=1"`        // По умолчанию 1, подставляет контроллер
+	Limit int    `validate:"number,min=1,max=50"` // По умолчанию 10
+}
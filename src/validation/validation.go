@@ -18,6 +18,8 @@ var customMessages = map[string]string{
 	"alphanum": "Field %s must contain only alphanumeric characters",
 	"oneof":    "Invalid value for field %s",
 	"password": "Field %s must contain at least 1 letter and 1 number",
+	"rrule":    "Field %s must be a valid RFC 5545 recurrence rule",
+	"emoji":    "Field %s must be a single emoji",
 }
 
 func CustomErrorMessages(err error) map[string]string {
@@ -62,5 +64,13 @@ func Validator() *validator.Validate {
 		return nil
 	}
 
+	if err := validate.RegisterValidation("rrule", RRule); err != nil {
+		return nil
+	}
+
+	if err := validate.RegisterValidation("emoji", Emoji); err != nil {
+		return nil
+	}
+
 	return validate
 }
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"app/src/model"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ClearProjects удаляет проекты вместе с секциями, задачами и всем, что на них ссылается
+func ClearProjects(db *gorm.DB) {
+	err := db.Exec("TRUNCATE projects CASCADE").Error
+	if err != nil {
+		logrus.Fatalf("Failed clear project data : %+v", err)
+	}
+}
+
+// InsertProject создаёт проект с участниками и секцией "To Do"
+func InsertProject(db *gorm.DB, title string, members ...*model.User) (*model.Project, *model.Section) {
+	project := &model.Project{Title: title}
+	if err := db.Create(project).Error; err != nil {
+		logrus.Errorf("Failed create project : %+v", err)
+	}
+	for _, member := range members {
+		projectUser := &model.ProjectUser{ProjectID: project.ID, UserID: member.ID}
+		if err := db.Create(projectUser).Error; err != nil {
+			logrus.Errorf("Failed add project member : %+v", err)
+		}
+	}
+
+	section := &model.Section{Title: "To Do", ProjectID: project.ID}
+	if err := db.Create(section).Error; err != nil {
+		logrus.Errorf("Failed create section : %+v", err)
+	}
+	return project, section
+}
+
+// InsertTask создаёт задачи в секции проекта
+func InsertTask(db *gorm.DB, section *model.Section, tasks ...*model.Task) {
+	for _, task := range tasks {
+		task.ProjectID = section.ProjectID
+		task.SectionID = section.ID
+		if task.Status == "" {
+			task.Status = model.TaskStatusTodo
+		}
+		if err := db.Create(task).Error; err != nil {
+			logrus.Errorf("Failed create task : %+v", err)
+		}
+	}
+}
+
+// InsertProjectPermission выдаёт пользователю роль в проекте
+func InsertProjectPermission(db *gorm.DB, project *model.Project, user *model.User, role string) {
+	permission := &model.ProjectPermission{ProjectID: project.ID, UserID: user.ID, Role: role}
+	if err := db.Create(permission).Error; err != nil {
+		logrus.Errorf("Failed create project permission : %+v", err)
+	}
+}
+
+// InsertSection добавляет в проект секцию
+func InsertSection(db *gorm.DB, project *model.Project, section *model.Section) *model.Section {
+	section.ProjectID = project.ID
+	if err := db.Create(section).Error; err != nil {
+		logrus.Errorf("Failed create section : %+v", err)
+	}
+	return section
+}
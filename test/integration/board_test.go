@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardRoutes(t *testing.T) {
+	move := func(t *testing.T, task *model.Task, body map[string]interface{}) *http.Response {
+		accessToken, err := fixture.AccessToken(fixture.UserOne)
+		require.NoError(t, err)
+		bodyJSON, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/v1/tasks/"+task.ID.String()+"/move",
+			strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+		apiResponse, err := test.App.Test(request)
+		require.NoError(t, err)
+		return apiResponse
+	}
+
+	t.Run("PUT /v1/tasks/:taskID/move with WIP limits", func(t *testing.T) {
+		setup := func(strict bool) (*model.Task, *model.Section) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+			project, todo := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+			doing := helper.InsertSection(test.DB, project, &model.Section{
+				Title: "Doing", Order: 1, WIPLimit: 1, WIPStrict: strict,
+			})
+			task := &model.Task{Title: "Next"}
+			helper.InsertTask(test.DB, todo, task)
+			helper.InsertTask(test.DB, doing, &model.Task{Title: "Current"})
+			return task, doing
+		}
+
+		t.Run("should return 409 when a strict limit is reached", func(t *testing.T) {
+			task, doing := setup(true)
+			apiResponse := move(t, task, map[string]interface{}{"section_id": doing.ID})
+			assert.Equal(t, http.StatusConflict, apiResponse.StatusCode)
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "id = ?", task.ID).Error)
+			assert.Equal(t, task.SectionID, saved.SectionID)
+		})
+
+		t.Run("should move with a warning when the limit is not strict", func(t *testing.T) {
+			task, doing := setup(false)
+			apiResponse := move(t, task, map[string]interface{}{"section_id": doing.ID})
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+			assert.NotEmpty(t, apiResponse.Header.Get("X-WIP-Warning"))
+		})
+	})
+
+	t.Run("PUT /v1/tasks/:taskID/move between swimlanes", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne)
+		project, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+		task := &model.Task{Title: "Lane task", Priority: "low"}
+		helper.InsertTask(test.DB, section, task)
+
+		attached := &model.UserGroup{TeamTitle: "Backend team", OwnerID: fixture.UserOne.ID}
+		other := &model.UserGroup{TeamTitle: "Other team", OwnerID: fixture.UserOne.ID}
+		require.NoError(t, test.DB.Create(attached).Error)
+		require.NoError(t, test.DB.Create(other).Error)
+		require.NoError(t, test.DB.Exec("INSERT INTO project_user_groups (project_id, user_group_id) VALUES (?, ?)",
+			project.ID, attached.ID).Error)
+
+		laneMove := func(swimlane, key string) int {
+			return move(t, task, map[string]interface{}{
+				"section_id": section.ID, "swimlane": swimlane, "lane_key": key,
+			}).StatusCode
+		}
+
+		t.Run("should only accept groups attached to the project", func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, laneMove("user_group", other.ID.String()))
+			assert.Equal(t, http.StatusOK, laneMove("user_group", attached.ID.String()))
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "id = ?", task.ID).Error)
+			assert.Equal(t, &attached.ID, saved.UserGroup)
+		})
+
+		t.Run("should only accept priorities shown on the board", func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, laneMove("priority", "whenever"))
+			assert.Equal(t, http.StatusOK, laneMove("priority", "high"))
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "id = ?", task.ID).Error)
+			assert.Equal(t, "high", saved.Priority)
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFieldRoutes(t *testing.T) {
+	t.Run("POST /v1/projects/:projectID/custom-fields", func(t *testing.T) {
+		create := func(t *testing.T, project *model.Project, user *model.User) int {
+			accessToken, err := fixture.AccessToken(user)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodPost, "/v1/projects/"+project.ID.String()+"/custom-fields",
+				strings.NewReader(`{"name":"Severity","type":"text"}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			return apiResponse.StatusCode
+		}
+
+		setup := func() *model.Project {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			project, _ := helper.InsertProject(test.DB, "Backend", fixture.UserOne, fixture.UserTwo)
+			helper.InsertProjectPermission(test.DB, project, fixture.UserOne, model.ProjectRoleOwner)
+			return project
+		}
+
+		t.Run("should let the project owner define a field", func(t *testing.T) {
+			project := setup()
+			assert.Equal(t, http.StatusCreated, create(t, project, fixture.UserOne))
+		})
+
+		t.Run("should return 403 for a member without a managing role", func(t *testing.T) {
+			project := setup()
+			assert.Equal(t, http.StatusForbidden, create(t, project, fixture.UserTwo))
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.CustomField{}).Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	})
+}
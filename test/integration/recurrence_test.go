@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceRoutes(t *testing.T) {
+	t.Run("PUT /v1/tasks/:taskID/recurrence", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			_, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+			dueDate := time.Now().Add(24 * time.Hour)
+			task := &model.Task{Title: "Weekly report", DueDate: &dueDate}
+			helper.InsertTask(test.DB, section, task)
+
+			accessToken, err := fixture.AccessToken(fixture.UserTwo)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodPut, "/v1/tasks/"+task.ID.String()+"/recurrence",
+				strings.NewReader(`{"rrule":"FREQ=WEEKLY"}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.TaskRecurrence{}).Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	})
+}
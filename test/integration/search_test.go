@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRoutes(t *testing.T) {
+	helper.ClearAll(test.DB)
+	helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+
+	_, ownSection := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+	_, otherSection := helper.InsertProject(test.DB, "Secret", fixture.UserTwo)
+	helper.InsertTask(test.DB, ownSection,
+		&model.Task{Title: "Release notes", Description: "Mention the database upgrade"},
+		&model.Task{Title: "Database migration", Description: "Run the database migration before the database backup"},
+	)
+	helper.InsertTask(test.DB, ownSection, &model.Task{Title: "Deploy", Description: `<img src=x onerror="alert(1)">`})
+	helper.InsertTask(test.DB, otherSection, &model.Task{Title: "Database password rotation"})
+
+	accessToken, err := fixture.AccessToken(fixture.UserOne)
+	require.NoError(t, err)
+
+	search := func(t *testing.T, query string) (int, *response.SuccessWithPaginate[response.SearchResult]) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/search?"+query, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+		apiResponse, err := test.App.Test(request)
+		require.NoError(t, err)
+		bytes, err := io.ReadAll(apiResponse.Body)
+		require.NoError(t, err)
+
+		responseBody := new(response.SuccessWithPaginate[response.SearchResult])
+		require.NoError(t, json.Unmarshal(bytes, responseBody))
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("GET /v1/search", func(t *testing.T) {
+		t.Run("should rank title matches first and hide tasks of other projects", func(t *testing.T) {
+			status, body := search(t, "q=database&types=task")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(2), body.TotalResults)
+			require.Len(t, body.Results, 2)
+			assert.Equal(t, "Database migration", body.Results[0].Title)
+			assert.Equal(t, "Release notes", body.Results[1].Title)
+			assert.Greater(t, body.Results[0].Rank, body.Results[1].Rank)
+			assert.Contains(t, body.Results[0].Snippet, "<mark>")
+		})
+
+		t.Run("should escape HTML typed by users in snippets", func(t *testing.T) {
+			status, body := search(t, "q=deploy&types=task")
+			assert.Equal(t, http.StatusOK, status)
+			require.Len(t, body.Results, 1)
+			assert.Contains(t, body.Results[0].Snippet, "<mark>Deploy</mark>")
+			assert.Contains(t, body.Results[0].Snippet, "&lt;img")
+			assert.NotContains(t, body.Results[0].Snippet, "<img")
+		})
+
+		t.Run("should not repeat results for a repeated type", func(t *testing.T) {
+			status, body := search(t, "q=database&types=task,task")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(2), body.TotalResults)
+			assert.Len(t, body.Results, 2)
+		})
+
+		t.Run("should page through results", func(t *testing.T) {
+			status, body := search(t, "q=database&types=task&limit=1&page=2")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(2), body.TotalResults)
+			assert.Equal(t, int64(2), body.TotalPages)
+			require.Len(t, body.Results, 1)
+			assert.Equal(t, "Release notes", body.Results[0].Title)
+		})
+
+		t.Run("should return 400 for page or limit below 1", func(t *testing.T) {
+			for _, query := range []string{"q=database&page=0", "q=database&limit=0", "q=database&limit=-1"} {
+				status, _ := search(t, query)
+				assert.Equal(t, http.StatusBadRequest, status, query)
+			}
+		})
+
+		t.Run("should return 400 for unknown type", func(t *testing.T) {
+			status, _ := search(t, "q=database&types=user")
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtaskRoutes(t *testing.T) {
+	setup := func() (parent, child *model.Task) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+		_, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+		parent = &model.Task{Title: "Story"}
+		child = &model.Task{Title: "Subtask"}
+		helper.InsertTask(test.DB, section, parent, child)
+		return parent, child
+	}
+
+	t.Run("GET /v1/tasks/:taskID/subtasks", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			parent, _ := setup()
+			accessToken, err := fixture.AccessToken(fixture.UserTwo)
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/tasks/"+parent.ID.String()+"/subtasks", nil)
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+		})
+	})
+
+	t.Run("PUT /v1/tasks/:taskID/parent", func(t *testing.T) {
+		move := func(t *testing.T, task, parent *model.Task, user *model.User) int {
+			accessToken, err := fixture.AccessToken(user)
+			require.NoError(t, err)
+			bodyJSON, err := json.Marshal(map[string]interface{}{"parent_task_id": parent.ID})
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPut, "/v1/tasks/"+task.ID.String()+"/parent",
+				strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			return apiResponse.StatusCode
+		}
+
+		t.Run("should move the task and record the parent change in history", func(t *testing.T) {
+			parent, child := setup()
+			assert.Equal(t, http.StatusOK, move(t, child, parent, fixture.UserOne))
+
+			var history []model.TaskHistory
+			require.NoError(t, test.DB.Where("task_id = ?", child.ID).Find(&history).Error)
+			require.Len(t, history, 1)
+			require.Len(t, history[0].Changes, 1)
+			assert.Equal(t, "parent_task_id", history[0].Changes[0].Field)
+		})
+
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			parent, child := setup()
+			assert.Equal(t, http.StatusNotFound, move(t, child, parent, fixture.UserTwo))
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "id = ?", child.ID).Error)
+			assert.Nil(t, saved.ParentTaskID)
+		})
+	})
+
+	t.Run("POST /v1/tasks with parent_task_id", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			parent, _ := setup()
+			accessToken, err := fixture.AccessToken(fixture.UserTwo)
+			require.NoError(t, err)
+			bodyJSON, err := json.Marshal(map[string]interface{}{
+				"title": "Injected", "project_id": parent.ProjectID, "parent_task_id": parent.ID, "estimated_time": 600,
+			})
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.Task{}).Where("parent_task_id = ?", parent.ID).Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLinkRoutes(t *testing.T) {
+	// У UserOne и UserTwo по проекту с одной задачей
+	setup := func() (own, foreign *model.Task) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+		_, ownSection := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+		_, foreignSection := helper.InsertProject(test.DB, "Secret", fixture.UserTwo)
+		own = &model.Task{Title: "Release"}
+		foreign = &model.Task{Title: "Rotate keys"}
+		helper.InsertTask(test.DB, ownSection, own)
+		helper.InsertTask(test.DB, foreignSection, foreign)
+		return own, foreign
+	}
+
+	send := func(t *testing.T, method, url, body string, user *model.User) int {
+		accessToken, err := fixture.AccessToken(user)
+		require.NoError(t, err)
+		request := httptest.NewRequest(method, url, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+		apiResponse, err := test.App.Test(request)
+		require.NoError(t, err)
+		return apiResponse.StatusCode
+	}
+
+	t.Run("POST /v1/tasks/:taskID/links", func(t *testing.T) {
+		t.Run("should return 404 when the target task is in another project", func(t *testing.T) {
+			own, foreign := setup()
+			bodyJSON, err := json.Marshal(map[string]interface{}{"target_task_id": foreign.ID, "type": "blocked_by"})
+			require.NoError(t, err)
+
+			status := send(t, http.MethodPost, "/v1/tasks/"+own.ID.String()+"/links", string(bodyJSON), fixture.UserOne)
+			assert.Equal(t, http.StatusNotFound, status)
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.TaskLink{}).Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	})
+
+	t.Run("GET /v1/tasks/:taskID/links", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			own, _ := setup()
+			status := send(t, http.MethodGet, "/v1/tasks/"+own.ID.String()+"/links", "", fixture.UserTwo)
+			assert.Equal(t, http.StatusNotFound, status)
+		})
+	})
+
+	t.Run("DELETE /v1/tasks/:taskID/links/:linkID", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			own, foreign := setup()
+			link := &model.TaskLink{
+				SourceTaskID: foreign.ID, TargetTaskID: own.ID, Type: model.TaskLinkBlocks, CreatedBy: fixture.UserTwo.ID,
+			}
+			require.NoError(t, test.DB.Create(link).Error)
+
+			status := send(t, http.MethodDelete,
+				"/v1/tasks/"+foreign.ID.String()+"/links/"+link.ID.String(), "", fixture.UserTwo)
+			assert.Equal(t, http.StatusNotFound, status)
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.TaskLink{}).Count(&count).Error)
+			assert.Equal(t, int64(1), count)
+		})
+	})
+}
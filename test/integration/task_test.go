@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRoutes(t *testing.T) {
+	t.Run("POST /v1/tasks", func(t *testing.T) {
+		t.Run("should rank the task in the first project section and in the assignee's section", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+			project, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+			userSection := &model.UserSection{Title: "Recently Assigned", UserID: fixture.UserOne.ID}
+			require.NoError(t, test.DB.Create(userSection).Error)
+
+			accessToken, err := fixture.AccessToken(fixture.UserOne)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodPost, "/v1/tasks",
+				strings.NewReader(`{"title":"Release","project_id":"`+project.ID.String()+`"}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "project_id = ?", project.ID).Error)
+			assert.Equal(t, section.ID, saved.SectionID)
+			require.NotNil(t, saved.UserSectionID)
+			assert.Equal(t, userSection.ID, *saved.UserSectionID)
+			assert.NotEmpty(t, saved.Rank)
+			assert.NotEmpty(t, saved.UserRank)
+		})
+	})
+
+	t.Run("GET /v1/tasks/:taskID", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+		_, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+		task := &model.Task{Title: "Release"}
+		helper.InsertTask(test.DB, section, task)
+		require.NoError(t, test.DB.Create(&model.Comment{TaskID: task.ID, UserID: fixture.UserOne.ID, Body: "Internal note"}).Error)
+
+		get := func(t *testing.T, user *model.User) int {
+			accessToken, err := fixture.AccessToken(user)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodGet, "/v1/tasks/"+task.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			return apiResponse.StatusCode
+		}
+
+		t.Run("should return the task to a project member", func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, get(t, fixture.UserOne))
+		})
+
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			assert.Equal(t, http.StatusNotFound, get(t, fixture.UserTwo))
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashRoutes(t *testing.T) {
+	send := func(t *testing.T, method, url string, user *model.User) int {
+		accessToken, err := fixture.AccessToken(user)
+		require.NoError(t, err)
+		request := httptest.NewRequest(method, url, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+		apiResponse, err := test.App.Test(request)
+		require.NoError(t, err)
+		return apiResponse.StatusCode
+	}
+
+	setup := func() *model.Project {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+		project, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne, fixture.UserTwo)
+		helper.InsertProjectPermission(test.DB, project, fixture.UserOne, model.ProjectRoleOwner)
+		helper.InsertTask(test.DB, section, &model.Task{Title: "Release"})
+		return project
+	}
+
+	t.Run("DELETE /v1/tasks/:taskID", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			_, section := helper.InsertProject(test.DB, "Secret", fixture.UserOne)
+			task := &model.Task{Title: "Rotate keys"}
+			helper.InsertTask(test.DB, section, task)
+
+			assert.Equal(t, http.StatusNotFound, send(t, http.MethodDelete, "/v1/tasks/"+task.ID.String(), fixture.UserTwo))
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "id = ?", task.ID).Error)
+		})
+	})
+
+	t.Run("DELETE /v1/projects/:projectID", func(t *testing.T) {
+		t.Run("should return 403 for a member without a managing role", func(t *testing.T) {
+			project := setup()
+			assert.Equal(t, http.StatusForbidden, send(t, http.MethodDelete, "/v1/projects/"+project.ID.String(), fixture.UserTwo))
+
+			var saved model.Project
+			require.NoError(t, test.DB.First(&saved, "id = ?", project.ID).Error)
+		})
+	})
+
+	t.Run("POST /v1/projects/:projectID/restore", func(t *testing.T) {
+		t.Run("should let only the owner restore the project", func(t *testing.T) {
+			project := setup()
+			require.Equal(t, http.StatusOK, send(t, http.MethodDelete, "/v1/projects/"+project.ID.String(), fixture.UserOne))
+
+			restore := "/v1/projects/" + project.ID.String() + "/restore"
+			assert.Equal(t, http.StatusForbidden, send(t, http.MethodPost, restore, fixture.UserTwo))
+			assert.Equal(t, http.StatusOK, send(t, http.MethodPost, restore, fixture.UserOne))
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.Task{}).Where("project_id = ?", project.ID).Count(&count).Error)
+			assert.Equal(t, int64(1), count)
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowRoutes(t *testing.T) {
+	t.Run("PUT /v1/projects/:projectID/workflow", func(t *testing.T) {
+		workflow := validation.UpdateWorkflow{
+			Statuses: []validation.WorkflowStatus{
+				{Name: "open", IsInitial: true},
+				{Name: "closed", IsFinal: true},
+			},
+			Transitions: []validation.WorkflowTransition{{From: "open", To: "closed"}},
+		}
+		bodyJSON, err := json.Marshal(workflow)
+		require.NoError(t, err)
+
+		update := func(t *testing.T, projectID string, user *model.User) int {
+			accessToken, err := fixture.AccessToken(user)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodPut, "/v1/projects/"+projectID+"/workflow",
+				strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			return apiResponse.StatusCode
+		}
+
+		t.Run("should let the project owner replace the workflow", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+			project, _ := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+			helper.InsertProjectPermission(test.DB, project, fixture.UserOne, model.ProjectRoleOwner)
+
+			assert.Equal(t, http.StatusOK, update(t, project.ID.String(), fixture.UserOne))
+
+			var statuses []model.WorkflowStatus
+			require.NoError(t, test.DB.Where("project_id = ?", project.ID).Order(`"order"`).Find(&statuses).Error)
+			require.Len(t, statuses, 2)
+			assert.Equal(t, "open", statuses[0].Name)
+		})
+
+		t.Run("should return 404 for a user outside the project", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			project, _ := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+
+			assert.Equal(t, http.StatusNotFound, update(t, project.ID.String(), fixture.UserTwo))
+		})
+
+		t.Run("should return 403 for a member without a managing role", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			project, _ := helper.InsertProject(test.DB, "Backend", fixture.UserOne, fixture.UserTwo)
+			helper.InsertProjectPermission(test.DB, project, fixture.UserOne, model.ProjectRoleOwner)
+
+			assert.Equal(t, http.StatusForbidden, update(t, project.ID.String(), fixture.UserTwo))
+
+			var count int64
+			require.NoError(t, test.DB.Model(&model.WorkflowStatus{}).Where("project_id = ?", project.ID).Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	})
+
+	t.Run("PUT /v1/tasks/:taskID/status", func(t *testing.T) {
+		t.Run("should return 404 for a user outside the project even with force", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			_, section := helper.InsertProject(test.DB, "Backend", fixture.UserOne)
+			task := &model.Task{Title: "Release"}
+			helper.InsertTask(test.DB, section, task)
+
+			accessToken, err := fixture.AccessToken(fixture.UserTwo)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodPut, "/v1/tasks/"+task.ID.String()+"/status",
+				strings.NewReader(`{"status":"in_progress","force":true}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+			apiResponse, err := test.App.Test(request)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+
+			var saved model.Task
+			require.NoError(t, test.DB.First(&saved, "id = ?", task.ID).Error)
+			assert.Equal(t, model.TaskStatusTodo, saved.Status)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUserService struct {
+	service.UserService
+	user    *model.User
+	lookups int
+}
+
+func (s *fakeUserService) GetUserByEmail(_ *fiber.Ctx, email string) (*model.User, error) {
+	s.lookups++
+	if email != s.user.Email {
+		return nil, errors.New("not found")
+	}
+	return s.user, nil
+}
+
+type fakeAuditService struct {
+	service.AuditService
+	entries []*model.AuditLog
+}
+
+func (s *fakeAuditService) Record(_ *fiber.Ctx, entry *model.AuditLog) {
+	s.entries = append(s.entries, entry)
+}
+
+func TestBasicAuth(t *testing.T) {
+	hash, err := utils.HashPassword("password1")
+	require.NoError(t, err)
+	users := &fakeUserService{user: &model.User{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		Email:     "test1@gmail.com",
+		Password:  hash,
+	}}
+	audit := &fakeAuditService{}
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/caldav", middleware.BasicAuth(users, audit), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	request := func(t *testing.T, email, password string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/caldav", nil)
+		if email != "" {
+			credentials := base64.StdEncoding.EncodeToString([]byte(email + ":" + password))
+			req.Header.Set(fiber.HeaderAuthorization, "Basic "+credentials)
+		}
+		apiResponse, err := app.Test(req)
+		require.NoError(t, err)
+		return apiResponse
+	}
+
+	t.Run("should challenge requests without credentials", func(t *testing.T) {
+		apiResponse := request(t, "", "")
+		assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+		assert.Contains(t, apiResponse.Header.Get(fiber.HeaderWWWAuthenticate), "Basic")
+		assert.Empty(t, audit.entries)
+	})
+
+	t.Run("should let the user in with the right password", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, request(t, "test1@gmail.com", "password1").StatusCode)
+	})
+
+	t.Run("should audit failed attempts and stop checking passwords after the limit", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, http.StatusUnauthorized, request(t, "test1@gmail.com", "wrong").StatusCode)
+		}
+		require.Len(t, audit.entries, 20)
+		assert.Equal(t, model.AuditLoginFailed, audit.entries[0].ActionType)
+		assert.Equal(t, &users.user.ID, audit.entries[0].EntityID)
+
+		lookups := users.lookups
+		assert.Equal(t, http.StatusTooManyRequests, request(t, "test1@gmail.com", "password1").StatusCode)
+		assert.Equal(t, lookups, users.lookups)
+		assert.Len(t, audit.entries, 20)
+	})
+}
@@ -0,0 +1,75 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheConfig(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(middleware.CacheConfig())
+	app.Get("/public", func(c *fiber.Ctx) error {
+		calls++
+		return c.SendString(strconv.Itoa(calls))
+	})
+	app.Get("/private", func(c *fiber.Ctx) error {
+		calls++
+		c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+		return c.SendString(strconv.Itoa(calls))
+	})
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		calls++
+		if c.Get(fiber.HeaderAuthorization) == "" {
+			return fiber.ErrUnauthorized
+		}
+		return c.SendString(strconv.Itoa(calls))
+	})
+
+	get := func(t *testing.T, target, authorization string) (int, string) {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		if authorization != "" {
+			request.Header.Set(fiber.HeaderAuthorization, authorization)
+		}
+		apiResponse, err := app.Test(request)
+		require.NoError(t, err)
+		body, err := io.ReadAll(apiResponse.Body)
+		require.NoError(t, err)
+		return apiResponse.StatusCode, string(body)
+	}
+
+	t.Run("should cache anonymous responses by full URL", func(t *testing.T) {
+		_, first := get(t, "/public?page=1", "")
+		_, again := get(t, "/public?page=1", "")
+		_, other := get(t, "/public?page=2", "")
+		assert.Equal(t, first, again)
+		assert.NotEqual(t, first, other)
+	})
+
+	t.Run("should not store responses marked as private", func(t *testing.T) {
+		_, first := get(t, "/private", "")
+		_, again := get(t, "/private", "")
+		assert.NotEqual(t, first, again)
+	})
+
+	t.Run("should not serve or store responses for authenticated requests", func(t *testing.T) {
+		status, _ := get(t, "/protected", "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		status, first := get(t, "/protected", "Bearer token")
+		assert.Equal(t, http.StatusOK, status)
+		_, again := get(t, "/protected", "Bearer token")
+		assert.NotEqual(t, first, again)
+
+		status, _ = get(t, "/protected", "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+}
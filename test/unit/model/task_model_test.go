@@ -0,0 +1,474 @@
+package model_test
+
+import (
+	"app/src/model"
+	"app/src/validation"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTaskModel(t *testing.T) {
+	t.Run("Create task validation", func(t *testing.T) {
+		parentID := uuid.New()
+		var newTask = validation.CreateTask{
+			Title:         "Subtask",
+			ProjectID:     uuid.New(),
+			ParentTaskID:  &parentID,
+			EstimatedTime: 30,
+		}
+
+		t.Run("should correctly validate a valid subtask", func(t *testing.T) {
+			err := validate.Struct(newTask)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if estimated time is negative", func(t *testing.T) {
+			newTask.EstimatedTime = -1
+			err := validate.Struct(newTask)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if title is empty", func(t *testing.T) {
+			newTask.EstimatedTime = 0
+			newTask.Title = ""
+			err := validate.Struct(newTask)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Update workflow validation", func(t *testing.T) {
+		var workflow = validation.UpdateWorkflow{
+			Statuses: []validation.WorkflowStatus{
+				{Name: "todo", IsInitial: true},
+				{Name: "done", IsFinal: true},
+			},
+			Transitions: []validation.WorkflowTransition{
+				{From: "todo", To: "done", Guards: []string{"assignee_required", "subtasks_done"}},
+			},
+		}
+
+		t.Run("should correctly validate a valid workflow", func(t *testing.T) {
+			err := validate.Struct(workflow)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if guard is unknown", func(t *testing.T) {
+			workflow.Transitions[0].Guards = []string{"unknown"}
+			err := validate.Struct(workflow)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if there are no statuses", func(t *testing.T) {
+			workflow.Transitions[0].Guards = nil
+			workflow.Statuses = nil
+			err := validate.Struct(workflow)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Create task link validation", func(t *testing.T) {
+		var newLink = validation.CreateTaskLink{
+			TargetTaskID: uuid.New(),
+			Type:         "blocked_by",
+		}
+
+		t.Run("should correctly validate a valid link", func(t *testing.T) {
+			err := validate.Struct(newLink)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if link type is unknown", func(t *testing.T) {
+			newLink.Type = "depends_on"
+			err := validate.Struct(newLink)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if target task is missing", func(t *testing.T) {
+			newLink.Type = "blocks"
+			newLink.TargetTaskID = uuid.Nil
+			err := validate.Struct(newLink)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Set recurrence validation", func(t *testing.T) {
+		var recurrence = validation.SetRecurrence{
+			RRule: "FREQ=WEEKLY;BYDAY=MO",
+		}
+
+		t.Run("should correctly validate a valid rule", func(t *testing.T) {
+			err := validate.Struct(recurrence)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if rule is invalid", func(t *testing.T) {
+			recurrence.RRule = "FREQ=SOMETIMES"
+			err := validate.Struct(recurrence)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Time entry validation", func(t *testing.T) {
+		var entry = validation.CreateTimeEntry{
+			StartedAt: time.Now().Add(-2 * time.Hour),
+			Duration:  90,
+		}
+
+		t.Run("should correctly validate a valid entry", func(t *testing.T) {
+			err := validate.Struct(entry)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if duration exceeds a day", func(t *testing.T) {
+			entry.Duration = 24*60 + 1
+			err := validate.Struct(entry)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if edit note is missing", func(t *testing.T) {
+			duration := 60
+			err := validate.Struct(validation.UpdateTimeEntry{Duration: &duration})
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Move task validation", func(t *testing.T) {
+		sectionID, neighbourID := uuid.New(), uuid.New()
+
+		t.Run("should correctly validate a move into a section", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{SectionID: &sectionID, AfterTaskID: &neighbourID})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if target section is missing", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{AfterTaskID: &neighbourID})
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if both sections are set", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{SectionID: &sectionID, UserSectionID: &sectionID})
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if both neighbours are set", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{
+				SectionID: &sectionID, AfterTaskID: &neighbourID, BeforeTaskID: &neighbourID,
+			})
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Bulk task validation", func(t *testing.T) {
+		var bulk = validation.BulkTask{
+			TaskIDs:   []uuid.UUID{uuid.New(), uuid.New()},
+			Operation: "set_status",
+			Status:    "done",
+		}
+
+		t.Run("should correctly validate a valid operation", func(t *testing.T) {
+			err := validate.Struct(bulk)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if operation argument is missing", func(t *testing.T) {
+			bulk.Operation = "reassign"
+			err := validate.Struct(bulk)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if operation is unknown", func(t *testing.T) {
+			bulk.Operation = "archive"
+			err := validate.Struct(bulk)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if there are no tasks", func(t *testing.T) {
+			bulk.Operation = "delete"
+			bulk.TaskIDs = nil
+			err := validate.Struct(bulk)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Instantiate task template validation", func(t *testing.T) {
+		t.Run("should correctly validate a valid target", func(t *testing.T) {
+			err := validate.Struct(validation.InstantiateTaskTemplate{ProjectID: uuid.New(), SectionID: uuid.New()})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if section is missing", func(t *testing.T) {
+			err := validate.Struct(validation.InstantiateTaskTemplate{ProjectID: uuid.New()})
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Create label validation", func(t *testing.T) {
+		var label = validation.CreateLabel{
+			Name:  "bug",
+			Color: "#e53935",
+		}
+
+		t.Run("should correctly validate a valid label", func(t *testing.T) {
+			err := validate.Struct(label)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if color is not hex", func(t *testing.T) {
+			label.Color = "red"
+			err := validate.Struct(label)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if name is empty", func(t *testing.T) {
+			label.Color = ""
+			label.Name = ""
+			err := validate.Struct(label)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Create custom field validation", func(t *testing.T) {
+		var field = validation.CreateCustomField{
+			Name:    "Severity",
+			Type:    "select",
+			Options: []string{"minor", "major", "show stopper"},
+		}
+
+		t.Run("should correctly validate a valid select field", func(t *testing.T) {
+			err := validate.Struct(field)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if option contains a comma", func(t *testing.T) {
+			field.Options = []string{"minor, major"}
+			err := validate.Struct(field)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if options are duplicated", func(t *testing.T) {
+			field.Options = []string{"minor", "minor"}
+			err := validate.Struct(field)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if select field has no options", func(t *testing.T) {
+			field.Options = nil
+			err := validate.Struct(field)
+			assert.Error(t, err)
+		})
+
+		t.Run("should correctly validate a field without options", func(t *testing.T) {
+			field.Type = "number"
+			err := validate.Struct(field)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if type is unknown", func(t *testing.T) {
+			field.Type = "color"
+			err := validate.Struct(field)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Custom field values", func(t *testing.T) {
+		t.Run("should store empty values as an empty object", func(t *testing.T) {
+			var values model.CustomFieldValues
+			value, err := values.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, "{}", value)
+		})
+
+		t.Run("should read values back from jsonb", func(t *testing.T) {
+			var values model.CustomFieldValues
+			err := values.Scan([]byte(`{"a":1.5,"b":["x"],"c":true}`))
+			assert.NoError(t, err)
+			assert.Equal(t, 1.5, values["a"])
+			assert.Equal(t, []interface{}{"x"}, values["b"])
+			assert.Equal(t, true, values["c"])
+		})
+	})
+
+	t.Run("Query task history validation", func(t *testing.T) {
+		var query = validation.QueryTaskHistory{
+			Limit: 50,
+		}
+
+		t.Run("should correctly validate a valid query", func(t *testing.T) {
+			err := validate.Struct(query)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if limit is too big", func(t *testing.T) {
+			query.Limit = 101
+			err := validate.Struct(query)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if cursor is not base64url", func(t *testing.T) {
+			query.Limit = 50
+			query.Cursor = "not a cursor"
+			err := validate.Struct(query)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Task in trash", func(t *testing.T) {
+		t.Run("should not expose deletion fields in JSON", func(t *testing.T) {
+			userID := uuid.New()
+			task := model.Task{Title: "Deleted", DeletedBy: &userID}
+			task.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			data, err := json.Marshal(task)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(data), "deleted_at")
+			assert.NotContains(t, string(data), "deleted_by")
+		})
+	})
+
+	t.Run("Create comment validation", func(t *testing.T) {
+		replyTo := uuid.New()
+		var comment = validation.CreateComment{
+			Body:      "Looks good",
+			TaskID:    uuid.New(),
+			ReplyToID: &replyTo,
+		}
+
+		t.Run("should correctly validate a valid reply", func(t *testing.T) {
+			err := validate.Struct(comment)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if body is empty", func(t *testing.T) {
+			comment.Body = ""
+			err := validate.Struct(comment)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if body is too long", func(t *testing.T) {
+			comment.Body = strings.Repeat("a", 10001)
+			err := validate.Struct(comment)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Comment reaction validation", func(t *testing.T) {
+		t.Run("should correctly validate emoji", func(t *testing.T) {
+			for _, emoji := range []string{"👍", "❤️", "👍🏽", "👩‍💻", "🇷🇺", "1️⃣"} {
+				err := validate.Struct(validation.CommentReaction{Emoji: emoji})
+				assert.NoError(t, err, emoji)
+			}
+		})
+
+		t.Run("should throw a validation error if reaction is text", func(t *testing.T) {
+			for _, emoji := range []string{"+1", "ok", "👍 ok", "1"} {
+				err := validate.Struct(validation.CommentReaction{Emoji: emoji})
+				assert.Error(t, err, emoji)
+			}
+		})
+
+		t.Run("should throw a validation error if emoji is empty", func(t *testing.T) {
+			err := validate.Struct(validation.CommentReaction{})
+			assert.Error(t, err)
+		})
+	})
+
+
+	t.Run("Section WIP limit validation", func(t *testing.T) {
+		t.Run("should correctly validate a limit", func(t *testing.T) {
+			err := validate.Struct(validation.SectionWIPLimit{WIPLimit: 5, Strict: true})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should allow removing the limit", func(t *testing.T) {
+			err := validate.Struct(validation.SectionWIPLimit{})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if limit is negative", func(t *testing.T) {
+			err := validate.Struct(validation.SectionWIPLimit{WIPLimit: -1})
+			assert.Error(t, err)
+		})
+	})
+
+
+	t.Run("Board swimlane validation", func(t *testing.T) {
+		sectionID := uuid.New()
+		laneKey := ""
+
+		t.Run("should correctly validate a move across lanes", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{SectionID: &sectionID, Swimlane: "assignee", LaneKey: &laneKey})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if lane key is missing", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{SectionID: &sectionID, Swimlane: "priority"})
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if custom field is missing", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{SectionID: &sectionID, Swimlane: "custom_field", LaneKey: &laneKey})
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error for lanes in user sections", func(t *testing.T) {
+			err := validate.Struct(validation.MoveTask{UserSectionID: &sectionID, Swimlane: "assignee", LaneKey: &laneKey})
+			assert.Error(t, err)
+		})
+
+		t.Run("should correctly validate board query", func(t *testing.T) {
+			assert.NoError(t, validate.Struct(validation.QueryBoard{}))
+			assert.NoError(t, validate.Struct(validation.QueryBoard{Swimlane: "label"}))
+			assert.NoError(t, validate.Struct(validation.QueryBoard{Swimlane: "custom_field", FieldID: uuid.NewString()}))
+		})
+
+		t.Run("should throw a validation error for unknown swimlane", func(t *testing.T) {
+			assert.Error(t, validate.Struct(validation.QueryBoard{Swimlane: "status"}))
+			assert.Error(t, validate.Struct(validation.QueryBoard{Swimlane: "custom_field"}))
+		})
+	})
+
+
+	t.Run("Calendar feed JSON", func(t *testing.T) {
+		t.Run("should hide the feed token", func(t *testing.T) {
+			feed := model.CalendarFeed{Token: "secret-token", URL: "http://localhost/v1/calendar/secret-token.ics"}
+			data, err := json.Marshal(feed)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(data), `"token"`)
+			assert.Contains(t, string(data), `"url"`)
+		})
+	})
+
+
+	t.Run("Task todo validation", func(t *testing.T) {
+		t.Run("should correctly validate todo from CalDAV", func(t *testing.T) {
+			assert.NoError(t, validate.Struct(validation.UpdateTaskTodo{Title: "Release", Completed: true}))
+		})
+
+		t.Run("should throw a validation error for empty or long summary", func(t *testing.T) {
+			assert.Error(t, validate.Struct(validation.UpdateTaskTodo{}))
+			assert.Error(t, validate.Struct(validation.UpdateTaskTodo{Title: strings.Repeat("a", 51)}))
+		})
+	})
+
+
+	t.Run("Task import validation", func(t *testing.T) {
+		t.Run("should correctly validate import without mapping or with known fields", func(t *testing.T) {
+			assert.NoError(t, validate.Struct(validation.ImportTasks{DryRun: true}))
+			assert.NoError(t, validate.Struct(validation.ImportTasks{
+				Mapping: map[string]string{"title": "Name", "assignee_email": "Owner"},
+			}))
+		})
+
+		t.Run("should throw a validation error for unknown field or long column name", func(t *testing.T) {
+			assert.Error(t, validate.Struct(validation.ImportTasks{Mapping: map[string]string{"owner": "Owner"}}))
+			assert.Error(t, validate.Struct(validation.ImportTasks{
+				Mapping: map[string]string{"title": strings.Repeat("a", 101)},
+			}))
+		})
+	})
+}
@@ -91,6 +91,40 @@ func TestUserModel(t *testing.T) {
 			err := validate.Struct(updateUser)
 			assert.Error(t, err)
 		})
+
+		t.Run("should throw a validation error if role is unknown", func(t *testing.T) {
+			updateUser.Password = "password1"
+			updateUser.Role = "superuser"
+			err := validate.Struct(updateUser)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Query audit log validation", func(t *testing.T) {
+		var query = validation.QueryAuditLog{
+			Action: "auth.login_failed,user.role_changed",
+			IP:     "192.168.0.1",
+			From:   "2024-10-01",
+			Format: "ndjson",
+		}
+
+		t.Run("should correctly validate a valid query", func(t *testing.T) {
+			err := validate.Struct(query)
+			assert.NoError(t, err)
+		})
+
+		t.Run("should throw a validation error if ip is invalid", func(t *testing.T) {
+			query.IP = "localhost"
+			err := validate.Struct(query)
+			assert.Error(t, err)
+		})
+
+		t.Run("should throw a validation error if format is unknown", func(t *testing.T) {
+			query.IP = ""
+			query.Format = "csv"
+			err := validate.Struct(query)
+			assert.Error(t, err)
+		})
 	})
 
 	t.Run("Update user password validation", func(t *testing.T) {
@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"app/src/storage"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStorage проверяет общий контракт Storage для любой реализации
+func testStorage(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	key := "tasks/test/file.txt"
+	content := "hello, attachment"
+
+	t.Run("should put and get object", func(t *testing.T) {
+		err := s.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain")
+		require.NoError(t, err)
+
+		body, err := s.Get(ctx, key)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+
+	t.Run("should return ErrNotFound for missing object", func(t *testing.T) {
+		_, err := s.Get(ctx, "tasks/test/missing")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("should delete object and ignore missing ones", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, key))
+		_, err := s.Get(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		assert.NoError(t, s.Delete(ctx, key))
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	testStorage(t, s)
+
+	t.Run("should reject keys escaping the root", func(t *testing.T) {
+		err := s.Put(context.Background(), "../outside", strings.NewReader("x"), 1, "text/plain")
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail on size mismatch", func(t *testing.T) {
+		err := s.Put(context.Background(), "tasks/test/short", strings.NewReader("abc"), 10, "text/plain")
+		assert.Error(t, err)
+		_, err = s.Get(context.Background(), "tasks/test/short")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+// TestS3Storage запускается против MinIO из docker-compose:
+// S3_TEST_ENDPOINT=localhost:9000 go test ./test/unit/storage/...
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+
+	s, err := storage.NewS3Storage(context.Background(), storage.S3Options{
+		Endpoint:  endpoint,
+		Region:    "us-east-1",
+		Bucket:    "attachments-test",
+		AccessKey: "minioadmin",
+		SecretKey: "minioadmin",
+	})
+	require.NoError(t, err)
+
+	testStorage(t, s)
+}
@@ -0,0 +1,44 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("should decode an encoded cursor", func(t *testing.T) {
+		id := uuid.New()
+		encoded, err := utils.EncodeCursor("in_progress", id)
+		assert.NoError(t, err)
+
+		cursor, err := utils.DecodeCursor(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, "in_progress", cursor.Value)
+		assert.Equal(t, id, cursor.ID)
+	})
+
+	t.Run("should keep null sort values", func(t *testing.T) {
+		encoded, err := utils.EncodeCursor(nil, uuid.New())
+		assert.NoError(t, err)
+
+		cursor, err := utils.DecodeCursor(encoded)
+		assert.NoError(t, err)
+		assert.Nil(t, cursor.Value)
+	})
+
+	t.Run("should reject a malformed cursor", func(t *testing.T) {
+		_, err := utils.DecodeCursor("not a cursor")
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject a cursor without id", func(t *testing.T) {
+		encoded, err := utils.EncodeCursor(1, uuid.Nil)
+		assert.NoError(t, err)
+
+		_, err = utils.DecodeCursor(encoded)
+		assert.Error(t, err)
+	})
+}
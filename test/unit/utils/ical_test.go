@@ -0,0 +1,102 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestICalWriter(t *testing.T) {
+	t.Run("should escape text values", func(t *testing.T) {
+		assert.Equal(t, `a\, b\; c\\d\ne`, utils.ICalEscape("a, b; c\\d\ne"))
+	})
+
+	t.Run("should end lines with CRLF", func(t *testing.T) {
+		var w utils.ICalWriter
+		w.Line("BEGIN", "VCALENDAR")
+		w.Text("SUMMARY", "Release, v2")
+		assert.Equal(t, "BEGIN:VCALENDAR\r\nSUMMARY:Release\\, v2\r\n", string(w.Bytes()))
+	})
+
+	t.Run("should fold long lines without breaking characters", func(t *testing.T) {
+		var w utils.ICalWriter
+		w.Text("SUMMARY", strings.Repeat("задача ", 30))
+
+		lines := strings.Split(strings.TrimSuffix(string(w.Bytes()), "\r\n"), "\r\n")
+		assert.Greater(t, len(lines), 1)
+		var unfolded strings.Builder
+		for i, line := range lines {
+			assert.LessOrEqual(t, len(line), 75)
+			assert.True(t, utf8.ValidString(line))
+			if i > 0 {
+				assert.True(t, strings.HasPrefix(line, " "))
+				line = line[1:]
+			}
+			unfolded.WriteString(line)
+		}
+		assert.Equal(t, "SUMMARY:"+strings.Repeat("задача ", 30), unfolded.String())
+	})
+
+	t.Run("should format times in UTC", func(t *testing.T) {
+		at := time.Date(2024, 10, 7, 12, 30, 0, 0, time.FixedZone("UTC+3", 3*3600))
+		assert.Equal(t, "20241007T093000Z", utils.ICalTime(at))
+		assert.Equal(t, "20241007", utils.ICalDate(at))
+	})
+}
+
+func TestParseICal(t *testing.T) {
+	t.Run("should read a VTODO with folded and escaped values", func(t *testing.T) {
+		var w utils.ICalWriter
+		w.Line("BEGIN", "VCALENDAR")
+		w.Line("BEGIN", "VTODO")
+		w.Text("SUMMARY", strings.Repeat("задача, ", 20))
+		w.Line("DUE;TZID=Europe/Moscow", "20241007T120000")
+		w.Line("BEGIN", "VALARM")
+		w.Line("ACTION", "DISPLAY")
+		w.Line("END", "VALARM")
+		w.Line("END", "VTODO")
+		w.Line("END", "VCALENDAR")
+
+		calendar, err := utils.ParseICal(w.Bytes())
+		assert.NoError(t, err)
+		todo := calendar.Component("VTODO")
+		if assert.NotNil(t, todo) {
+			assert.Equal(t, strings.Repeat("задача, ", 20), utils.ICalUnescape(todo.Property("SUMMARY").Value))
+			assert.Nil(t, todo.Property("ACTION"))
+			assert.NotNil(t, calendar.Component("VALARM"))
+
+			due, allDay, err := utils.ParseICalTime(todo.Property("DUE"))
+			assert.NoError(t, err)
+			assert.False(t, allDay)
+			assert.Equal(t, time.Date(2024, 10, 7, 9, 0, 0, 0, time.UTC), due)
+		}
+	})
+
+	t.Run("should keep colons inside quoted parameters", func(t *testing.T) {
+		calendar, err := utils.ParseICal([]byte("BEGIN:VTODO\r\nATTENDEE;CN=\"Doe: John\":mailto:john@example.com\r\nEND:VTODO\r\n"))
+		assert.NoError(t, err)
+		attendee := calendar.Property("ATTENDEE")
+		if assert.NotNil(t, attendee) {
+			assert.Equal(t, "Doe: John", attendee.Params["CN"])
+			assert.Equal(t, "mailto:john@example.com", attendee.Value)
+		}
+	})
+
+	t.Run("should read dates as midnight UTC", func(t *testing.T) {
+		due, allDay, err := utils.ParseICalTime(&utils.ICalProperty{Params: map[string]string{"VALUE": "DATE"}, Value: "20241007"})
+		assert.NoError(t, err)
+		assert.True(t, allDay)
+		assert.Equal(t, time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC), due)
+	})
+
+	t.Run("should reject unbalanced components", func(t *testing.T) {
+		_, err := utils.ParseICal([]byte("BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n"))
+		assert.ErrorIs(t, err, utils.ErrInvalidICal)
+		_, err = utils.ParseICal([]byte("SUMMARY:no component\r\n"))
+		assert.ErrorIs(t, err, utils.ErrInvalidICal)
+	})
+}
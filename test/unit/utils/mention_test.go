@@ -0,0 +1,35 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	t.Run("should find mentions by name and email", func(t *testing.T) {
+		handles := utils.ParseMentions("@Alice please sync with @bob.smith and @carol@example.com")
+		assert.Equal(t, []string{"alice", "bob.smith", "carol@example.com"}, handles)
+	})
+
+	t.Run("should strip trailing punctuation", func(t *testing.T) {
+		handles := utils.ParseMentions("Thanks, @alice. Ask @bob-")
+		assert.Equal(t, []string{"alice", "bob"}, handles)
+	})
+
+	t.Run("should skip duplicates", func(t *testing.T) {
+		handles := utils.ParseMentions("@alice @ALICE (@alice)")
+		assert.Equal(t, []string{"alice"}, handles)
+	})
+
+	t.Run("should ignore plain email addresses", func(t *testing.T) {
+		handles := utils.ParseMentions("Write to bob@example.com")
+		assert.Empty(t, handles)
+	})
+
+	t.Run("should support non-latin names", func(t *testing.T) {
+		handles := utils.ParseMentions("@Иван, посмотри")
+		assert.Equal(t, []string{"иван"}, handles)
+	})
+}
@@ -0,0 +1,32 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchMIME(t *testing.T) {
+	allowed := []string{"image/*", "application/pdf", " text/plain "}
+
+	t.Run("should match exact types ignoring parameters", func(t *testing.T) {
+		assert.True(t, utils.MatchMIME("application/pdf", allowed))
+		assert.True(t, utils.MatchMIME("text/plain; charset=utf-8", allowed))
+	})
+
+	t.Run("should match wildcard groups", func(t *testing.T) {
+		assert.True(t, utils.MatchMIME("image/png", allowed))
+		assert.True(t, utils.MatchMIME("IMAGE/JPEG", allowed))
+	})
+
+	t.Run("should reject other types", func(t *testing.T) {
+		assert.False(t, utils.MatchMIME("application/x-msdownload", allowed))
+		assert.False(t, utils.MatchMIME("text/html", allowed))
+		assert.False(t, utils.MatchMIME("image", allowed))
+	})
+
+	t.Run("should allow everything with */*", func(t *testing.T) {
+		assert.True(t, utils.MatchMIME("application/octet-stream", []string{"*/*"}))
+	})
+}
@@ -0,0 +1,60 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRank(t *testing.T) {
+	t.Run("should return a key between neighbours", func(t *testing.T) {
+		cases := [][2]string{{"", ""}, {"", "1"}, {"", "0i"}, {"i", ""}, {"iz", "j"}, {"i5", "i5z"}, {"a", "b"}}
+		for _, c := range cases {
+			key, err := utils.RankBetween(c[0], c[1])
+			assert.NoError(t, err)
+			assert.Less(t, c[0], key)
+			if c[1] != "" {
+				assert.Less(t, key, c[1])
+			}
+		}
+	})
+
+	t.Run("should keep order after many insertions", func(t *testing.T) {
+		random := rand.New(rand.NewSource(1))
+		keys := []string{}
+		for i := 0; i < 500; i++ {
+			pos := random.Intn(len(keys) + 1)
+			prev, next := "", ""
+			if pos > 0 {
+				prev = keys[pos-1]
+			}
+			if pos < len(keys) {
+				next = keys[pos]
+			}
+			key, err := utils.RankBetween(prev, next)
+			assert.NoError(t, err)
+			keys = append(keys[:pos], append([]string{key}, keys[pos:]...)...)
+		}
+		assert.True(t, sort.StringsAreSorted(keys))
+	})
+
+	t.Run("should reject keys out of order", func(t *testing.T) {
+		_, err := utils.RankBetween("b", "a")
+		assert.Error(t, err)
+
+		_, err = utils.RankBetween("a", "a")
+		assert.Error(t, err)
+	})
+
+	t.Run("should generate distinct increasing keys", func(t *testing.T) {
+		keys := utils.RankSequence(100)
+		assert.Len(t, keys, 100)
+		assert.True(t, sort.StringsAreSorted(keys))
+		for i := 1; i < len(keys); i++ {
+			assert.NotEqual(t, keys[i-1], keys[i])
+		}
+	})
+}
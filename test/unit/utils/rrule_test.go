@@ -0,0 +1,61 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRRule(t *testing.T) {
+	// Понедельник
+	dtstart := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should return next weekly occurrence", func(t *testing.T) {
+		next, err := utils.NextOccurrence("FREQ=WEEKLY;BYDAY=MO,WE", dtstart, nil, dtstart)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.January, 3, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("should skip excluded dates", func(t *testing.T) {
+		exdates := []time.Time{time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)}
+		next, err := utils.NextOccurrence("FREQ=DAILY", dtstart, exdates, dtstart)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.January, 3, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("should return zero time when series is over", func(t *testing.T) {
+		next, err := utils.NextOccurrence("FREQ=DAILY;COUNT=2", dtstart, nil, dtstart.AddDate(0, 0, 1))
+		assert.NoError(t, err)
+		assert.True(t, next.IsZero())
+	})
+
+	t.Run("should list upcoming occurrences", func(t *testing.T) {
+		occurrences, err := utils.Occurrences("FREQ=MONTHLY;BYMONTHDAY=-1", dtstart, nil, dtstart, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC),
+		}, occurrences)
+	})
+
+	t.Run("should find occurrence on a day", func(t *testing.T) {
+		occurrence, found, err := utils.OccurrenceOn("FREQ=WEEKLY;BYDAY=MO", dtstart, nil,
+			time.Date(2024, time.January, 8, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, time.Date(2024, time.January, 8, 9, 0, 0, 0, time.UTC), occurrence)
+
+		_, found, err = utils.OccurrenceOn("FREQ=WEEKLY;BYDAY=MO", dtstart, nil,
+			time.Date(2024, time.January, 9, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should reject invalid rule", func(t *testing.T) {
+		_, err := utils.NextOccurrence("FREQ=SOMETIMES", dtstart, nil, dtstart)
+		assert.Error(t, err)
+	})
+}
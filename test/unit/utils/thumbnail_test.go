@@ -0,0 +1,64 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Run("should keep aspect ratio when downscaling", func(t *testing.T) {
+		img, format, err := utils.DecodeImage(encodePNG(t, 400, 100))
+		require.NoError(t, err)
+		assert.Equal(t, "png", format)
+
+		thumb := utils.ResizeToFit(img, 64)
+		assert.Equal(t, 64, thumb.Bounds().Dx())
+		assert.Equal(t, 16, thumb.Bounds().Dy())
+	})
+
+	t.Run("should not upscale small images", func(t *testing.T) {
+		img, _, err := utils.DecodeImage(encodePNG(t, 20, 30))
+		require.NoError(t, err)
+
+		thumb := utils.ResizeToFit(img, 256)
+		assert.Equal(t, image.Rect(0, 0, 20, 30), thumb.Bounds())
+	})
+
+	t.Run("should reject images with huge dimensions", func(t *testing.T) {
+		_, _, err := utils.DecodeImage(encodePNG(t, 10000, 6000))
+		assert.ErrorIs(t, err, utils.ErrImageTooLarge)
+	})
+
+	t.Run("should reject non-image data", func(t *testing.T) {
+		_, _, err := utils.DecodeImage([]byte("%PDF-1.4 not an image"))
+		assert.Error(t, err)
+	})
+
+	t.Run("should keep transparency formats in PNG", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		var buf bytes.Buffer
+		contentType, err := utils.EncodeThumbnail(&buf, img, "png")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", contentType)
+
+		buf.Reset()
+		contentType, err = utils.EncodeThumbnail(&buf, img, "jpeg")
+		require.NoError(t, err)
+		assert.Equal(t, "image/jpeg", contentType)
+	})
+}
@@ -0,0 +1,65 @@
+package utils_test
+
+import (
+	"app/src/utils"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebDAV(t *testing.T) {
+	t.Run("should treat an empty PROPFIND as allprop", func(t *testing.T) {
+		req, err := utils.ParseDAVRequest(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "propfind", req.Kind)
+		assert.True(t, req.AllProp)
+	})
+
+	t.Run("should read requested properties and hrefs of a multiget", func(t *testing.T) {
+		req, err := utils.ParseDAVRequest([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data><c:comp name="VCALENDAR"/></c:calendar-data></d:prop>
+  <d:href>/v1/caldav/projects/p/1.ics</d:href>
+  <d:href>/v1/caldav/projects/p/2.ics</d:href>
+</c:calendar-multiget>`))
+		assert.NoError(t, err)
+		assert.Equal(t, "calendar-multiget", req.Kind)
+		assert.False(t, req.AllProp)
+		assert.Equal(t, []xml.Name{
+			{Space: utils.DAVNamespace, Local: "getetag"},
+			{Space: utils.CalDAVNamespace, Local: "calendar-data"},
+		}, req.Props)
+		assert.Equal(t, []string{"/v1/caldav/projects/p/1.ics", "/v1/caldav/projects/p/2.ics"}, req.Hrefs)
+	})
+
+	t.Run("should reject malformed XML", func(t *testing.T) {
+		_, err := utils.ParseDAVRequest([]byte("<d:propfind xmlns:d=\"DAV:\"><d:prop>"))
+		assert.ErrorIs(t, err, utils.ErrInvalidDAVRequest)
+	})
+
+	t.Run("should split found and missing properties", func(t *testing.T) {
+		ms := utils.NewDAVMultistatus()
+		ms.Response("/a & b/", []utils.DAVProp{
+			{Name: xml.Name{Space: utils.DAVNamespace, Local: "getetag"}, Value: utils.DAVText(`"1"`)},
+		}, []xml.Name{{Space: "urn:x", Local: "foo"}})
+		ms.Status("/gone.ics", 404)
+
+		body := string(ms.Bytes())
+		assert.Contains(t, body, "<D:href>/a &amp; b/</D:href>")
+		assert.Contains(t, body, "<D:getetag>&#34;1&#34;</D:getetag></D:prop><D:status>HTTP/1.1 200 OK</D:status>")
+		assert.Contains(t, body, `<foo xmlns="urn:x"/></D:prop><D:status>HTTP/1.1 404 Not Found</D:status>`)
+		assert.Contains(t, body, "<D:href>/gone.ics</D:href><D:status>HTTP/1.1 404 Not Found</D:status>")
+		assert.True(t, strings.HasSuffix(body, "</D:multistatus>"))
+
+		// Ответ должен быть корректным XML
+		decoder := xml.NewDecoder(strings.NewReader(body))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				assert.Equal(t, "EOF", err.Error())
+				break
+			}
+		}
+	})
+}